@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/config"
+	"github.com/elasticpath/epcc-cli/external/assertions"
+	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/httpclient"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/elasticpath/epcc-cli/external/rest"
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func NewAssertCommand(parentCmd *cobra.Command) func() {
+	overrides := &httpclient.HttpParameterOverrides{
+		QueryParameters: nil,
+		OverrideUrlPath: "",
+	}
+
+	// Ensure that any new options here are added to the resetFunc
+	var jqPredicates []string
+	var exprPredicates []string
+	var fromCapture = ""
+
+	resetFunc := func() {
+		overrides.QueryParameters = nil
+		overrides.OverrideUrlPath = ""
+		jqPredicates = nil
+		exprPredicates = nil
+		fromCapture = ""
+	}
+
+	assertPredicates := func(document string) error {
+		if len(jqPredicates) == 0 && len(exprPredicates) == 0 {
+			return fmt.Errorf("please specify what to assert, using --jq or --expr")
+		}
+
+		var failed []string
+
+		check := func(predicate string, err error) {
+			if err == nil {
+				log.Infof("Assertion passed: %s", predicate)
+				return
+			}
+
+			// The expected and actual values are printed as is, as they can span multiple lines
+			fmt.Fprintln(os.Stderr, err)
+			failed = append(failed, predicate)
+		}
+
+		for _, predicate := range jqPredicates {
+			check(predicate, assertions.Jq(predicate, document))
+		}
+
+		for _, predicate := range exprPredicates {
+			check(predicate, assertions.Expr(predicate, document))
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d assertions failed: %s", len(failed), len(jqPredicates)+len(exprPredicates), strings.Join(failed, "; "))
+		}
+
+		return nil
+	}
+
+	var assertCmd = &cobra.Command{
+		Use:   "assert",
+		Short: "Checks that predicates are true for a resource, or a value captured earlier in a runbook",
+		Long: `Checks that predicates are true for a resource (retrieved the same way as epcc get), or a value captured earlier in a runbook (--from-capture).
+
+Predicates are jq expressions (--jq) or expr expressions (https://expr-lang.org/) with the keys of the response as variables (--expr), and each can be given more than once.
+A captured value that isn't an object (e.g., a string) is the variable value in expr expressions.
+When a predicate compares two values (e.g., actual == expected) and fails, the expected and actual values (or the differences between them) are shown.
+`,
+		Example: `  epcc assert customer name=Ron_Swanson --jq '.data.email == "ron@swanson.example"'
+  epcc assert pcm-products --expr 'len(data) > 0'
+
+  # In a runbook, after capturing a cart with --capture cart='.'
+  epcc assert --from-capture cart --jq '.data.meta.display_price.discount.amount == -500'`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromCapture != "" {
+				value, ok := runbooks.CapturedValueFromContext(cmd.Context(), fromCapture)
+
+				if !ok {
+					return fmt.Errorf("there is no value captured as %s, --from-capture can only be used in a runbook after the value is captured", fromCapture)
+				}
+
+				return assertPredicates(value)
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("please specify a resource or --from-capture, epcc assert [RESOURCE], see epcc assert --help")
+			} else {
+				return fmt.Errorf("invalid resource [%s] specified, see all with epcc assert --help", args[0])
+			}
+		},
+	}
+
+	e := config.GetEnv()
+	hiddenResources := map[string]struct{}{}
+	for _, v := range e.EPCC_CLI_DISABLE_RESOURCES {
+		hiddenResources[v] = struct{}{}
+	}
+
+	for _, resource := range resources.GetPluralResources() {
+		if _, ok := hiddenResources[resource.SingularName]; ok {
+			log.Tracef("Hiding resource %s", resource.SingularName)
+			continue
+		}
+
+		if _, ok := hiddenResources[resource.PluralName]; ok {
+			log.Tracef("Hiding resource %s", resource.SingularName)
+			continue
+		}
+
+		resource := resource
+
+		for _, urlInfo := range []*resources.CrudEntityInfo{resource.GetCollectionInfo, resource.GetEntityInfo} {
+			if urlInfo == nil {
+				continue
+			}
+
+			resourceName := resource.PluralName
+			completionVerb := completion.GetAll
+			if urlInfo == resource.GetEntityInfo {
+				resourceName = resource.SingularName
+				completionVerb = completion.Get
+			}
+
+			resourceUrl := urlInfo.Url
+
+			singularTypeNames, err := resources.GetSingularTypesOfVariablesNeeded(resourceUrl)
+			if err != nil {
+				log.Warnf("Could not generate usage string for %s, error %v", resourceName, err)
+			}
+
+			var assertResourceCommand = &cobra.Command{
+				Use:   resourceName + GetParametersForTypes(singularTypeNames),
+				Short: fmt.Sprintf("Checks that predicates are true for the response of GET %s", GetHelpResourceUrls(resourceUrl)),
+				Args:  GetArgFunctionForUrl(resourceName, resourceUrl),
+				RunE: func(cmd *cobra.Command, args []string) error {
+					body, err := rest.GetInternal(commandContext(cmd), overrides, append([]string{resourceName}, args...), false, false)
+
+					if err != nil {
+						return err
+					} else if !gojson.Valid([]byte(body)) {
+						return fmt.Errorf("could not retrieve %s: %s", resourceName, body)
+					}
+
+					return assertPredicates(body)
+				},
+				ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+					if len(args) < len(singularTypeNames) {
+						types, err := resources.GetTypesOfVariablesNeeded(resourceUrl)
+
+						if err != nil {
+							return []string{}, cobra.ShellCompDirectiveNoFileComp
+						}
+
+						if completionResource, ok := resources.GetResourceByName(types[len(args)]); ok {
+							return completion.Complete(completion.Request{
+								Type:     completion.CompleteAlias,
+								Resource: completionResource,
+							})
+						}
+					} else if (len(args)-len(singularTypeNames))%2 == 0 {
+						return completion.Complete(completion.Request{
+							Type:     completion.CompleteQueryParamKey,
+							Resource: resource,
+							Verb:     completionVerb,
+						})
+					}
+
+					return []string{}, cobra.ShellCompDirectiveNoFileComp
+				},
+			}
+
+			assertCmd.AddCommand(assertResourceCommand)
+		}
+	}
+
+	assertCmd.PersistentFlags().StringArrayVarP(&jqPredicates, "jq", "", []string{}, "A jq expression that must be true (e.g., '.data.name == \"Ron\"'), can be given more than once")
+	assertCmd.PersistentFlags().StringArrayVarP(&exprPredicates, "expr", "", []string{}, "An expr expression that must be true (e.g., 'data.name == \"Ron\"'), can be given more than once")
+	assertCmd.PersistentFlags().StringVarP(&fromCapture, "from-capture", "", "", "In a runbook, check a value captured earlier (e.g., with --capture cart='.') instead of retrieving a resource")
+	assertCmd.PersistentFlags().StringSliceVarP(&overrides.QueryParameters, "query-parameters", "q", []string{}, "Pass in key=value an they will be added as query parameters")
+
+	parentCmd.AddCommand(assertCmd)
+
+	return resetFunc
+}
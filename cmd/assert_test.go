@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertFromCaptureChecksTheCapturedValue(t *testing.T) {
+	// Fixture Setup
+	rootCmd := &cobra.Command{Use: "epcc", SilenceErrors: true}
+	NewAssertCommand(rootCmd)
+
+	ctx := runbooks.WithCapturedValues(context.Background(), map[string]string{"cart": `{"data":{"meta":{"display_price":{"discount":{"amount":-400}}}}}`})
+	rootCmd.SetArgs([]string{"assert", "--from-capture", "cart", "--jq", ".data.meta.display_price.discount.amount < 0", "--expr", "data.meta.display_price.discount.amount == -500"})
+
+	// Execute SUT
+	err := rootCmd.ExecuteContext(ctx)
+
+	// Verification
+	require.EqualError(t, err, "1 of 2 assertions failed: data.meta.display_price.discount.amount == -500")
+}
+
+func TestAssertFromCaptureOutsideOfARunbookFails(t *testing.T) {
+	// Fixture Setup
+	rootCmd := &cobra.Command{Use: "epcc", SilenceErrors: true}
+	NewAssertCommand(rootCmd)
+
+	rootCmd.SetArgs([]string{"assert", "--from-capture", "cart", "--jq", "true"})
+
+	// Execute SUT
+	err := rootCmd.ExecuteContext(context.Background())
+
+	// Verification
+	require.ErrorContains(t, err, "there is no value captured as cart")
+}
+
+func TestAssertFromCaptureChecksACapturedString(t *testing.T) {
+	// Fixture Setup
+	rootCmd := &cobra.Command{Use: "epcc", SilenceErrors: true}
+	NewAssertCommand(rootCmd)
+
+	c := &runbooks.CommandCaptures{Captures: []runbooks.Capture{{Variable: "email", Query: ".data.email"}}}
+	require.NoError(t, c.CaptureFromBody(`{"data":{"email":"a@b.com"}}`))
+
+	ctx := runbooks.WithCapturedValues(context.Background(), c.Values())
+	rootCmd.SetArgs([]string{"assert", "--from-capture", "email", "--jq", `. == "a@b.com"`, "--expr", `value endsWith "@b.com"`})
+
+	// Execute SUT
+	err := rootCmd.ExecuteContext(ctx)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestAssertFromCaptureChecksACapturedStringThatLooksLikeANumber(t *testing.T) {
+	// Fixture Setup
+	rootCmd := &cobra.Command{Use: "epcc", SilenceErrors: true}
+	NewAssertCommand(rootCmd)
+
+	c := &runbooks.CommandCaptures{Captures: []runbooks.Capture{{Variable: "code", Query: ".data.code"}}}
+	require.NoError(t, c.CaptureFromBody(`{"data":{"code":"12345"}}`))
+
+	ctx := runbooks.WithCapturedValues(context.Background(), c.Values())
+	rootCmd.SetArgs([]string{"assert", "--from-capture", "code", "--jq", `. == "12345"`})
+
+	// Execute SUT
+	err := rootCmd.ExecuteContext(ctx)
+
+	// Verification
+	require.NoError(t, err)
+}
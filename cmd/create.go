@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/elasticpath/epcc-cli/config"
-	"github.com/elasticpath/epcc-cli/external/clictx"
 
 	"github.com/elasticpath/epcc-cli/external/aliases"
 	"github.com/elasticpath/epcc-cli/external/completion"
@@ -60,7 +59,10 @@ func NewCreateCommand(parentCmd *cobra.Command) func() {
 	var logOnSuccess = ""
 	var logOnFailure = ""
 	var disableConstants = false
+	var noValidate = false
 	var data = ""
+	var responseFormat = ResponseFormatJson
+	var columns []string
 
 	resetFunc := func() {
 		autoFillOnCreate = false
@@ -80,7 +82,10 @@ func NewCreateCommand(parentCmd *cobra.Command) func() {
 		logOnSuccess = ""
 		logOnFailure = ""
 		disableConstants = false
+		noValidate = false
 		data = ""
+		responseFormat = ResponseFormatJson
+		columns = nil
 	}
 
 	e := config.GetEnv()
@@ -136,12 +141,16 @@ func NewCreateCommand(parentCmd *cobra.Command) func() {
 						}
 					}
 
-					body, err := rest.CreateInternal(clictx.Ctx, overrides, append([]string{resourceName}, args...), autoFillOnCreate, setAlias, skipAliases, disableConstants, data)
+					body, err := rest.CreateInternal(commandContext(cmd), overrides, append([]string{resourceName}, args...), autoFillOnCreate, setAlias, skipAliases, disableConstants, noValidate, data)
 
 					if err != nil {
 						return err
 					}
 
+					if err := captureOutput(cmd, body); err != nil {
+						return err
+					}
+
 					if outputJq != "" {
 						output, err := json.RunJQOnStringWithArray(outputJq, body)
 
@@ -167,6 +176,8 @@ func NewCreateCommand(parentCmd *cobra.Command) func() {
 
 					if noBodyPrint {
 						return nil
+					} else if ok, err := printBodyInResponseFormat(body, responseFormat, columns, resource); ok {
+						return err
 					} else if outputKeyValue {
 						return json.PrintJsonAsKeyValue(body)
 					} else {
@@ -286,12 +297,15 @@ func NewCreateCommand(parentCmd *cobra.Command) func() {
 	createCmd.PersistentFlags().Uint32VarP(&repeatDelay, "repeat-delay", "", 100, "Delay (in ms) between repeats")
 
 	createCmd.PersistentFlags().BoolVarP(&disableConstants, "no-auto-constants", "", false, "Disable setting of known constant values in the request body")
+	createCmd.PersistentFlags().BoolVarP(&noValidate, "no-validate", "", false, "Don't check arguments against the attribute types and OpenAPI request schema before sending the request")
 	createCmd.PersistentFlags().StringVarP(&logOnSuccess, "log-on-success", "", "", "Output the following message as an info if the result is successful")
 	createCmd.PersistentFlags().StringVarP(&logOnFailure, "log-on-failure", "", "", "Output the following message as an error if the result fails")
 	createCmd.PersistentFlags().StringVarP(&data, "data", "d", "", "Raw JSON data to use as the request body. If provided, positional arguments will be ignored.")
 
 	createCmd.MarkFlagsMutuallyExclusive("output-key-val", "output-jq", "silent", "compact")
-	_ = createCmd.RegisterFlagCompletionFunc("output-jq", jqCompletionFunc)
+	addResponseFormatFlags(createCmd, &responseFormat, &columns)
+	createCmd.MarkFlagsMutuallyExclusive("output", "output-jq")
+	_ = createCmd.RegisterFlagCompletionFunc("output-jq", getJqCompletionFunc(completion.Create))
 
 	return resetFunc
 }
@@ -13,7 +13,6 @@ import (
 	"github.com/elasticpath/epcc-cli/config"
 	"github.com/elasticpath/epcc-cli/external/aliases"
 	"github.com/elasticpath/epcc-cli/external/apihelper"
-	"github.com/elasticpath/epcc-cli/external/clictx"
 	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/id"
 	"github.com/elasticpath/epcc-cli/external/json"
@@ -86,7 +85,7 @@ func NewDeleteAllCommand(parentCmd *cobra.Command) func() {
 			Short:  GetDeleteAllShort(resource),
 			Hidden: false,
 			RunE: func(cmd *cobra.Command, args []string) error {
-				return deleteAllInternal(clictx.Ctx, pageLength, append([]string{resourceName}, args...))
+				return deleteAllInternal(commandContext(cmd), pageLength, append([]string{resourceName}, args...))
 			},
 		}
 		deleteAll.AddCommand(deleteAllResourceCmd)
@@ -5,7 +5,6 @@ import (
 
 	"github.com/elasticpath/epcc-cli/config"
 	"github.com/elasticpath/epcc-cli/external/aliases"
-	"github.com/elasticpath/epcc-cli/external/clictx"
 	"github.com/elasticpath/epcc-cli/external/completion"
 	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/json"
@@ -53,6 +52,8 @@ func NewDeleteCommand(parentCmd *cobra.Command) func() {
 	var outputKeyVal = false
 	var logOnSuccess = ""
 	var logOnFailure = ""
+	var responseFormat = ResponseFormatJson
+	var columns []string
 
 	resetFunc := func() {
 		overrides.QueryParameters = nil
@@ -67,6 +68,8 @@ func NewDeleteCommand(parentCmd *cobra.Command) func() {
 		ignoreErrors = false
 		logOnSuccess = ""
 		logOnFailure = ""
+		responseFormat = ResponseFormatJson
+		columns = nil
 	}
 
 	e := config.GetEnv()
@@ -121,7 +124,7 @@ func NewDeleteCommand(parentCmd *cobra.Command) func() {
 						}
 					}
 
-					body, err := rest.DeleteInternal(clictx.Ctx, overrides, allow404, append([]string{resourceName}, args...))
+					body, err := rest.DeleteInternal(commandContext(cmd), overrides, allow404, append([]string{resourceName}, args...))
 
 					if err != nil {
 						if body != "" {
@@ -132,8 +135,14 @@ func NewDeleteCommand(parentCmd *cobra.Command) func() {
 						return err
 					}
 
+					if err := captureOutput(cmd, body); err != nil {
+						return err
+					}
+
 					if noBodyPrint {
 						return nil
+					} else if ok, err := printBodyInResponseFormat(body, responseFormat, columns, resource); ok {
+						return err
 					} else if outputKeyVal {
 						return json.PrintJsonAsKeyValue(body)
 					} else {
@@ -238,6 +247,7 @@ func NewDeleteCommand(parentCmd *cobra.Command) func() {
 	parentCmd.AddCommand(deleteCmd)
 
 	deleteCmd.MarkFlagsMutuallyExclusive("output-key-val", "silent")
+	addResponseFormatFlags(deleteCmd, &responseFormat, &columns)
 
 	return resetFunc
 }
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/config"
+	"github.com/elasticpath/epcc-cli/external/aliases"
+	"github.com/elasticpath/epcc-cli/external/clictx"
+	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/headergroups"
+	"github.com/elasticpath/epcc-cli/external/httpclient"
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/elasticpath/epcc-cli/external/profiles"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/elasticpath/epcc-cli/external/rest"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrDifferencesFound is returned by diff when the entities are not the same
+var ErrDifferencesFound = errors.New("differences found")
+
+// Values that differ between stores (or every time an entity is recreated) and that aren't interesting to compare
+var defaultDiffIgnoredPointers = []string{
+	"/data/id",
+	"/data/links",
+	"/data/meta/timestamps",
+	"/data/relationships/*/data/id",
+	"/data/relationships/*/data/*/id",
+	"/links",
+}
+
+func NewDiffCommand(parentCmd *cobra.Command) func() {
+
+	var diffCmd = &cobra.Command{
+		Use:          "diff",
+		Short:        "Compares a resource with one in another profile, another resource or a file",
+		SilenceUsage: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("please specify a resource, epcc diff [RESOURCE], see epcc diff --help")
+			} else {
+				return fmt.Errorf("invalid resource [%s] specified, see all with epcc diff --help", args[0])
+			}
+		},
+	}
+
+	overrides := &httpclient.HttpParameterOverrides{
+		QueryParameters: nil,
+		OverrideUrlPath: "",
+	}
+
+	// Ensure that any new options here are added to the resetFunc
+	var againstProfile = ""
+	var againstFile = ""
+	var againstIds []string
+	var ignoredPointers = defaultDiffIgnoredPointers
+
+	resetFunc := func() {
+		overrides.QueryParameters = nil
+		overrides.OverrideUrlPath = ""
+		againstProfile = ""
+		againstFile = ""
+		againstIds = nil
+		ignoredPointers = defaultDiffIgnoredPointers
+	}
+
+	e := config.GetEnv()
+	hiddenResources := map[string]struct{}{}
+	for _, v := range e.EPCC_CLI_DISABLE_RESOURCES {
+		hiddenResources[v] = struct{}{}
+	}
+
+	for _, resource := range resources.GetPluralResources() {
+		if resource.GetEntityInfo == nil {
+			continue
+		}
+
+		if _, ok := hiddenResources[resource.SingularName]; ok {
+			log.Tracef("Hiding resource %s", resource.SingularName)
+			continue
+		}
+
+		if _, ok := hiddenResources[resource.PluralName]; ok {
+			log.Tracef("Hiding resource %s", resource.SingularName)
+			continue
+		}
+
+		resource := resource
+		resourceName := resource.SingularName
+
+		singularTypeNames, err := resources.GetSingularTypesOfVariablesNeeded(resource.GetEntityInfo.Url)
+		if err != nil {
+			log.Warnf("Could not generate usage string for %s, error %v", resourceName, err)
+		}
+
+		exampleIds := strings.TrimSpace(GetArgumentExampleWithAlias(singularTypeNames))
+
+		var diffResourceCommand = &cobra.Command{
+			Use:   resourceName + GetParametersForTypes(singularTypeNames),
+			Short: fmt.Sprintf("Compares a %s with one in another profile, another %s or a file", resourceName, resourceName),
+			Long: fmt.Sprintf(`Compares a %s with one in another profile (--against-profile), another %s (--against-ids) or a file saved with epcc get (--against-file).
+
+Values only in the other %s are shown with -, values only in this one with + and values that have changed with ~.
+
+The exit code is 0 if there are no differences and 5 if there are.
+`, resourceName, resourceName, resourceName),
+			Example: fmt.Sprintf(`  epcc diff %s %s --against-profile prod
+  epcc get %s %s > before.json && epcc diff %s %s --against-file before.json`,
+				resourceName, exampleIds, resourceName, exampleIds, resourceName, exampleIds),
+			Args:          GetArgFunctionForUrl(resourceName, resource.GetEntityInfo.Url),
+			SilenceUsage:  true,
+			SilenceErrors: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if againstProfile == "" && againstFile == "" && len(againstIds) == 0 {
+					return fmt.Errorf("please specify what to compare with, using --against-profile, --against-ids or --against-file")
+				}
+
+				body, err := rest.GetInternal(clictx.Ctx, overrides, append([]string{resourceName}, args...), false, false)
+				if err != nil {
+					return err
+				} else if !gojson.Valid([]byte(body)) {
+					return fmt.Errorf("could not retrieve %s: %s", resourceName, body)
+				}
+
+				var againstBody string
+
+				if againstFile != "" {
+					againstBody, err = readDiffFile(againstFile)
+				} else {
+					ids := args
+					if len(againstIds) > 0 {
+						ids = againstIds
+					}
+
+					if againstProfile != "" {
+						againstBody, err = getInternalInProfile(againstProfile, overrides, append([]string{resourceName}, ids...))
+					} else {
+						againstBody, err = rest.GetInternal(clictx.Ctx, overrides, append([]string{resourceName}, ids...), false, false)
+					}
+				}
+
+				if err != nil {
+					return err
+				} else if !gojson.Valid([]byte(againstBody)) {
+					return fmt.Errorf("could not retrieve other %s: %s", resourceName, againstBody)
+				}
+
+				diffs, err := json.DiffJson(againstBody, body, ignoredPointers)
+				if err != nil {
+					return err
+				}
+
+				if len(diffs) == 0 {
+					fmt.Fprintf(os.Stderr, "No differences\n")
+					return nil
+				}
+
+				if err := json.PrintJsonDiff(diffs); err != nil {
+					return err
+				}
+
+				return ErrDifferencesFound
+			},
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				if len(args) < len(singularTypeNames) {
+					types, err := resources.GetTypesOfVariablesNeeded(resource.GetEntityInfo.Url)
+
+					if err != nil {
+						return []string{}, cobra.ShellCompDirectiveNoFileComp
+					}
+
+					if completionResource, ok := resources.GetResourceByName(types[len(args)]); ok {
+						return completion.Complete(completion.Request{
+							Type:     completion.CompleteAlias,
+							Resource: completionResource,
+						})
+					}
+				}
+
+				return []string{}, cobra.ShellCompDirectiveNoFileComp
+			},
+		}
+
+		diffCmd.AddCommand(diffResourceCommand)
+	}
+
+	diffCmd.PersistentFlags().StringVarP(&againstProfile, "against-profile", "", "", "The profile to retrieve the other resource from")
+	diffCmd.PersistentFlags().StringVarP(&againstFile, "against-file", "", "", "A JSON or YAML file to compare with, either the output of epcc get or just the data")
+	diffCmd.PersistentFlags().StringSliceVarP(&againstIds, "against-ids", "", []string{}, "The ids (or aliases) of the other resource, by default the same ids are used")
+	diffCmd.PersistentFlags().StringSliceVarP(&ignoredPointers, "ignore", "", defaultDiffIgnoredPointers, "JSON pointers of values to ignore, a * matches any key or index, use --ignore \"\" to compare everything")
+	diffCmd.PersistentFlags().StringSliceVarP(&overrides.QueryParameters, "query-parameters", "q", []string{}, "Pass in key=value an they will be added as query parameters")
+	diffCmd.MarkFlagsMutuallyExclusive("against-file", "against-profile")
+	diffCmd.MarkFlagsMutuallyExclusive("against-file", "against-ids")
+	_ = diffCmd.MarkPersistentFlagFilename("against-file", "json", "yaml", "yml")
+
+	parentCmd.AddCommand(diffCmd)
+
+	return resetFunc
+}
+
+// getInternalInProfile retrieves a resource from another profile, aliases and header groups are saved and reloaded when the profile changes,
+// so that the aliases in args are resolved in the other profile.
+func getInternalInProfile(profile string, overrides *httpclient.HttpParameterOverrides, args []string) (string, error) {
+	if !profiles.ProfileExists(profile) {
+		return "", fmt.Errorf("could not find profile %s, see epcc configure", profile)
+	}
+
+	previousProfile := profiles.GetProfileName()
+	previousEnv := config.GetEnv()
+
+	useProfile := func(name string, e *config.Env) {
+		aliases.FlushAliases()
+		headergroups.FlushHeaderGroups()
+		profiles.SetProfileName(name)
+		config.SetEnv(e)
+	}
+
+	log.Debugf("Switching to profile %s", profile)
+	useProfile(profile, profiles.GetProfile(profile))
+	defer useProfile(previousProfile, previousEnv)
+
+	return rest.GetInternal(clictx.Ctx, overrides, args, false, false)
+}
+
+// readDiffFile reads a JSON or YAML file and returns it as JSON, if the file doesn't have a data key (i.e., it's just the entity),
+// it is wrapped in one.
+func readDiffFile(name string) (string, error) {
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %w", name, err)
+	}
+
+	var v interface{}
+
+	// JSON is also valid YAML
+	if err := yaml.Unmarshal(contents, &v); err != nil {
+		return "", fmt.Errorf("could not parse %s: %w", name, err)
+	}
+
+	if m, ok := v.(map[string]interface{}); ok {
+		if _, ok := m["data"]; !ok {
+			v = map[string]interface{}{"data": m}
+		}
+	}
+
+	result, err := gojson.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("could not convert %s to JSON: %w", name, err)
+	}
+
+	return string(result), nil
+}
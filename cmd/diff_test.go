@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/aliases"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCompletionReturnsAliases(t *testing.T) {
+
+	// Fixture Setup
+	err := aliases.ClearAllAliases()
+
+	require.NoError(t, err)
+
+	aliases.SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "123",
+		"type": "account",
+		"name": "John"
+	}
+}`)
+
+	rootCmd := &cobra.Command{}
+	NewDiffCommand(rootCmd)
+	diffCmd := getCommandForResource(rootCmd.Commands()[0], "account")
+
+	require.NotNil(t, diffCmd, "Diff command for account should exist")
+
+	// Execute SUT
+	completionResult, _ := diffCmd.ValidArgsFunction(diffCmd, []string{}, "")
+
+	// Verify
+	require.Contains(t, completionResult, "name=John")
+}
+
+func TestReadDiffFileWrapsYamlEntityInData(t *testing.T) {
+	// Fixture Setup
+	file := filepath.Join(t.TempDir(), "account.yaml")
+	err := os.WriteFile(file, []byte("type: account\nname: John\nlegal_name: John Inc\n"), 0600)
+	require.NoError(t, err)
+
+	// Execute SUT
+	result, err := readDiffFile(file)
+
+	// Verification
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data": {"type": "account", "name": "John", "legal_name": "John Inc"}}`, result)
+}
+
+func TestReadDiffFileKeepsJsonResponse(t *testing.T) {
+	// Fixture Setup
+	file := filepath.Join(t.TempDir(), "account.json")
+	err := os.WriteFile(file, []byte(`{"data": {"type": "account", "name": "John"}, "links": {"self": "x"}}`), 0600)
+	require.NoError(t, err)
+
+	// Execute SUT
+	result, err := readDiffFile(file)
+
+	// Verification
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data": {"type": "account", "name": "John"}, "links": {"self": "x"}}`, result)
+}
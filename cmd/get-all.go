@@ -30,11 +30,13 @@ Algorithm Overview:
    For each parent path, we paginate through the target resource collection:
    - Fetch pages of 100 items at a time using page[limit] and page[offset]
    - Continue until we get an empty page or detect duplicate results (some endpoints don't paginate)
-   - Send each page's raw JSON to the output processor via a channel
+   - Send each page's raw JSON to the output processor via a small buffered channel, so at most a few
+     pages are in memory at once
 
 4. OUTPUT PROCESSING (runs concurrently)
    A goroutine receives pages and processes them according to the output format:
-   - jsonl/json/csv: Transform and output the data directly
+   - jsonl/json/csv: Transform and output the data directly, pages are decoded element by element and
+     written incrementally (csv spools rows to a temporary file, as the header needs every row's keys)
    - epcc-cli/epcc-cli-runbook: Generate `epcc create` commands to recreate resources
 
 5. TOPOLOGICAL SORTING (for self-referential resources)
@@ -53,6 +55,7 @@ Output Formats:
 */
 
 import (
+	"bytes"
 	"context"
 	gojson "encoding/json"
 	"fmt"
@@ -76,12 +79,13 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/thediveo/enumflag"
-	"github.com/yukithm/json2csv"
-	"github.com/yukithm/json2csv/jsonpointer"
 )
 
 type OutputFormat enumflag.Flag
 
+// The number of pages that can be waiting to be written while get-all fetches the next ones
+const getAllPageBufferSize = 4
+
 const (
 	Jsonl OutputFormat = iota
 	Json
@@ -449,7 +453,8 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 		txt []byte
 		id  []idableAttributesWithType
 	}
-	var sendChannel = make(chan msg, 0)
+	// Pages are buffered so that fetching can get ahead of writing, but not so far that every page is in memory
+	var sendChannel = make(chan msg, getAllPageBufferSize)
 
 	var writer io.Writer
 	if outputFile == "" {
@@ -480,7 +485,32 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 	outputWriter := func() {
 		defer syncGroup.Done()
 
-		csvLines := make([]interface{}, 0)
+		// Json and Csv output are written as we go, rather than building the whole result in memory
+		var jsonArrayWriter *json.JsonArrayWriter
+		var csvWriter *json.CsvWriter
+
+		if outputFormat == Json {
+			jsonArrayWriter = json.NewJsonArrayWriter(writer)
+		} else if outputFormat == Csv {
+			csvWriter, err = json.NewCsvWriter(writer)
+
+			if err != nil {
+				log.Errorf("Error writing CSV: %v", err)
+				return
+			}
+		}
+
+		writeObject := func(obj interface{}) {
+			if jsonArrayWriter != nil {
+				if err := jsonArrayWriter.Write(obj); err != nil {
+					log.Errorf("Error writing JSON: %v", err)
+				}
+			} else if csvWriter != nil {
+				if err := csvWriter.Write(obj); err != nil {
+					log.Errorf("Error writing CSV: %v", err)
+				}
+			}
+		}
 
 		if outputFormat == EpccCliRunbook && !topoSortNeeded {
 			// We need to prefix
@@ -499,18 +529,26 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 					log.Debugf("Channel closed, we are done.")
 					break endMessages
 				}
-				var obj interface{}
-				err = gojson.Unmarshal(result.txt, &obj)
+				newObjs := make([]interface{}, 0)
+				// The JSON of each object, so that the epcc-cli formats can output it exactly (e.g., numbers)
+				newObjsJson := make([]string, 0)
+				_, err = json.StreamJsonArrays(bytes.NewReader(result.txt), func(key string, element gojson.RawMessage) error {
+					if key != "data" {
+						return nil
+					}
 
-				if err != nil {
-					log.Errorf("Couldn't unmarshal JSON response %s due to error: %v", result, err)
-					continue
-				}
+					var newObj interface{}
+					if err := gojson.Unmarshal(element, &newObj); err != nil {
+						return err
+					}
 
-				newObjs, err := json.RunJQWithArray(".data[]", obj)
+					newObjs = append(newObjs, newObj)
+					newObjsJson = append(newObjsJson, string(element))
+					return nil
+				})
 
 				if err != nil {
-					log.Errorf("Couldn't process response %s due to error: %v", result, err)
+					log.Errorf("Couldn't process response %s due to error: %v", result.txt, err)
 					continue
 				}
 
@@ -557,92 +595,69 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 					}
 
 					// Process each item with its index
-					for itemIdx, newObj := range newObjs {
-						kvs, err := json2csv.JSON2CSV(newObj)
+					for itemIdx := range newObjs {
+						kv, err := json.FromJsonObjectToMap(newObjsJson[itemIdx])
 						if err != nil {
 							log.Errorf("Error generating Key/Value pairs for item %d: %v", itemIdx, err)
 							continue
 						}
 
-						for _, kv := range kvs {
-							keys := kv.Keys()
-							sort.Strings(keys)
+						keys := make([]string, 0, len(kv))
+						for k := range kv {
+							keys = append(keys, k)
+						}
+						sort.Strings(keys)
 
-						nextKeyArray:
-							for _, k := range keys {
-								v := kv[k]
+					nextKeyArray:
+						for _, jsonPointerKey := range keys {
+							v := kv[jsonPointerKey]
 
-								jp, err := jsonpointer.New(k)
-								if err != nil {
-									log.Errorf("Couldn't generate JSON Pointer for %s: %v", k, err)
-									continue
-								}
-
-								jsonPointerKey := jp.DotNotation(true)
+							if strings.HasPrefix(jsonPointerKey, "meta.") {
+								continue
+							}
+							if strings.HasPrefix(jsonPointerKey, "links.") {
+								continue
+							}
 
-								if strings.HasPrefix(jsonPointerKey, "meta.") {
-									continue
-								}
-								if strings.HasPrefix(jsonPointerKey, "links.") {
-									continue
+							// Skip timestamps and other read-only fields
+							excludedPrefixes := []string{"created_at", "updated_at", "timestamps."}
+							for _, prefix := range excludedPrefixes {
+								if strings.HasPrefix(jsonPointerKey, prefix) {
+									continue nextKeyArray
 								}
+							}
 
-								// Skip timestamps and other read-only fields
-								excludedPrefixes := []string{"created_at", "updated_at", "timestamps."}
-								for _, prefix := range excludedPrefixes {
-									if strings.HasPrefix(jsonPointerKey, prefix) {
-										continue nextKeyArray
-									}
+							// Skip resource-specific excluded JSON pointers
+							for _, excluded := range resource.ExcludedJsonPointersFromImport {
+								if strings.HasPrefix(jsonPointerKey, excluded) {
+									continue nextKeyArray
 								}
+							}
 
-								// Skip resource-specific excluded JSON pointers
-								for _, excluded := range resource.ExcludedJsonPointersFromImport {
-									if strings.HasPrefix(jsonPointerKey, excluded) {
-										continue nextKeyArray
-									}
-								}
+							sb.WriteString(" ")
+							// Use incrementing index for each item
+							sb.WriteString(fmt.Sprintf("data[%d].", itemIdx))
+							sb.WriteString(jsonPointerKey)
+							sb.WriteString(" ")
 
-								sb.WriteString(" ")
-								// Use incrementing index for each item
-								sb.WriteString(fmt.Sprintf("data[%d].", itemIdx))
-								sb.WriteString(jsonPointerKey)
-								sb.WriteString(" ")
-
-								// Check if this attribute is a RESOURCE_ID type by looking up the attribute definition
-								// Convert jsonPointerKey to the generic attribute key format (e.g., "id" -> "data[n].id")
-								attrKey := "data[n]." + jsonPointerKey
-								isResourceId := false
-								if attr, ok := resource.Attributes[attrKey]; ok {
-									if strings.HasPrefix(attr.Type, "RESOURCE_ID:") {
-										isResourceId = true
-									}
+							// Check if this attribute is a RESOURCE_ID type by looking up the attribute definition
+							// Convert jsonPointerKey to the generic attribute key format (e.g., "id" -> "data[n].id")
+							attrKey := "data[n]." + jsonPointerKey
+							isResourceId := false
+							if attr, ok := resource.Attributes[attrKey]; ok {
+								if strings.HasPrefix(attr.Type, "RESOURCE_ID:") {
+									isResourceId = true
 								}
+							}
 
-								if s, ok := v.(string); ok {
-									if isResourceId {
-										// Use alias reference format for RESOURCE_ID attributes
-										sb.WriteString(`"`)
-										sb.WriteString("exported_source_id=")
-										sb.WriteString(s)
-										sb.WriteString(`"`)
-									} else {
-										sb.WriteString(`"`)
-										quoteArgument := json.ValueNeedsQuotes(s)
-										if quoteArgument {
-											sb.WriteString("\\\"")
-										}
-										value := strings.ReplaceAll(s, `\`, `\\`)
-										value = strings.ReplaceAll(value, `$`, `\$`)
-										value = strings.ReplaceAll(value, `"`, `\"`)
-										sb.WriteString(value)
-										if quoteArgument {
-											sb.WriteString("\\\"")
-										}
-										sb.WriteString(`"`)
-									}
-								} else {
-									sb.WriteString(fmt.Sprintf("%v", v))
-								}
+							if s, ok := jsonStringValue(v); ok && isResourceId {
+								// Use alias reference format for RESOURCE_ID attributes
+								sb.WriteString(`"`)
+								sb.WriteString("exported_source_id=")
+								sb.WriteString(s)
+								sb.WriteString(`"`)
+							} else {
+								writeShellQuotedValue(sb, v)
 							}
 						}
 					}
@@ -675,13 +690,13 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 								log.Errorf("Error writing JSON line: %v", err)
 							}
 						} else if outputFormat == Json || outputFormat == Csv {
-							csvLines = append(csvLines, wrappedObj)
+							writeObject(wrappedObj)
 						}
 					}
 					continue // Skip the per-item processing below
 				}
 
-				for _, newObj := range newObjs {
+				for newObjIdx, newObj := range newObjs {
 
 					wrappedObj := map[string]interface{}{
 						"data": newObj,
@@ -698,7 +713,7 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 							continue
 						}
 					} else if outputFormat == Json || outputFormat == Csv {
-						csvLines = append(csvLines, wrappedObj)
+						writeObject(wrappedObj)
 					} else if outputFormat == EpccCli || outputFormat == EpccCliRunbook {
 						sb := &strings.Builder{}
 
@@ -740,120 +755,86 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 
 						}
 
-						kvs, err := json2csv.JSON2CSV(newObj)
+						kv, err := json.FromJsonObjectToMap(newObjsJson[newObjIdx])
 						if err != nil {
 							log.Errorf("Error generating Key/Value pairs: %v", err)
 							sb.WriteString("\n")
 							continue
 						}
 
-						for _, kv := range kvs {
-
-							keys := kv.Keys()
-
-							sort.Strings(keys)
-
-						nextKey:
-							for _, k := range keys {
-								v := kv[k]
+						keys := make([]string, 0, len(kv))
+						for k := range kv {
+							keys = append(keys, k)
+						}
 
-								jp, err := jsonpointer.New(k)
+						sort.Strings(keys)
 
-								if err != nil {
-									log.Errorf("Couldn't generate JSON Pointer for %s: %v", k, err)
+					nextKey:
+						for _, jsonPointerKey := range keys {
+							v := kv[jsonPointerKey]
 
-									continue
-								}
+							if strings.HasPrefix(jsonPointerKey, "meta.") {
+								continue
+							}
 
-								jsonPointerKey := jp.DotNotation(true)
+							if strings.HasPrefix(jsonPointerKey, "links.") {
+								continue
+							}
 
-								if strings.HasPrefix(jsonPointerKey, "meta.") {
+							// Skip id fields (id, data.id, data[n].id) unless no-wrapping (where data.id is needed for relationships)
+							if !resource.NoWrapping {
+								if jsonPointerKey == "id" || strings.HasPrefix(jsonPointerKey, "data.id") ||
+									strings.HasPrefix(jsonPointerKey, "data[") && strings.HasSuffix(jsonPointerKey, "].id") {
 									continue
 								}
+							}
 
-								if strings.HasPrefix(jsonPointerKey, "links.") {
-									continue
-								}
+							// Skip type field unless no-wrapping (where data.type is needed)
+							if jsonPointerKey == "type" && !resource.NoWrapping {
+								continue
+							}
 
-								// Skip id fields (id, data.id, data[n].id) unless no-wrapping (where data.id is needed for relationships)
-								if !resource.NoWrapping {
-									if jsonPointerKey == "id" || strings.HasPrefix(jsonPointerKey, "data.id") ||
-										strings.HasPrefix(jsonPointerKey, "data[") && strings.HasSuffix(jsonPointerKey, "].id") {
-										continue
-									}
+							// Skip timestamps and other read-only fields
+							excludedPrefixes := []string{"created_at", "updated_at", "timestamps."}
+							for _, prefix := range excludedPrefixes {
+								if strings.HasPrefix(jsonPointerKey, prefix) {
+									continue nextKey
 								}
+							}
 
-								// Skip type field unless no-wrapping (where data.type is needed)
-								if jsonPointerKey == "type" && !resource.NoWrapping {
-									continue
+							// Skip resource-specific excluded JSON pointers
+							for _, excluded := range resource.ExcludedJsonPointersFromImport {
+								if strings.HasPrefix(jsonPointerKey, excluded) {
+									continue nextKey
 								}
+							}
 
-								// Skip timestamps and other read-only fields
-								excludedPrefixes := []string{"created_at", "updated_at", "timestamps."}
-								for _, prefix := range excludedPrefixes {
-									if strings.HasPrefix(jsonPointerKey, prefix) {
-										continue nextKey
-									}
-								}
+							sb.WriteString(" ")
+							// For no-wrapping resources, we need to prefix keys with "data."
+							// (array notation is handled separately above)
+							if resource.NoWrapping {
+								sb.WriteString("data.")
+							}
+							sb.WriteString(jsonPointerKey)
+							sb.WriteString(" ")
 
-								// Skip resource-specific excluded JSON pointers
-								for _, excluded := range resource.ExcludedJsonPointersFromImport {
-									if strings.HasPrefix(jsonPointerKey, excluded) {
-										continue nextKey
-									}
-								}
+							writeValueFromJson := true
 
-								sb.WriteString(" ")
-								// For no-wrapping resources, we need to prefix keys with "data."
-								// (array notation is handled separately above)
-								if resource.NoWrapping {
-									sb.WriteString("data.")
-								}
-								sb.WriteString(jsonPointerKey)
-								sb.WriteString(" ")
-
-								if s, ok := v.(string); ok {
-
-									writeValueFromJson := true
-
-									for _, topoKey := range topoSortKeys {
-										if jsonPointerKey == topoKey {
-											dependentId := fmt.Sprintf("%s", v)
-											graph.AddEdge(dependentId, myId)
-											writeValueFromJson = false
-											sb.WriteString(`"`)
-											sb.WriteString("exported_source_id=")
-											sb.WriteString(dependentId)
-											sb.WriteString(`"`)
-										}
-									}
-
-									if writeValueFromJson {
-										// This is to prevent shell characters from interpreting things
+							if s, ok := jsonStringValue(v); ok {
+								for _, topoKey := range topoSortKeys {
+									if jsonPointerKey == topoKey {
+										graph.AddEdge(s, myId)
+										writeValueFromJson = false
 										sb.WriteString(`"`)
-
-										quoteArgument := json.ValueNeedsQuotes(s)
-
-										if quoteArgument {
-											// This is to force the EPCC CLI to interpret the value as a string
-											sb.WriteString("\\\"")
-										}
-										value := strings.ReplaceAll(s, `\`, `\\`)
-										value = strings.ReplaceAll(value, `$`, `\$`)
-										value = strings.ReplaceAll(value, `"`, `\"`)
-										sb.WriteString(value)
-
-										if quoteArgument {
-											// This is to force the EPCC CLI to interpret the value as a string
-											sb.WriteString("\\\"")
-										}
-										// This is to prevent shell characters from interpreting things
+										sb.WriteString("exported_source_id=")
+										sb.WriteString(s)
 										sb.WriteString(`"`)
 									}
-								} else {
-									sb.WriteString(fmt.Sprintf("%v", v))
 								}
+							}
 
+							if writeValueFromJson {
+								writeShellQuotedValue(sb, v)
 							}
 						}
 
@@ -881,28 +862,12 @@ func getAllSingleResource(ctx context.Context, outputFormat OutputFormat, output
 			}
 		}
 
-		if outputFormat == Json {
-			err = writeJson(csvLines, writer)
-
-			if err != nil {
-				log.Errorf("Error writing JSON line: %v", err)
-			}
-		} else if outputFormat == Csv {
-
-			// Create writer that saves to string
-			results, err := json2csv.JSON2CSV(csvLines)
-
-			if err != nil {
-				log.Errorf("Error converting to CSV: %v", err)
-				return
+		if jsonArrayWriter != nil {
+			if err := jsonArrayWriter.Close(); err != nil {
+				log.Errorf("Error writing JSON: %v", err)
 			}
-
-			csvWriter := json2csv.NewCSVWriter(writer)
-
-			csvWriter.HeaderStyle = json2csv.DotBracketStyle
-			csvWriter.Transpose = false
-
-			if err := csvWriter.WriteCSV(results); err != nil {
+		} else if csvWriter != nil {
+			if err := csvWriter.Close(); err != nil {
 				log.Errorf("Error writing CSV: %v", err)
 				return
 			}
@@ -1074,3 +1039,25 @@ func getParentIdsForGetAll(ctx context.Context, resource resources.Resource) ([]
 		return apihelper.GetAllIds(ctx, defaultPageLength, &parentResource)
 	}
 }
+
+// jsonStringValue returns the string, if a value from json.FromJsonObjectToMap is a JSON string
+func jsonStringValue(v string) (string, bool) {
+	var s string
+	if err := gojson.Unmarshal([]byte(v), &s); err != nil {
+		return "", false
+	}
+
+	return s, true
+}
+
+// writeShellQuotedValue writes a value from json.FromJsonObjectToMap in double quotes, so that the shell doesn't interpret it
+func writeShellQuotedValue(sb *strings.Builder, v string) {
+	value := strings.ReplaceAll(v, `\`, `\\`)
+	value = strings.ReplaceAll(value, `$`, `\$`)
+	value = strings.ReplaceAll(value, "`", "\\`")
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	sb.WriteString(`"`)
+	sb.WriteString(value)
+	sb.WriteString(`"`)
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/elasticpath/epcc-cli/config"
 	"github.com/elasticpath/epcc-cli/external/aliases"
-	"github.com/elasticpath/epcc-cli/external/clictx"
 	"github.com/elasticpath/epcc-cli/external/completion"
 	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/json"
@@ -42,6 +41,8 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 	var ignoreErrors = false
 	var logOnSuccess = ""
 	var logOnFailure = ""
+	var responseFormat = ResponseFormatJson
+	var columns []string
 
 	resetFunc := func() {
 		overrides.QueryParameters = nil
@@ -61,6 +62,8 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 		ignoreErrors = false
 		logOnSuccess = ""
 		logOnFailure = ""
+		responseFormat = ResponseFormatJson
+		columns = nil
 
 	}
 
@@ -173,7 +176,7 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 						retriesFailedError := fmt.Errorf("Maximum number of retries hit %d and condition [%s] always true", retryWhileJQMaxAttempts, retryWhileJQ)
 
 						for attempt := uint16(0); attempt < retryWhileJQMaxAttempts; attempt++ {
-							body, err = rest.GetInternal(clictx.Ctx, overrides, append([]string{resourceName}, args...), autoFillOnGet, skipAliases)
+							body, err = rest.GetInternal(commandContext(cmd), overrides, append([]string{resourceName}, args...), autoFillOnGet, skipAliases)
 							if retryWhileJQ == "" {
 								retriesFailedError = nil
 								break
@@ -204,6 +207,10 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 							return err
 						}
 
+						if err := captureOutput(cmd, body); err != nil {
+							return err
+						}
+
 						if outputJq != "" {
 							output, err := json.RunJQOnStringWithArray(outputJq, body)
 
@@ -229,6 +236,12 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 
 						if noBodyPrint {
 							return retriesFailedError
+						} else if ok, err := printBodyInResponseFormat(body, responseFormat, columns, resource); ok {
+							if retriesFailedError != nil {
+								return retriesFailedError
+							}
+
+							return err
 						} else if outputKeyValue {
 							return json.PrintJsonAsKeyValue(body)
 						} else {
@@ -342,7 +355,10 @@ func NewGetCommand(parentCmd *cobra.Command) func() {
 	getCmd.PersistentFlags().StringVarP(&logOnFailure, "log-on-failure", "", "", "Output the following message as an error if the result fails")
 
 	getCmd.MarkFlagsMutuallyExclusive("output-key-val", "output-jq", "silent", "compact")
-	_ = getCmd.RegisterFlagCompletionFunc("output-jq", jqCompletionFunc)
+	addResponseFormatFlags(getCmd, &responseFormat, &columns)
+	getCmd.MarkFlagsMutuallyExclusive("output", "output-jq")
+	_ = getCmd.RegisterFlagCompletionFunc("output-jq", getJqCompletionFunc(completion.Get))
+	_ = getCmd.RegisterFlagCompletionFunc("retry-while-jq", getJqCompletionFunc(completion.Get))
 
 	parentCmd.AddCommand(getCmd)
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/elasticpath/epcc-cli/external/aliases"
+	"github.com/elasticpath/epcc-cli/external/completion"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
@@ -193,3 +194,30 @@ func TestGetArgFunctionForEntityUrlHasNoErrorWithArgs(t *testing.T) {
 	// Verification
 	require.NoError(t, err)
 }
+
+func TestGetOutputJqCompletionUsesResponseSchema(t *testing.T) {
+	// Fixture Setup
+	rootCmd := &cobra.Command{}
+	NewGetCommand(rootCmd)
+	getCmd := getCommandForResource(rootCmd.Commands()[0], "account-members")
+
+	require.NotNil(t, getCmd, "Get command for account members should exist")
+
+	// Execute SUT
+	completionResult, _ := getJqCompletionFunc(completion.Get)(getCmd, []string{}, "")
+
+	// Verification
+	require.Contains(t, completionResult, ".data[].email")
+	require.NotContains(t, completionResult, ".data.attributes.")
+}
+
+func TestGetOutputJqCompletionWithoutResourceReturnsGenericPaths(t *testing.T) {
+	// Fixture Setup
+	cmd := &cobra.Command{Use: "not-a-resource"}
+
+	// Execute SUT
+	completionResult, _ := getJqCompletionFunc(completion.Get)(cmd, []string{}, "")
+
+	// Verification
+	require.Contains(t, completionResult, ".data.attributes.")
+}
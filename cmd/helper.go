@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -11,9 +12,14 @@ import (
 	"sync"
 
 	"github.com/elasticpath/epcc-cli/external/autofill"
+	"github.com/elasticpath/epcc-cli/external/clictx"
 	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/elasticpath/epcc-cli/external/random"
 	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	"github.com/elasticpath/epcc-cli/external/shutdown"
 	"github.com/google/uuid"
 	"github.com/iancoleman/strcase"
 	log "github.com/sirupsen/logrus"
@@ -21,6 +27,31 @@ import (
 	"github.com/yosida95/uritemplate/v3"
 )
 
+// commandContext returns the context to make requests with, with the stream of random values for the command (e.g., in a runbook)
+func commandContext(cmd *cobra.Command) context.Context {
+	if shutdown.IsCleanup(cmd.Context()) {
+		// Cleanup commands (e.g., finally in a runbook) still run once the global context is cancelled on shutdown
+		return cmd.Context()
+	}
+
+	ctx := random.WithStream(clictx.Ctx, random.FromContext(cmd.Context()))
+
+	if requestStats := httpclient.RequestStatsFromContext(cmd.Context()); requestStats != nil {
+		ctx = httpclient.WithRequestStats(ctx, requestStats)
+	}
+
+	return ctx
+}
+
+// captureOutput saves values from the body of a response, when the command is in a runbook and has --capture
+func captureOutput(cmd *cobra.Command, body string) error {
+	if c := runbooks.CapturesFromContext(cmd.Context()); c != nil {
+		return c.CaptureFromBody(body)
+	}
+
+	return nil
+}
+
 var DisableLongOutput = false
 var DisableExampleOutput = false
 
@@ -871,7 +902,7 @@ func GetCreateExample(resource resources.Resource) string {
 			jsonTxt, _ := json.ToJson(extendedArgs, resource.NoWrapping, resource.JsonApiFormat == "compliant", resource.Attributes, false, true)
 			examples += GetJsonExample(fmt.Sprintf("# Create a %s passing in an argument", resourceName), fmt.Sprintf("%s %s %s", exampleWithAliases, k, arg), fmt.Sprintf("> POST %s", FillUrlWithIds(resource.CreateEntityInfo, uuids)), jsonTxt)
 
-			autofilledData := autofill.GetJsonArrayForResource(&resource)
+			autofilledData := autofill.GetJsonArrayForResource(context.Background(), &resource, extendedArgs)
 
 			extendedArgs = append(autofilledData, extendedArgs...)
 
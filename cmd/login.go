@@ -367,7 +367,8 @@ var loginCustomer = &cobra.Command{
 		newArgs = append(newArgs, "customer-token")
 		newArgs = append(newArgs, args...)
 
-		body, err := rest.CreateInternal(ctx, overrides, newArgs, false, "", false, false, "")
+		// Tokens are created without validation, like the other logins, so that a quirk in a schema doesn't stop logging in
+		body, err := rest.CreateInternal(ctx, overrides, newArgs, false, "", false, false, true, "")
 
 		if err != nil {
 			log.Warnf("Login not completed successfully")
@@ -581,7 +582,8 @@ var loginAccountManagement = &cobra.Command{
 		}
 
 		// Do the login and get back a list of accounts
-		body, err := rest.CreateInternal(ctx, overrides, append([]string{"account-management-authentication-token"}, args...), false, "", false, false, "")
+		// Not validated, like the customer token
+		body, err := rest.CreateInternal(ctx, overrides, append([]string{"account-management-authentication-token"}, args...), false, "", false, false, true, "")
 
 		if err != nil {
 			log.Warnf("Login not completed successfully")
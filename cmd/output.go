@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/spf13/cobra"
+	"github.com/thediveo/enumflag"
+)
+
+type ResponseFormat enumflag.Flag
+
+const (
+	ResponseFormatJson ResponseFormat = iota
+	ResponseFormatYaml
+	ResponseFormatTable
+)
+
+var ResponseFormatIds = map[ResponseFormat][]string{
+	ResponseFormatJson:  {"json"},
+	ResponseFormatYaml:  {"yaml"},
+	ResponseFormatTable: {"table"},
+}
+
+// responseFormatCompletionFunc provides tab completion for the --output flag
+var responseFormatCompletionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		"json\tJSON (default)",
+		"yaml\tYAML",
+		"table\tA table with a row for each object, see --columns",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Attributes that make good columns when a resource doesn't declare default columns
+var defaultColumnAttributes = []string{"name", "sku", "slug", "code", "email", "status"}
+
+// addResponseFormatFlags adds the --output and --columns flags to a command
+func addResponseFormatFlags(cmd *cobra.Command, responseFormat *ResponseFormat, columns *[]string) {
+	cmd.PersistentFlags().VarP(
+		enumflag.New(responseFormat, "output", ResponseFormatIds, enumflag.EnumCaseInsensitive),
+		"output", "",
+		"sets output format; can be 'json', 'yaml' or 'table'")
+	cmd.PersistentFlags().StringSliceVarP(columns, "columns", "", []string{}, "The columns to show with --output table, as a JSON pointer (/attributes/name) or jq path (attributes.name)")
+	_ = cmd.RegisterFlagCompletionFunc("output", responseFormatCompletionFunc)
+	cmd.MarkFlagsMutuallyExclusive("output", "output-key-val")
+	cmd.MarkFlagsMutuallyExclusive("columns", "output-key-val")
+}
+
+// printBodyInResponseFormat prints a response body as yaml or a table, if the format is json (which is handled by each command) false is returned.
+func printBodyInResponseFormat(body string, responseFormat ResponseFormat, columns []string, resource resources.Resource) (bool, error) {
+	switch responseFormat {
+	case ResponseFormatYaml:
+		return true, json.PrintJsonAsYaml(body)
+	case ResponseFormatTable:
+		if len(columns) == 0 {
+			columns = getDefaultColumns(resource)
+		}
+		return true, json.PrintJsonAsTable(body, columns)
+	default:
+		return false, nil
+	}
+}
+
+// getDefaultColumns returns the default columns for a resource, either from the resource definition or the id and well known attributes.
+func getDefaultColumns(resource resources.Resource) []string {
+	if len(resource.DefaultColumns) > 0 {
+		return resource.DefaultColumns
+	}
+
+	columns := []string{"id"}
+
+	for _, a := range defaultColumnAttributes {
+		if _, ok := resource.Attributes[a]; !ok {
+			continue
+		}
+
+		if resource.JsonApiFormat == "compliant" {
+			columns = append(columns, "attributes."+a)
+		} else {
+			columns = append(columns, a)
+		}
+	}
+
+	return columns
+}
@@ -24,7 +24,7 @@ func repeater(c func(*cobra.Command, []string) error, repeat, repeatDelay uint32
 			time.Sleep(time.Duration(repeatDelay) * time.Millisecond)
 		}
 
-		if shutdown.ShutdownFlag.Load() {
+		if shutdown.ShutdownFlag.Load() && !shutdown.IsCleanup(cmd.Context()) {
 			return nil
 		}
 	}
@@ -218,7 +218,7 @@ func resetResourcesUndeletableResources(ctx context.Context, overrides *httpclie
 	errors := make([]string, 0)
 
 	for _, resetCmd := range resetCmds {
-		body, err := rest.UpdateInternal(ctx, overrides, false, false, resetCmd, "")
+		body, err := rest.UpdateInternal(ctx, overrides, false, false, true, nil, resetCmd, "")
 
 		if err != nil {
 			errors = append(errors, fmt.Errorf("error resetting  %s: %v", resetCmd[0], err).Error())
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/elasticpath/epcc-cli/config"
+	"github.com/elasticpath/epcc-cli/external/clictx"
+	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/customapis"
+	"github.com/elasticpath/epcc-cli/external/openapi"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var resourcesCmd = &cobra.Command{
+	Use:          "resources",
+	Short:        "Manage the resource definitions used by the CLI",
+	SilenceUsage: false,
+}
+
+func NewResourcesCommand(parentCmd *cobra.Command) {
+	var outputDir = ""
+	var dryRun = false
+
+	syncCustomApis := &cobra.Command{
+		Use:   "sync-custom-apis",
+		Short: "Generates resource definitions for the Commerce Extensions custom APIs in the store",
+		Long: `Generates resource definitions for the Commerce Extensions custom APIs in the store.
+
+A resource definition is written for each custom API into the resource directory (EPCC_RESOURCE_DIRECTORY), so that entries can be
+managed directly (e.g., epcc create wishlist name foo) instead of through custom-api-extensions-entries. Re-run this command
+whenever custom APIs or their fields change, definitions that were generated for custom APIs that no longer exist are removed.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputDir == "" {
+				outputDir = config.GetEnv().EPCC_RESOURCE_DIRECTORY
+			}
+
+			if outputDir == "" && !dryRun {
+				return fmt.Errorf("no output directory, either set EPCC_RESOURCE_DIRECTORY or use --output-dir")
+			}
+
+			apis, err := customapis.GetCustomApis(clictx.Ctx)
+
+			if err != nil {
+				return err
+			}
+
+			generatedResources := customapis.GenerateResources(apis)
+
+			if !dryRun {
+				if err := os.MkdirAll(outputDir, 0700); err != nil {
+					return fmt.Errorf("could not create directory %s: %w", outputDir, err)
+				}
+			}
+
+			currentFiles := make([]string, 0, len(apis))
+
+			for _, api := range apis {
+				resourceName, _ := customapis.GetResourceNames(api.Slug)
+				resource := generatedResources[resourceName]
+
+				filename := filepath.Join(outputDir, fmt.Sprintf("custom-api-%s.yaml", resourceName))
+				currentFiles = append(currentFiles, filename)
+
+				if existing, ok := resources.GetResourceByName(resourceName); ok && filepath.Clean(existing.SourceFile) != filepath.Clean(filename) {
+					log.Warnf("Skipping custom api %s, as resource %s is already defined in %s", api.Slug, resourceName, existing.SourceFile)
+					continue
+				}
+
+				if existing, ok := resources.GetResourceByName(resource.SingularName); ok && filepath.Clean(existing.SourceFile) != filepath.Clean(filename) {
+					log.Warnf("Skipping custom api %s, as resource %s is already defined in %s", api.Slug, resource.SingularName, existing.SourceFile)
+					continue
+				}
+
+				yamlBytes, err := yaml.Marshal(map[string]resources.Resource{resourceName: resource})
+
+				if err != nil {
+					return fmt.Errorf("could not generate resource for custom api %s: %w", api.Slug, err)
+				}
+
+				contents := fmt.Sprintf("%s from custom api %s (%s), changes will be overwritten\n%s", customapis.GeneratedFileHeader, api.Slug, api.Id, yamlBytes)
+
+				if _, err := resources.LoadResourcesFromYaml(filename, []byte(contents)); err != nil {
+					log.Warnf("Skipping custom api %s, generated resource is not valid:\n%v", api.Slug, err)
+					continue
+				}
+
+				if dryRun {
+					fmt.Println(contents)
+					continue
+				}
+
+				if err := os.WriteFile(filename, []byte(contents), 0600); err != nil {
+					return fmt.Errorf("could not write resource for custom api %s: %w", api.Slug, err)
+				}
+
+				log.Infof("Wrote resource %s (%s) to %s", resourceName, resource.SingularName, filename)
+			}
+
+			if outputDir != "" {
+				staleFiles, err := customapis.FindStaleResourceFiles(outputDir, currentFiles)
+
+				if err != nil {
+					return err
+				}
+
+				for _, f := range staleFiles {
+					if dryRun {
+						fmt.Printf("# Would remove %s, as its custom api no longer exists\n", f)
+						continue
+					}
+
+					if err := os.Remove(f); err != nil {
+						return fmt.Errorf("could not remove %s: %w", f, err)
+					}
+
+					log.Infof("Removed %s, as its custom api no longer exists", f)
+				}
+			}
+
+			if !dryRun && filepath.Clean(outputDir) != filepath.Clean(config.GetEnv().EPCC_RESOURCE_DIRECTORY) {
+				log.Warnf("Resources were written to %s, set EPCC_RESOURCE_DIRECTORY to this directory to use them", outputDir)
+			}
+
+			return nil
+		},
+	}
+
+	syncCustomApis.Flags().StringVarP(&outputDir, "output-dir", "", "", "directory to write resource definitions to (defaults to EPCC_RESOURCE_DIRECTORY)")
+	syncCustomApis.Flags().BoolVarP(&dryRun, "dry-run", "", false, "print the generated resource definitions instead of writing them")
+
+	var suggestPatches = false
+	var includeUnmapped = false
+
+	audit := &cobra.Command{
+		Use:   "audit [RESOURCE]...",
+		Short: "Reports differences between resource definitions and the OpenAPI specs",
+		Long: `Reports differences between resource definitions and the embedded OpenAPI specs, for resources that have an openapi-operation-id:
+
+- attributes that are not in the request schema
+- request schema properties that are not attributes
+- ENUM: attributes whose values differ from the request schema
+- query parameters that are not in the resource
+
+If no resources are given all resources are audited, and OpenAPI operations that no resource references are also reported.
+`,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.Complete(completion.Request{
+				Type: completion.CompletePluralResource,
+			})
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourcesToAudit := map[string]resources.Resource{}
+
+			if len(args) == 0 {
+				resourcesToAudit = resources.GetPluralResources()
+				includeUnmapped = true
+			}
+
+			for _, name := range args {
+				resource, ok := resources.GetResourceByName(name)
+
+				if !ok {
+					return fmt.Errorf("could not find resource %s", name)
+				}
+
+				resourcesToAudit[resource.PluralName] = resource
+			}
+
+			findings, err := openapi.AuditResources(resourcesToAudit, includeUnmapped)
+
+			if err != nil {
+				return err
+			}
+
+			printAuditReport(findings)
+
+			if suggestPatches {
+				return printAuditPatchSuggestions(findings)
+			}
+
+			return nil
+		},
+	}
+
+	audit.Flags().BoolVarP(&suggestPatches, "suggest-patches", "", false, "print suggested changes to the resource yaml files")
+	audit.Flags().BoolVarP(&includeUnmapped, "include-unmapped", "", false, "report OpenAPI operations that no resource references (always on when auditing all resources)")
+
+	resourcesCmd.AddCommand(syncCustomApis, audit)
+	parentCmd.AddCommand(resourcesCmd)
+}
+
+func printAuditReport(findings []openapi.AuditFinding) {
+	counts := map[string]int{}
+	lastResource := ""
+	lastOperation := ""
+
+	for _, f := range findings {
+		counts[f.Kind]++
+
+		if f.Kind == openapi.AuditUnmappedOperation {
+			continue
+		}
+
+		if f.Resource != lastResource {
+			fmt.Printf("\n%s (%s)\n", f.Resource, f.SourceFile)
+			lastResource = f.Resource
+			lastOperation = ""
+		}
+
+		if f.OperationType != lastOperation {
+			fmt.Printf("  %s [%s]\n", f.OperationType, f.OperationId)
+			lastOperation = f.OperationType
+		}
+
+		fmt.Printf("    %s: %s\n", f.Kind, f.Message)
+	}
+
+	if counts[openapi.AuditUnmappedOperation] > 0 {
+		fmt.Printf("\nUnmapped OpenAPI operations\n")
+
+		for _, f := range findings {
+			if f.Kind != openapi.AuditUnmappedOperation {
+				continue
+			}
+
+			if f.Suggestion != nil {
+				fmt.Printf("  %s: %s (could be %s of %s)\n", f.OperationId, f.Message, f.OperationType, f.Resource)
+			} else {
+				fmt.Printf("  %s: %s\n", f.OperationId, f.Message)
+			}
+		}
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for k := range counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+
+	fmt.Printf("\nSummary\n")
+	if len(kinds) == 0 {
+		fmt.Printf("  No differences found\n")
+	}
+
+	for _, k := range kinds {
+		fmt.Printf("  %-26s %d\n", k, counts[k])
+	}
+}
+
+func printAuditPatchSuggestions(findings []openapi.AuditFinding) error {
+	// Source file => resource => yaml
+	patches := map[string]map[string]map[string]interface{}{}
+
+	for _, f := range findings {
+		if f.Suggestion == nil || f.Resource == "" {
+			continue
+		}
+
+		if _, ok := patches[f.SourceFile]; !ok {
+			patches[f.SourceFile] = map[string]map[string]interface{}{}
+		}
+
+		if _, ok := patches[f.SourceFile][f.Resource]; !ok {
+			patches[f.SourceFile][f.Resource] = map[string]interface{}{}
+		}
+
+		patch := patches[f.SourceFile][f.Resource]
+
+		switch f.Suggestion.Kind {
+		case openapi.SuggestAttribute:
+			if _, ok := patch["attributes"]; !ok {
+				patch["attributes"] = map[string]interface{}{}
+			}
+			patch["attributes"].(map[string]interface{})[f.Suggestion.Key] = map[string]string{"type": f.Suggestion.Value}
+		case openapi.SuggestQuery:
+			if _, ok := patch[f.OperationType]; !ok {
+				patch[f.OperationType] = map[string]interface{}{}
+			}
+			op := patch[f.OperationType].(map[string]interface{})
+			query, _ := op["query"].([]map[string]string)
+			op["query"] = append(query, map[string]string{"name": f.Suggestion.Key, "type": f.Suggestion.Value})
+		case openapi.SuggestOperationId:
+			if _, ok := patch[f.OperationType]; !ok {
+				patch[f.OperationType] = map[string]interface{}{}
+			}
+			patch[f.OperationType].(map[string]interface{})["openapi-operation-id"] = f.Suggestion.Value
+		}
+	}
+
+	files := make([]string, 0, len(patches))
+	for k := range patches {
+		files = append(files, k)
+	}
+	sort.Strings(files)
+
+	fmt.Printf("\nSuggested Patches (merge into the existing definitions)\n")
+
+	for _, file := range files {
+		fmt.Printf("\n# %s\n", file)
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(patches[file]); err != nil {
+			return err
+		}
+
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
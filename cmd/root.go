@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -13,11 +14,14 @@ import (
 	"github.com/elasticpath/epcc-cli/external/aliases"
 	"github.com/elasticpath/epcc-cli/external/authentication"
 	"github.com/elasticpath/epcc-cli/external/clictx"
+	"github.com/elasticpath/epcc-cli/external/completion"
 	"github.com/elasticpath/epcc-cli/external/headergroups"
 	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/logger"
 	"github.com/elasticpath/epcc-cli/external/misc"
+	"github.com/elasticpath/epcc-cli/external/openapi"
 	"github.com/elasticpath/epcc-cli/external/profiles"
+	"github.com/elasticpath/epcc-cli/external/random"
 	"github.com/elasticpath/epcc-cli/external/resources"
 	"github.com/elasticpath/epcc-cli/external/shutdown"
 	"github.com/elasticpath/epcc-cli/external/version"
@@ -35,6 +39,8 @@ var requestTimeout float32
 
 var statisticsFrequency uint16
 
+var seed int64
+
 var jqCompletionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return []string{
 		".data.",
@@ -62,6 +68,44 @@ var jqCompletionFunc = func(cmd *cobra.Command, args []string, toComplete string
 	}, cobra.ShellCompDirectiveNoSpace
 }
 
+// getJqCompletionFunc returns a completion function for jq flags that suggests the paths in the OpenAPI response schema of the resource, and
+// falls back to jqCompletionFunc if the schema isn't known.
+func getJqCompletionFunc(verb int) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		resource, ok := resources.GetResourceByName(cmd.Name())
+		if !ok {
+			return jqCompletionFunc(cmd, args, toComplete)
+		}
+
+		var crudInfo *resources.CrudEntityInfo
+
+		switch verb {
+		case completion.Get:
+			if _, ok := resources.GetPluralResources()[cmd.Name()]; ok && resource.GetCollectionInfo != nil {
+				crudInfo = resource.GetCollectionInfo
+			} else {
+				crudInfo = resource.GetEntityInfo
+			}
+		case completion.Create:
+			crudInfo = resource.CreateEntityInfo
+		case completion.Update:
+			crudInfo = resource.UpdateEntityInfo
+		}
+
+		if crudInfo == nil || crudInfo.OpenApiOperationId == "" {
+			return jqCompletionFunc(cmd, args, toComplete)
+		}
+
+		paths, err := openapi.GetJqPathsForResponse(crudInfo.OpenApiOperationId)
+		if err != nil || len(paths) == 0 {
+			log.Debugf("Could not get jq paths for %s: %v", crudInfo.OpenApiOperationId, err)
+			return jqCompletionFunc(cmd, args, toComplete)
+		}
+
+		return paths, cobra.ShellCompDirectiveNoSpace
+	}
+}
+
 var profileNameFromCommandLine = ""
 
 // ErrReadOnlyMode is returned when a write operation is attempted in read-only mode
@@ -133,9 +177,18 @@ func InitializeCmd() {
 	log.Tracef("Building Get All Commands")
 	NewGetAllCommand(RootCmd)
 
+	log.Tracef("Building Diff Commands")
+	NewDiffCommand(RootCmd)
+
+	log.Tracef("Building Assert Commands")
+	NewAssertCommand(RootCmd)
+
 	log.Tracef("Building Resource Info Commands")
 	NewResourceInfoCommand(RootCmd)
 
+	log.Tracef("Building Resources Commands")
+	NewResourcesCommand(RootCmd)
+
 	Logs.AddCommand(LogsList, LogsShow, LogsClear, LogsCurlReplay)
 
 	LogsShow.PersistentFlags().BoolVarP(&LogsShowPrettyJson, "pretty", "", false, "If set, we will pretty print json request and responses")
@@ -161,6 +214,7 @@ func InitializeCmd() {
 
 	RootCmd.PersistentFlags().Float32VarP(&requestTimeout, "timeout", "", 60, "Request timeout in seconds (fractional values allowed)")
 	RootCmd.PersistentFlags().Uint16VarP(&statisticsFrequency, "statistics-frequency", "", 15, "How often to print runtime statistics (0 turns them off)")
+	RootCmd.PersistentFlags().Int64VarP(&seed, "seed", "", 0, "Seed for random values in templates and auto-fill, so that the same data is generated every time")
 
 	ResetStore.ResetFlags()
 	ResetStore.PersistentFlags().BoolVarP(&DeleteApplicationKeys, "delete-application-keys", "", false, "if set, we delete application keys as well")
@@ -246,11 +300,13 @@ Environment Variables
 - EPCC_PROFILE - The name of the profile we will use (isolates namespace, credentials, etc...)
 - EPCC_CLI_DISABLE_TLS_VERIFICATION - Disables TLS verification
 - EPCC_RUNBOOK_DIRECTORY - Directory to scan for additional runbooks
+- EPCC_RESOURCE_DIRECTORY - Directory to scan for additional or overriding resource definitions (.yaml files)
 - EPCC_CLI_DISABLE_TEMPLATE_EXECUTION - Disables template execution (recommended if input is untrusted).
 - EPCC_CLI_DISABLE_RESOURCES - A comma seperated list of resources that will be hidden in command lists
 - EPCC_CLI_RATE_LIMIT - The default rate limit to use.
 - EPCC_CLI_DISABLE_HTTP_LOGGING - Disables writing of HTTP logs
 - EPCC_CLI_READ_ONLY - Enables read-only mode, blocking create/update/delete operations
+- EPCC_CLI_SEED - The default seed for random values (e.g., in templates and auto-fill)
 `,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			log.SetLevel(logger.Loglevel)
@@ -264,6 +320,21 @@ Environment Variables
 				rateLimit = 20
 			}
 
+			if cmd.Root().PersistentFlags().Changed("seed") {
+				random.SetSeed(seed)
+			} else if e.EPCC_CLI_SEED != "" {
+				envSeed, err := strconv.ParseInt(e.EPCC_CLI_SEED, 10, 64)
+				if err != nil {
+					return fmt.Errorf("could not parse EPCC_CLI_SEED %s, it must be an integer: %w", e.EPCC_CLI_SEED, err)
+				}
+
+				random.SetSeed(envSeed)
+			}
+
+			if s, seeded := random.GetSeed(); seeded {
+				log.Debugf("Random values will be generated with seed %d", s)
+			}
+
 			authentication.Initialize()
 
 			log.Debugf("Rate limit set to %d request per second, printing statistics every %d seconds ", rateLimit, statisticsFrequency)
@@ -331,6 +402,9 @@ func Execute() {
 	<-shutdownHandlerDone
 
 	if err != nil {
+		if errors.Is(err, ErrDifferencesFound) {
+			os.Exit(5)
+		}
 		if errors.Is(err, ErrReadOnlyMode) {
 			log.Errorf("Error: %s", err)
 			os.Exit(4)
@@ -3,16 +3,23 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/buildkite/shellwords"
+	"github.com/elasticpath/epcc-cli/external/aliases"
 	"github.com/elasticpath/epcc-cli/external/clictx"
 	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/httpclient"
 	"github.com/elasticpath/epcc-cli/external/misc"
+	"github.com/elasticpath/epcc-cli/external/random"
 	"github.com/elasticpath/epcc-cli/external/resources"
 	"github.com/elasticpath/epcc-cli/external/runbooks"
 	_ "github.com/elasticpath/epcc-cli/external/runbooks"
@@ -34,6 +41,7 @@ var runbookGlobalCmd = &cobra.Command{
 
 func initRunbookCommands() {
 	runbooks.InitializeBuiltInRunbooks()
+	runbooks.SetCommandFlagParser(parseRunbookCommandFlags)
 
 	runbookGlobalCmd.AddCommand(initRunbookShowCommands())
 	runbookGlobalCmd.AddCommand(initRunbookRunCommands())
@@ -45,24 +53,49 @@ func initRunbookCommands() {
 var AbortRunbookExecution = atomic.Bool{}
 
 func initRunbookValidateCommands() *cobra.Command {
+	var strict bool
+
 	// epcc runbook validate
 	runbookValidateCommand := &cobra.Command{
 		Use:   "validate",
 		Short: "Validates all runbooks",
+		Long: `Validates all runbooks, including that each epcc command uses a resource that supports the verb, with the ids it needs, and flags that exist.
+Warnings are shown for likely mistakes (e.g., attributes the resource doesn't have, invalid ENUM values, and aliases for a type that no earlier command creates or retrieves), and fail validation with --strict.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			errMsg := ""
+			warnings := 0
 
-			for _, runbook := range runbooks.GetRunbooks() {
+			runbooksByName := runbooks.GetRunbooks()
+			for _, name := range runbooks.GetRunbookNames() {
+				runbook := runbooksByName[name]
 				log.Debugf("Validating runbook %s", runbook.Name)
-				err := runbooks.ValidateRunbook(&runbook)
+				problems, err := runbooks.LintRunbook(&runbook)
 
 				if err != nil {
 					newErr := fmt.Errorf("validation of runbook '%s' failed: %v", runbook.Name, err)
 					errMsg += newErr.Error() + "\n"
+					continue
+				}
+
+				for _, problem := range problems {
+					if !problem.Warning {
+						errMsg += fmt.Sprintf("validation of runbook '%s' failed: %v\n", runbook.Name, problem)
+						continue
+					}
+
+					warnings++
+					log.Warnf("%v", problem)
+
+					if strict {
+						errMsg += fmt.Sprintf("validation of runbook '%s' has a warning (--strict): %v\n", runbook.Name, problem)
+					}
 				}
 			}
 
-			if errMsg == "" {
+			if errMsg == "" && warnings > 0 {
+				log.Infof("All runbooks are valid, with %d warning(s)", warnings)
+				return nil
+			} else if errMsg == "" {
 				log.Infof("All runbooks are valid!")
 				return nil
 			} else {
@@ -71,6 +104,8 @@ func initRunbookValidateCommands() *cobra.Command {
 		},
 	}
 
+	runbookValidateCommand.Flags().BoolVar(&strict, "strict", false, "Fail validation if there are warnings (e.g., in CI)")
+
 	return runbookValidateCommand
 }
 
@@ -86,18 +121,19 @@ func initRunbookShowCommands() *cobra.Command {
 	var asBash bool
 	runbookShowCommand.PersistentFlags().BoolVarP(&asBash, "as-bash", "", false, "Display the runbook contents as bash commands")
 	runbookShowCommand.PersistentFlags().Int64("execution-timeout", 900, "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().Int64("cleanup-timeout", 300, "Does nothing, just here in case you swap run for show to debug")
 	runbookShowCommand.PersistentFlags().Int("max-concurrency", 20, "Does nothing, just here in case you swap run for show to debug")
-
-	err := runbookShowCommand.PersistentFlags().MarkHidden("execution-timeout")
-
-	if err != nil {
-		panic(err)
-	}
-
-	err = runbookShowCommand.PersistentFlags().MarkHidden("max-concurrency")
-
-	if err != nil {
-		panic(err)
+	runbookShowCommand.PersistentFlags().Bool("resume", false, "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().String("from-step", "", "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().String("to-step", "", "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().String("report-json", "", "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().String("report-junit", "", "Does nothing, just here in case you swap run for show to debug")
+	runbookShowCommand.PersistentFlags().Bool("interactive", false, "Does nothing, just here in case you swap run for show to debug")
+
+	for _, flag := range []string{"execution-timeout", "cleanup-timeout", "max-concurrency", "resume", "from-step", "to-step", "report-json", "report-junit", "interactive"} {
+		if err := runbookShowCommand.PersistentFlags().MarkHidden(flag); err != nil {
+			panic(err)
+		}
 	}
 
 	for _, runbook := range runbooks.GetRunbooks() {
@@ -142,70 +178,148 @@ func initRunbookShowCommands() *cobra.Command {
 				Long:  runbookAction.Description.Long,
 				Short: runbookAction.Description.Short,
 				RunE: func(cmd *cobra.Command, args []string) error {
+					renderRunbookVariableDefaults(cmd, runbook.Name, runbookAction, runbookStringArguments)
 
-					cmds := runbookAction.RawCommands
-					for stepIdx := 0; stepIdx < len(cmds); stepIdx++ {
-						cmd := cmds[stepIdx]
-						templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %d", runbook.Name, runbookAction.Name, stepIdx)
-						rawCmdLines, err := runbooks.RenderTemplates(templateName, cmd, runbookStringArguments, runbookAction.Variables)
+					return showRunbookAction(runbook.Name, runbookAction, runbookStringArguments, []any{runbook.Name, runbookAction.Name}, "", asBash)
+				},
+			}
 
-						if err != nil {
-							return err
-						}
+			processRunbookVariablesOnCommand(runbookShowRunbookActionCmd, runbookStringArguments, runbookAction.Variables, false)
 
-						joinedString := strings.Join(rawCmdLines, "\n")
-						renderedCmd := []string{}
-						err = yaml.Unmarshal([]byte(joinedString), &renderedCmd)
-
-						if err == nil {
-							log.Tracef("Line %d is a Yaml array %s, inserting into stack", stepIdx, joinedString)
-							newCmds := make([]string, 0, len(cmds)+len(renderedCmd)-1)
-							newCmds = append(newCmds, cmds[0:stepIdx]...)
-							newCmds = append(newCmds, renderedCmd...)
-							newCmds = append(newCmds, cmds[stepIdx+1:]...)
-							cmds = newCmds
-							stepIdx--
-							continue
-						}
+			runbookShowRunbookCmd.AddCommand(runbookShowRunbookActionCmd)
+		}
+	}
 
-						fmt.Printf("# Step %d\n", stepIdx)
+	return runbookShowCommand
+}
 
-						runConcurrently := len(rawCmdLines) > 1
-						for _, line := range rawCmdLines {
-							if len(strings.Trim(line, " \n")) > 0 {
+// showRunbookAction prints the rendered steps of an action (followed by the on_failure and finally steps), the steps of called actions are shown inline with the step of the call as a prefix (e.g., 3.1)
+func showRunbookAction(runbookName string, runbookAction *runbooks.RunbookAction, stringVars map[string]*string, streamLabels []any, stepPrefix string, asBash bool) error {
+	if err := showSteps(runbookName, runbookAction, runbooks.IdentifySteps("", runbookAction.Steps), stringVars, streamLabels, stepPrefix, asBash); err != nil {
+		return err
+	}
 
-								fmt.Print(line)
+	if len(runbookAction.OnFailure) > 0 {
+		fmt.Printf("# On failure of %s/%s (only runs if a step fails)\n", runbookName, runbookAction.Name)
 
-								if runConcurrently && asBash {
-									fmt.Print("&")
-								}
+		if err := showSteps(runbookName, runbookAction, runbooks.IdentifySteps("on_failure-", runbookAction.OnFailure), stringVars, streamLabels, stepPrefix, asBash); err != nil {
+			return err
+		}
+	}
 
-								fmt.Println()
-							}
+	if len(runbookAction.Finally) > 0 {
+		fmt.Printf("# Finally of %s/%s (always runs)\n", runbookName, runbookAction.Name)
 
-						}
+		return showSteps(runbookName, runbookAction, runbooks.IdentifySteps("finally-", runbookAction.Finally), stringVars, streamLabels, stepPrefix, asBash)
+	}
 
-						if runConcurrently && asBash {
-							fmt.Println("# Wait for all processes to complete\nwait")
-							fmt.Println()
-						}
-					}
-					return nil
-				},
+	return nil
+}
+
+func showSteps(runbookName string, runbookAction *runbooks.RunbookAction, steps []runbooks.RunbookStep, stringVars map[string]*string, streamLabels []any, stepPrefix string, asBash bool) error {
+	labels := func(extra ...any) []any {
+		return append(append(make([]any, 0, len(streamLabels)+len(extra)), streamLabels...), extra...)
+	}
+
+	for stepIdx := 0; stepIdx < len(steps); stepIdx++ {
+		step := steps[stepIdx]
+		templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %s", runbookName, runbookAction.Name, step.ID)
+		// The same stream as run, so that with a seed this shows what would run
+		stepCtx := random.WithStream(clictx.Ctx, random.NewStream(labels(step.ID)...))
+
+		var rawCmdLines []string
+		if step.Call == "" {
+			var err error
+			rawCmdLines, err = runbooks.RenderTemplates(stepCtx, templateName, step.Run, stringVars, runbookAction.Variables)
+
+			if err != nil {
+				return err
 			}
 
-			processRunbookVariablesOnCommand(runbookShowRunbookActionCmd, runbookStringArguments, runbookAction.Variables, false)
+			if renderedSteps, ok := runbooks.ParseRenderedSteps(step, rawCmdLines); ok {
+				log.Tracef("Step %s is a Yaml array %s, inserting into stack", step.ID, strings.Join(rawCmdLines, "\n"))
+				newSteps := make([]runbooks.RunbookStep, 0, len(steps)+len(renderedSteps)-1)
+				newSteps = append(newSteps, steps[0:stepIdx]...)
+				newSteps = append(newSteps, renderedSteps...)
+				newSteps = append(newSteps, steps[stepIdx+1:]...)
+				steps = newSteps
+				stepIdx--
+				continue
+			}
+		}
 
-			runbookShowRunbookCmd.AddCommand(runbookShowRunbookActionCmd)
+		if step.Name != "" {
+			fmt.Printf("# Step %s%s: %s\n", stepPrefix, step.ID, step.Name)
+		} else {
+			fmt.Printf("# Step %s%s\n", stepPrefix, step.ID)
 		}
-	}
 
-	return runbookShowCommand
+		if step.When != "" {
+			fmt.Printf("# Only runs when: %s\n", step.When)
+		}
+
+		if step.Retries > 0 {
+			fmt.Printf("# Retries: %d (delay: %s)\n", step.Retries, step.RetryDelay)
+		}
+
+		if step.Timeout > 0 {
+			fmt.Printf("# Timeout: %s\n", step.Timeout)
+		}
+
+		if step.IgnoreErrors != nil {
+			fmt.Printf("# Ignore errors: %t\n", *step.IgnoreErrors)
+		}
+
+		if step.Call != "" {
+			calledRunbook, calledAction, err := runbooks.ResolveCall(nil, step.Call)
+
+			if err != nil {
+				return err
+			}
+
+			calledArguments, err := runbooks.RenderCallArguments(stepCtx, step, calledAction, stringVars, runbookAction.Variables)
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("# Calls: %s\n", step.Call)
+
+			err = showRunbookAction(calledRunbook.Name, calledAction, calledArguments, labels(step.ID, calledRunbook.Name, calledAction.Name), stepPrefix+step.ID+".", asBash)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		runConcurrently := len(rawCmdLines) > 1
+		for _, line := range rawCmdLines {
+			if len(strings.Trim(line, " \n")) > 0 {
+
+				fmt.Print(line)
+
+				if runConcurrently && asBash {
+					fmt.Print("&")
+				}
+
+				fmt.Println()
+			}
+
+		}
+
+		if runConcurrently && asBash {
+			fmt.Println("# Wait for all processes to complete\nwait")
+			fmt.Println()
+		}
+	}
+	return nil
 }
 
 type commandResult struct {
 	error       error
-	stepIdx     int
+	stepId      string
 	commandIdx  int
 	commandLine string
 }
@@ -221,7 +335,17 @@ func initRunbookRunCommands() *cobra.Command {
 	}
 
 	execTimeoutInSeconds := runbookRunCommand.PersistentFlags().Int64("execution-timeout", 900, "How long should the script take to execute before timing out")
+	cleanupTimeoutInSeconds := runbookRunCommand.PersistentFlags().Int64("cleanup-timeout", 300, "How long should the on_failure and finally steps of an action take to execute before timing out")
 	maxConcurrency := runbookRunCommand.PersistentFlags().Int("max-concurrency", 20, "Maximum number of commands that can run simultaneously")
+	resume := runbookRunCommand.PersistentFlags().Bool("resume", false, "Continue from where the last run of the action stopped (e.g., after a step failed)")
+	fromStep := runbookRunCommand.PersistentFlags().String("from-step", "", "The first step to run, as shown by runbooks show (e.g., 3 or 3.2)")
+	toStep := runbookRunCommand.PersistentFlags().String("to-step", "", "The last step to run, as shown by runbooks show (e.g., 5 or 5.1)")
+	runbookRunCommand.MarkFlagsMutuallyExclusive("resume", "from-step")
+	interactive := runbookRunCommand.PersistentFlags().Bool("interactive", false, "Prompt for the variables of the action (the default when stdin is a terminal and required variables are missing)")
+	reportFiles := runbookReportFiles{
+		json:  runbookRunCommand.PersistentFlags().String("report-json", "", "Write a report of the steps and commands that ran to this file as JSON"),
+		junit: runbookRunCommand.PersistentFlags().String("report-junit", "", "Write a report of the steps and commands that ran to this file as JUnit XML (steps are test suites, and commands are test cases)"),
+	}
 
 	for _, runbook := range runbooks.GetRunbooks() {
 		// Create a copy of runbook scoped to the loop
@@ -258,13 +382,60 @@ func initRunbookRunCommands() *cobra.Command {
 
 			runbookStringArguments := runbooks.CreateMapForRunbookArgumentPointers(runbookAction)
 
+			// The checkpoint of the last run, when resuming
+			var resumeCheckpoint *runbooks.Checkpoint
+
 			// epcc runbook run <runbook> <action>
 			runbookActionRunActionCommand := &cobra.Command{
 				Use:   runbookAction.Name,
 				Long:  runbookAction.Description.Long,
 				Short: runbookAction.Description.Short,
+				// Resuming and prompting happen before cobra checks that required flags are set
+				PreRunE: func(cmd *cobra.Command, args []string) error {
+					if *resume {
+						checkpoint, err := runbooks.LoadCheckpoint(runbook.Name, runbookAction.Name)
+
+						if err != nil {
+							return err
+						}
+
+						// The variables of the last run are used, unless they are set again
+						if err := setRunbookVariablesFromCheckpoint(cmd, checkpoint, runbookAction.Variables); err != nil {
+							return err
+						}
+
+						resumeCheckpoint = checkpoint
+					}
+
+					if !shouldPromptForRunbookVariables(cmd, *interactive, runbookAction.Variables) {
+						return nil
+					}
+
+					if err := promptForRunbookVariables(cmd, runbookAction.Variables, os.Stdin, os.Stderr); err != nil {
+						return err
+					}
+
+					fmt.Fprintf(os.Stderr, "\nTo run this again without prompting:\n  %s\n\n", nonInteractiveCommandLine(cmd))
+					return nil
+				},
 				RunE: func(cmd *cobra.Command, args []string) error {
-					return processRunBookCommands(runbook.Name, runbookStringArguments, runbookAction, maxConcurrency, execTimeoutInSeconds)
+					renderRunbookVariableDefaults(cmd, runbook.Name, runbookAction, runbookStringArguments)
+
+					stepRange, err := runbooks.NewStepRange(*fromStep, *toStep)
+
+					if err != nil {
+						return err
+					}
+
+					checkpoint := runbooks.NewCheckpoint(runbook.Name, runbookAction.Name)
+
+					if *resume {
+						checkpoint = resumeCheckpoint
+						stepRange.After = checkpoint.LastCompletedStep
+						log.Infof("Resuming %s %s after step %s", runbook.Name, runbookAction.Name, checkpoint.LastCompletedStep)
+					}
+
+					return processRunBookCommands(runbook.Name, runbookStringArguments, runbookAction, maxConcurrency, execTimeoutInSeconds, cleanupTimeoutInSeconds, checkpoint, stepRange, reportFiles)
 				},
 			}
 			processRunbookVariablesOnCommand(runbookActionRunActionCommand, runbookStringArguments, runbookAction.Variables, true)
@@ -286,6 +457,10 @@ func initRunbookRunScriptCmd() *cobra.Command {
 
 	execTimeoutInSeconds := cmd.Flags().Int64("execution-timeout", 900, "How long should the script take to execute before timing out")
 	maxConcurrency := cmd.Flags().Int("max-concurrency", 20, "Maximum number of commands that can run simultaneously")
+	reportFiles := runbookReportFiles{
+		json:  cmd.Flags().String("report-json", "", "Write a report of the steps and commands that ran to this file as JSON"),
+		junit: cmd.Flags().String("report-junit", "", "Write a report of the steps and commands that ran to this file as JUnit XML (steps are test suites, and commands are test cases)"),
+	}
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
@@ -301,75 +476,393 @@ func initRunbookRunScriptCmd() *cobra.Command {
 			return fmt.Errorf("could not read file %s: %v", args[0], err)
 		}
 
-		var commands []string
-		err = yaml.Unmarshal(data, &commands)
+		var steps []runbooks.RunbookStep
+		err = yaml.Unmarshal(data, &steps)
 		if err != nil {
 			return fmt.Errorf("could not parse YAML file %s: %v", args[0], err)
 		}
 
 		runbookAction := &runbooks.RunbookAction{
-			RawCommands: commands,
+			Steps: steps,
 		}
 
-		return processRunBookCommands("exec-script", map[string]*string{}, runbookAction, maxConcurrency, execTimeoutInSeconds)
+		// Scripts don't have on_failure or finally steps, so there is no cleanup timeout
+		return processRunBookCommands("exec-script", map[string]*string{}, runbookAction, maxConcurrency, execTimeoutInSeconds, execTimeoutInSeconds, nil, runbooks.StepRange{}, reportFiles)
 	}
 
 	return cmd
 }
 
-func processRunBookCommands(runbookName string, runbookStringArguments map[string]*string, runbookAction *runbooks.RunbookAction, maxConcurrency *int, execTimeoutInSeconds *int64) error {
-	numSteps := len(runbookAction.RawCommands)
-
-	parentCtx := clictx.Ctx
-
-	ctx, cancelFunc := context.WithCancel(parentCtx)
+// runbookExecution is what an action shares with the actions it calls (e.g., the pool of commands, the limit on concurrent commands and the timeout)
+type runbookExecution struct {
+	ctx                    context.Context
+	cancelFunc             context.CancelFunc
+	objectPool             *pool.ObjectPool
+	concurrentRunSemaphore *semaphore.Weighted
+	maxConcurrency         int
+	execTimeout            time.Duration
+	cleanupTimeout         time.Duration
+	// What happens is recorded in the report, if there is one
+	report *runbooks.Report
+}
 
-	concurrentRunSemaphore := semaphore.NewWeighted(int64(*maxConcurrency))
+func newRunbookExecution(ctx context.Context, cancelFunc context.CancelFunc, maxConcurrency int, execTimeout time.Duration, cleanupTimeout time.Duration, report *runbooks.Report) *runbookExecution {
 	factory := pool.NewPooledObjectFactorySimple(
 		func(ctx2 context.Context) (interface{}, error) {
 			return generateRunbookCmd(), nil
 		})
 
-	objectPool := pool.NewObjectPool(ctx, factory, &pool.ObjectPoolConfig{
-		MaxTotal: *maxConcurrency,
-		MaxIdle:  *maxConcurrency,
-	})
+	return &runbookExecution{
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+		objectPool: pool.NewObjectPool(ctx, factory, &pool.ObjectPoolConfig{
+			MaxTotal: maxConcurrency,
+			MaxIdle:  maxConcurrency,
+		}),
+		concurrentRunSemaphore: semaphore.NewWeighted(int64(maxConcurrency)),
+		maxConcurrency:         maxConcurrency,
+		execTimeout:            execTimeout,
+		cleanupTimeout:         cleanupTimeout,
+		report:                 report,
+	}
+}
+
+// cleanupExecution returns an execution for on_failure and finally steps, it isn't cancelled when this one is (e.g., on shutdown) but has the cleanup timeout
+func (e *runbookExecution) cleanupExecution() (*runbookExecution, context.CancelFunc) {
+	ctx, cancelFunc := context.WithTimeout(shutdown.WithCleanup(context.WithoutCancel(e.ctx)), e.cleanupTimeout)
+
+	return newRunbookExecution(ctx, cancelFunc, e.maxConcurrency, e.cleanupTimeout, e.cleanupTimeout, e.report), cancelFunc
+}
+
+// shuttingDown returns whether commands should stop being run, cleanup steps still run when shutting down
+func (e *runbookExecution) shuttingDown() bool {
+	return shutdown.ShutdownFlag.Load() && !shutdown.IsCleanup(e.ctx)
+}
+
+// runbookReportFiles are the files to write reports of an execution to (e.g., for CI), a report isn't written if its file is empty
+type runbookReportFiles struct {
+	json  *string
+	junit *string
+}
+
+func (f runbookReportFiles) enabled() bool {
+	return *f.json != "" || *f.junit != ""
+}
+
+func (f runbookReportFiles) write(report *runbooks.Report) {
+	if *f.json != "" {
+		if err := report.WriteJson(*f.json); err != nil {
+			log.Warnf("%v", err)
+		} else {
+			log.Infof("Wrote JSON report to %s", *f.json)
+		}
+	}
+
+	if *f.junit != "" {
+		if err := report.WriteJUnit(*f.junit); err != nil {
+			log.Warnf("%v", err)
+		} else {
+			log.Infof("Wrote JUnit report to %s", *f.junit)
+		}
+	}
+}
+
+// processRunBookCommands runs an action, if there is a checkpoint the progress is saved in it, and it's deleted once the action finishes
+func processRunBookCommands(runbookName string, runbookStringArguments map[string]*string, runbookAction *runbooks.RunbookAction, maxConcurrency *int, execTimeoutInSeconds *int64, cleanupTimeoutInSeconds *int64, checkpoint *runbooks.Checkpoint, stepRange runbooks.StepRange, reportFiles runbookReportFiles) error {
+	// On shutdown (e.g., Ctrl-C) wait for the on_failure and finally steps to run before exiting
+	shutdown.OutstandingOpCounter.Add(1)
+	defer shutdown.OutstandingOpCounter.Done()
+
+	parentCtx := clictx.Ctx
+
+	ctx, cancelFunc := context.WithCancel(parentCtx)
+	defer cancelFunc()
+
+	var report *runbooks.Report
+	if reportFiles.enabled() {
+		report = runbooks.NewReport(runbookName, runbookAction.Name)
+	}
+
+	execution := newRunbookExecution(ctx, cancelFunc, *maxConcurrency, time.Duration(*execTimeoutInSeconds)*time.Second, time.Duration(*cleanupTimeoutInSeconds)*time.Second, report)
+
+	_, err := execution.runAction(runbookName, runbookAction, runbookStringArguments, []any{runbookName, runbookAction.Name}, []string{runbookName + "/" + runbookAction.Name}, "", checkpoint, stepRange)
+
+	if report != nil {
+		report.Finish(err)
+		reportFiles.write(report)
+	}
+
+	if checkpoint != nil {
+		if err != nil {
+			log.Infof("Progress has been saved, after fixing the problem, you can continue with: epcc runbooks run %s %s --resume", runbookName, runbookAction.Name)
+		} else if stepRange.IncludesEnd() {
+			checkpoint.Delete()
+		}
+	}
+
+	return err
+}
+
+// actionRun is the state of an action while it runs, values captured from commands (e.g., --capture total='.data.total') are added to copies of the variables, so that they don't change the runbook
+type actionRun struct {
+	runbookName         string
+	action              *runbooks.RunbookAction
+	stringVars          map[string]*string
+	variableDefinitions map[string]runbooks.Variable
+	captured            map[string]string
+	streamLabels        []any
+	callStack           []string
+	// The prefix of the IDs of steps in the report (e.g., 3. for an action called by step 3)
+	stepPrefix string
+}
+
+func newActionRun(runbookName string, runbookAction *runbooks.RunbookAction, runbookStringArguments map[string]*string, streamLabels []any, callStack []string, stepPrefix string) *actionRun {
+	stringVars := make(map[string]*string, len(runbookStringArguments))
+	for k, v := range runbookStringArguments {
+		stringVars[k] = v
+	}
+
+	variableDefinitions := make(map[string]runbooks.Variable, len(runbookAction.Variables))
+	for k, v := range runbookAction.Variables {
+		variableDefinitions[k] = v
+	}
+
+	return &actionRun{
+		runbookName:         runbookName,
+		action:              runbookAction,
+		stringVars:          stringVars,
+		variableDefinitions: variableDefinitions,
+		captured:            map[string]string{},
+		streamLabels:        streamLabels,
+		callStack:           callStack,
+		stepPrefix:          stepPrefix,
+	}
+}
+
+func (r *actionRun) addCapturedValue(k string, v string) {
+	log.Debugf("Captured %s = %s", k, v)
+	// Captured values are JSON, but in templates a string is used without quotes
+	templateValue := runbooks.CapturedValueForTemplate(v)
+	r.stringVars[k] = &templateValue
+	r.captured[k] = v
+
+	if _, ok := r.variableDefinitions[k]; !ok {
+		r.variableDefinitions[k] = runbooks.Variable{Name: k, Type: "STRING"}
+	}
+}
+
+// labels returns the labels of a random stream for part of the action (e.g., a step)
+func (r *actionRun) labels(extra ...any) []any {
+	return append(append(make([]any, 0, len(r.streamLabels)+len(extra)), r.streamLabels...), extra...)
+}
+
+// runAction runs the steps of an action and returns the values it captured, random values come from streams with the labels for the action, and the call stack has the actions being run to detect cycles.
+// The IDs of steps in the report have the step prefix (e.g., 3. for an action called by step 3).
+// The progress is saved in the checkpoint after each step (called actions don't have one, as the call is a single step).
+// If the steps fail the on_failure steps run, and then the finally steps always run, even when shutting down (e.g., Ctrl-C), but the action still fails.
+func (e *runbookExecution) runAction(runbookName string, runbookAction *runbooks.RunbookAction, runbookStringArguments map[string]*string, streamLabels []any, callStack []string, stepPrefix string, checkpoint *runbooks.Checkpoint, stepRange runbooks.StepRange) (map[string]string, error) {
+	run := newActionRun(runbookName, runbookAction, runbookStringArguments, streamLabels, callStack, stepPrefix)
+
+	if checkpoint != nil {
+		// Values captured before resuming
+		for k, v := range checkpoint.Captured {
+			run.addCapturedValue(k, v)
+		}
+
+		for k, v := range runbookStringArguments {
+			checkpoint.Variables[k] = *v
+		}
+	}
+
+	err := e.runSteps(run, runbooks.IdentifySteps("", runbookAction.Steps), checkpoint, stepRange)
+
+	if len(runbookAction.OnFailure) == 0 && len(runbookAction.Finally) == 0 {
+		return run.captured, err
+	}
+
+	cleanup, cancelCleanup := e.cleanupExecution()
+	defer cancelCleanup()
+
+	if err != nil && len(runbookAction.OnFailure) > 0 {
+		log.Infof("Running on_failure steps of %s", runbookAction.Name)
+
+		if failureErr := cleanup.runSteps(run, runbooks.IdentifySteps("on_failure-", runbookAction.OnFailure), nil, runbooks.StepRange{}); failureErr != nil {
+			log.Warnf("Error in on_failure steps of %s: %v", runbookAction.Name, failureErr)
+		}
+	}
+
+	if len(runbookAction.Finally) > 0 {
+		log.Infof("Running finally steps of %s", runbookAction.Name)
+
+		if finallyErr := cleanup.runSteps(run, runbooks.IdentifySteps("finally-", runbookAction.Finally), nil, runbooks.StepRange{}); finallyErr != nil {
+			if err == nil {
+				err = fmt.Errorf("error in finally steps: %w", finallyErr)
+			} else {
+				log.Warnf("Error in finally steps of %s: %v", runbookAction.Name, finallyErr)
+			}
+		}
+	}
+
+	return run.captured, err
+}
 
-	rawCmds := runbookAction.RawCommands
-	for stepIdx := 0; stepIdx < len(rawCmds); stepIdx++ {
+// runSteps runs steps of an action, the progress is saved in the checkpoint (if there is one) after each step
+func (e *runbookExecution) runSteps(run *actionRun, steps []runbooks.RunbookStep, checkpoint *runbooks.Checkpoint, stepRange runbooks.StepRange) error {
+	numSteps := len(steps)
+	runbookName := run.runbookName
+	runbookAction := run.action
+	stringVars := run.stringVars
+	variableDefinitions := run.variableDefinitions
+	labels := run.labels
 
-		origIndex := &stepIdx
-		// Create a copy of loop variables
-		stepIdx := stepIdx
-		rawCmd := rawCmds[stepIdx]
+	ctx := e.ctx
 
-		templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %d", runbookName, runbookAction.Name, stepIdx)
-		rawCmdLines, err := runbooks.RenderTemplates(templateName, rawCmd, runbookStringArguments, runbookAction.Variables)
+	saveCheckpoint := func(step runbooks.RunbookStep, commandStatuses []runbooks.CommandStatus, completed bool) {
+		if checkpoint == nil {
+			return
+		}
+
+		checkpoint.CurrentStep = step.ID
+		checkpoint.Commands = commandStatuses
+		checkpoint.Captured = run.captured
+
+		if completed {
+			checkpoint.LastCompletedStep = step.ID
+		}
+
+		if err := checkpoint.Save(); err != nil {
+			log.Warnf("Could not save progress of step %s, %v", step.Describe(), err)
+		}
+	}
+
+	for stepIdx := 0; stepIdx < len(steps); stepIdx++ {
+
+		step := steps[stepIdx]
+
+		if stepRange.Skips(step.ID) {
+			log.Debugf("Skipping step %s, as it isn't in the steps to run", step.Describe())
+			e.report.AddStep(run.stepPrefix+step.ID, step.Name).Skip("not in the steps to run")
+			continue
+		}
+
+		if e.shuttingDown() {
+			return fmt.Errorf("aborting runbook execution before step %s, as the program is shutting down", step.Describe())
+		}
+
+		shouldRun, err := step.EvaluateWhen(stringVars, variableDefinitions)
 
 		if err != nil {
-			cancelFunc()
+			err = fmt.Errorf("error in step %s: %w", step.Describe(), err)
+			e.report.AddStep(run.stepPrefix+step.ID, step.Name).Finish(err)
 			return err
 		}
 
-		joinedString := strings.Join(rawCmdLines, "\n")
-		renderedCmd := []string{}
+		if !shouldRun {
+			log.Infof("Skipping step %s, as %s is false", step.Describe(), step.When)
+			e.report.AddStep(run.stepPrefix+step.ID, step.Name).Skip(step.When + " is false")
+			saveCheckpoint(step, nil, true)
+			continue
+		}
+
+		templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %s", runbookName, runbookAction.Name, step.ID)
+		// Random values come from streams for each step and command, so that with a seed they don't depend on the order commands run in
+		stepStream := random.NewStream(labels(step.ID)...)
+		stepCtx := random.WithStream(ctx, stepStream)
+
+		if step.Call != "" {
+			stepReport := e.report.AddStep(run.stepPrefix+step.ID, step.Name)
+			stepReport.Calls(step.Call)
+
+			// Steps rendered from templates are only validated with the default values of the variables
+			if err := step.ValidateCall(); err != nil {
+				err = fmt.Errorf("error in step %s: %w", step.Describe(), err)
+				stepReport.Finish(err)
+				return err
+			}
+
+			calledRunbook, calledAction, err := runbooks.ResolveCall(nil, step.Call)
+
+			if err != nil {
+				err = fmt.Errorf("error in step %s: %w", step.Describe(), err)
+				stepReport.Finish(err)
+				return err
+			}
+
+			calledName := calledRunbook.Name + "/" + calledAction.Name
+			for _, name := range run.callStack {
+				if name == calledName {
+					err = fmt.Errorf("error in step %s: call cycle detected: %s -> %s", step.Describe(), strings.Join(run.callStack, " -> "), calledName)
+					stepReport.Finish(err)
+					return err
+				}
+			}
+
+			calledArguments, err := runbooks.RenderCallArguments(stepCtx, step, calledAction, stringVars, variableDefinitions)
+
+			if err != nil {
+				err = fmt.Errorf("error in step %s: %w", step.Describe(), err)
+				stepReport.Finish(err)
+				return err
+			}
 
-		err = yaml.Unmarshal([]byte(joinedString), &renderedCmd)
+			log.Infof("Calling> %s", step.Call)
+			calledStack := append(append(make([]string, 0, len(run.callStack)+1), run.callStack...), calledName)
+			calledCaptures, err := e.runAction(calledRunbook.Name, calledAction, calledArguments, labels(step.ID, calledRunbook.Name, calledAction.Name), calledStack, run.stepPrefix+step.ID+".", nil, runbooks.StepRange{})
+			stepReport.Finish(err)
 
-		if err == nil {
-			log.Tracef("Line %d is a Yaml array %s, inserting into stack", stepIdx, joinedString)
-			newCmds := make([]string, 0, len(rawCmds)+len(renderedCmd)-1)
-			newCmds = append(newCmds, rawCmds[0:stepIdx]...)
-			newCmds = append(newCmds, renderedCmd...)
-			newCmds = append(newCmds, rawCmds[stepIdx+1:]...)
-			rawCmds = newCmds
-			*origIndex--
+			// Values captured by the called action can be used by the later steps
+			for k, v := range calledCaptures {
+				run.addCapturedValue(k, v)
+			}
+
+			callStatus := runbooks.CommandStatus{Command: "call " + step.Call, Status: runbooks.CommandSucceeded}
+
+			if err != nil {
+				callStatus.Status = runbooks.CommandFailed
+
+				if !step.ShouldIgnoreErrors(runbookAction) {
+					saveCheckpoint(step, []runbooks.CommandStatus{callStatus}, false)
+					return fmt.Errorf("error in called action %s: %w", step.Call, err)
+				}
+
+				log.Warnf("(Step %s/%d) %v", step.Describe(), numSteps, fmt.Errorf("error in called action %s: %w", step.Call, err))
+			}
+
+			saveCheckpoint(step, []runbooks.CommandStatus{callStatus}, true)
 			continue
 		}
 
-		log.Infof("Executing> %s", rawCmd)
-		resultChan := make(chan *commandResult, *maxConcurrency*2)
+		rawCmdLines, err := runbooks.RenderTemplates(stepCtx, templateName, step.Run, stringVars, variableDefinitions)
+
+		if err != nil {
+			e.report.AddStep(run.stepPrefix+step.ID, step.Name).Finish(err)
+			return err
+		}
+
+		if renderedSteps, ok := runbooks.ParseRenderedSteps(step, rawCmdLines); ok {
+			log.Tracef("Step %s is a Yaml array %s, inserting into stack", step.ID, strings.Join(rawCmdLines, "\n"))
+			newSteps := make([]runbooks.RunbookStep, 0, len(steps)+len(renderedSteps)-1)
+			newSteps = append(newSteps, steps[0:stepIdx]...)
+			newSteps = append(newSteps, renderedSteps...)
+			newSteps = append(newSteps, steps[stepIdx+1:]...)
+			steps = newSteps
+			stepIdx--
+			continue
+		}
+
+		timeout := e.execTimeout
+		if step.Timeout > 0 {
+			timeout = step.Timeout
+		}
+
+		log.Infof("Executing> %s", step.Run)
+		stepReport := e.report.AddStep(run.stepPrefix+step.ID, step.Name)
+		// A copy, as values are captured while commands are running
+		capturedValues := maps.Clone(run.captured)
+		resultChan := make(chan *commandResult, e.maxConcurrency*2)
 		funcs := make([]func(), 0, len(rawCmdLines))
+		stepCaptures := make([]*runbooks.CommandCaptures, 0, len(rawCmdLines))
+		commandStatuses := make([]runbooks.CommandStatus, 0, len(rawCmdLines))
 
 		for commandIdx, rawCmdLine := range rawCmdLines {
 
@@ -389,35 +882,106 @@ func processRunBookCommands(runbookName string, runbookStringArguments map[strin
 			rawCmdArguments, err := shellwords.SplitPosix(strings.Trim(rawCmdLine, " \n"))
 
 			if err != nil {
-				cancelFunc()
+				stepReport.Finish(err)
 				return err
 			}
 
-			funcs = append(funcs, func() {
+			rawCmdArguments, captures, err := runbooks.ExtractCaptures(rawCmdArguments)
 
-				log.Tracef("(Step %d/%d Command %d/%d) Building Commmand", stepIdx+1, numSteps, commandIdx+1, len(funcs))
+			if err != nil {
+				stepReport.Finish(err)
+				return err
+			}
 
-				stepCmdObject, err := objectPool.BorrowObject(ctx)
-				defer objectPool.ReturnObject(ctx, stepCmdObject)
+			commandReport := stepReport.AddCommand(commandIdx, rawCmdLine)
 
-				if err == nil {
-					commandAndResetFunc := stepCmdObject.(*CommandAndReset)
-					commandAndResetFunc.reset()
-					stepCmd := commandAndResetFunc.cmd
+			if checkpoint != nil && checkpoint.CommandSucceeded(step.ID, commandIdx, rawCmdLine) && len(captures) == 0 {
+				// Commands that capture values run again, as the values might not have been saved
+				log.Infof("(Step %s/%d Command %d) Skipping command that already succeeded [%s]", step.ID, numSteps, commandIdx+1, rawCmdLine)
+				commandReport.Skip("already succeeded")
+				commandStatuses = append(commandStatuses, runbooks.CommandStatus{Index: commandIdx, Command: rawCmdLine, Status: runbooks.CommandSucceeded})
+				continue
+			}
 
-					tweakedArguments := misc.AddImplicitDoubleDash(rawCmdArguments)
-					stepCmd.SetArgs(tweakedArguments[1:])
+			var commandCaptures *runbooks.CommandCaptures
+			if len(captures) > 0 {
+				commandCaptures = &runbooks.CommandCaptures{Captures: captures}
+				stepCaptures = append(stepCaptures, commandCaptures)
+			}
+
+			// The requests of all attempts are counted for the report
+			requestStats := &httpclient.RequestStats{}
+
+			runCommand := func(attempt int) error {
+				log.Tracef("(Step %s/%d Command %d/%d) Building Commmand", step.ID, numSteps, commandIdx+1, len(funcs))
+
+				stepCmdObject, err := e.objectPool.BorrowObject(ctx)
+				defer e.objectPool.ReturnObject(ctx, stepCmdObject)
+
+				if err != nil {
+					return err
+				}
+
+				commandAndResetFunc := stepCmdObject.(*CommandAndReset)
+				commandAndResetFunc.reset()
+				stepCmd := commandAndResetFunc.cmd
+
+				tweakedArguments := misc.AddImplicitDoubleDash(rawCmdArguments)
+				stepCmd.SetArgs(tweakedArguments[1:])
 
-					stepCmd.SilenceErrors = true
-					log.Tracef("(Step %d/%d Command %d/%d) Starting Command", stepIdx+1, numSteps, commandIdx+1, len(funcs))
+				stepCmd.SilenceErrors = true
+				log.Tracef("(Step %s/%d Command %d/%d) Starting Command", step.ID, numSteps, commandIdx+1, len(funcs))
 
-					stepCmd.ResetFlags()
-					err = stepCmd.ExecuteContext(ctx)
-					log.Tracef("(Step %d/%d Command %d/%d) Complete Command", stepIdx+1, numSteps, commandIdx+1, len(funcs))
+				stepCmd.ResetFlags()
+
+				commandLabels := labels(step.ID, commandIdx)
+				if attempt > 0 {
+					// Retries get new random values (e.g., so that a generated slug doesn't conflict again)
+					commandLabels = append(commandLabels, attempt)
 				}
 
+				// Commands use the seed of the step if it set one (e.g., {{ seed 1 }} before --auto-fill)
+				commandCtx := random.WithStream(ctx, stepStream.Derive(commandLabels...))
+				commandCtx = httpclient.WithRequestStats(commandCtx, requestStats)
+
+				commandCtx = runbooks.WithCapturedValues(commandCtx, capturedValues)
+
+				if commandCaptures != nil {
+					commandCtx = runbooks.WithCaptures(commandCtx, commandCaptures)
+				}
+
+				// Cobra only passes the context to a sub command that doesn't have one, and these are reused
+				if subCmd, _, err := stepCmd.Find(tweakedArguments[1:]); err == nil {
+					subCmd.SetContext(commandCtx)
+				}
+
+				err = stepCmd.ExecuteContext(commandCtx)
+				log.Tracef("(Step %s/%d Command %d/%d) Complete Command", step.ID, numSteps, commandIdx+1, len(funcs))
+
+				return err
+			}
+
+			funcs = append(funcs, func() {
+				commandReport.Start()
+				attempts := 1
+				err := runCommand(0)
+
+				for attempt := 1; err != nil && attempt <= step.Retries && !e.shuttingDown(); attempt++ {
+					log.Warnf("(Step %s/%d Command %d/%d) Retrying command (attempt %d/%d) after %s, error: %v", step.ID, numSteps, commandIdx+1, len(funcs), attempt, step.Retries, step.RetryDelay, err)
+
+					select {
+					case <-time.After(step.RetryDelay):
+					case <-ctx.Done():
+					}
+
+					attempts++
+					err = runCommand(attempt)
+				}
+
+				commandReport.Finish(attempts, requestStats.Total(), requestStats.StatusCodes(), err)
+
 				commandResult := &commandResult{
-					stepIdx:     stepIdx,
+					stepId:      step.ID,
 					commandIdx:  commandIdx,
 					commandLine: rawCmdLine,
 					error:       err,
@@ -437,18 +1001,18 @@ func processRunBookCommands(runbookName string, runbookStringArguments map[strin
 		go func() {
 			for idx, fn := range funcs {
 				idx := idx
-				if shutdown.ShutdownFlag.Load() {
+				if e.shuttingDown() {
 					log.Infof("Aborting runbook execution, after %d scheduled executions", idx)
-					cancelFunc()
+					e.cancelFunc()
 					break
 				}
 
 				fn := fn
 				log.Tracef("Run %d is waiting on semaphore", idx)
-				if err := concurrentRunSemaphore.Acquire(ctx, 1); err == nil {
+				if err := e.concurrentRunSemaphore.Acquire(ctx, 1); err == nil {
 					go func() {
 						log.Tracef("Run %d is starting", idx)
-						defer concurrentRunSemaphore.Release(1)
+						defer e.concurrentRunSemaphore.Release(1)
 						fn()
 					}()
 				} else {
@@ -461,19 +1025,32 @@ func processRunBookCommands(runbookName string, runbookStringArguments map[strin
 		for i := 0; i < len(funcs); i++ {
 			select {
 			case result := <-resultChan:
-				if !shutdown.ShutdownFlag.Load() {
+				if !e.shuttingDown() {
+					commandStatus := runbooks.CommandStatus{Index: result.commandIdx, Command: result.commandLine, Status: runbooks.CommandSucceeded}
+
 					if result.error != nil {
-						log.Warnf("(Step %d/%d Command %d/%d) %v", result.stepIdx+1, numSteps, result.commandIdx+1, len(funcs), fmt.Errorf("error processing command [%s], %w", result.commandLine, result.error))
+						commandStatus.Status = runbooks.CommandFailed
+						log.Warnf("(Step %s/%d Command %d/%d) %v", result.stepId, numSteps, result.commandIdx+1, len(funcs), fmt.Errorf("error processing command [%s], %w", result.commandLine, result.error))
 						errorCount++
 					} else {
-						log.Debugf("(Step %d/%d Command %d/%d) finished successfully ", result.stepIdx+1, numSteps, result.commandIdx+1, len(funcs))
+						log.Debugf("(Step %s/%d Command %d/%d) finished successfully ", result.stepId, numSteps, result.commandIdx+1, len(funcs))
 					}
+
+					commandStatuses = append(commandStatuses, commandStatus)
 				} else {
 					log.Tracef("Shutdown flag enabled, completion result %v", result)
-					cancelFunc()
+					e.cancelFunc()
 				}
-			case <-time.After(time.Duration(*execTimeoutInSeconds) * time.Second):
-				return fmt.Errorf("timeout of %d seconds reached, only %d of %d commands finished of step %d/%d", *execTimeoutInSeconds, i+1, len(funcs), stepIdx+1, numSteps)
+			case <-time.After(timeout):
+				saveCheckpoint(step, commandStatuses, false)
+				err := fmt.Errorf("timeout of %s reached, only %d of %d commands finished of step %s/%d", timeout, i+1, len(funcs), step.Describe(), numSteps)
+				stepReport.Finish(err)
+				return err
+			case <-ctx.Done():
+				saveCheckpoint(step, commandStatuses, false)
+				err := fmt.Errorf("runbook execution stopped (%w), only %d of %d commands finished of step %s/%d", ctx.Err(), i, len(funcs), step.Describe(), numSteps)
+				stepReport.Finish(err)
+				return err
 
 			}
 		}
@@ -482,67 +1059,224 @@ func processRunBookCommands(runbookName string, runbookStringArguments map[strin
 			log.Debugf("Running %d commands complete", len(funcs))
 		}
 
-		if !runbookAction.IgnoreErrors && errorCount > 0 {
-			return fmt.Errorf("error occurred while processing script aborting")
+		// Captures are added in the order of the commands, so the values don't depend on which command finished first
+		for _, commandCaptures := range stepCaptures {
+			for k, v := range commandCaptures.Values() {
+				run.addCapturedValue(k, v)
+			}
+		}
+
+		if !step.ShouldIgnoreErrors(runbookAction) && errorCount > 0 {
+			saveCheckpoint(step, commandStatuses, false)
+			err := fmt.Errorf("error occurred while processing script aborting")
+			stepReport.Finish(err)
+			return err
 		}
+
+		stepReport.Finish(nil)
+		saveCheckpoint(step, commandStatuses, true)
 	}
-	defer cancelFunc()
 	return nil
 }
 
-func processRunbookVariablesOnCommand(runbookActionRunActionCommand *cobra.Command, runbookStringArguments map[string]*string, variables map[string]runbooks.Variable, enableRequiredVars bool) {
-	for key, variable := range variables {
-		key := key
-		variable := variable
+// renderRunbookVariableDefaults renders the defaults of variables that weren't set again when there is a seed, as the flags were created before it was known
+// setRunbookVariablesFromCheckpoint sets the flags of the variables that weren't given to their values in the checkpoint
+func setRunbookVariablesFromCheckpoint(cmd *cobra.Command, checkpoint *runbooks.Checkpoint, variables map[string]runbooks.Variable) error {
+	// Sorted, so that errors are always reported in the same order
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
 
-		if variable.Required && enableRequiredVars {
+	sort.Strings(keys)
 
-			description := ""
-			if variable.Description != nil {
-				description = variable.Description.Short
-			}
-			runbookActionRunActionCommand.Flags().StringVar(runbookStringArguments[key], key, "", description)
-			err := runbookActionRunActionCommand.MarkFlagRequired(key)
+	for _, key := range keys {
+		value, ok := checkpoint.Variables[key]
+
+		if !ok || cmd.Flags().Changed(key) {
+			continue
+		}
+
+		values := []string{value}
+		if strings.HasPrefix(variables[key].Type, "LIST:") {
+			var err error
+			values, err = runbooks.SplitListValue(value)
 
 			if err != nil {
-				log.Errorf("Could not set flag as required, this is a bug of some kind %s: %v", key, err)
+				return fmt.Errorf("could not use the value of %s from the last run: %w", key, err)
 			}
-		} else {
-			description := ""
-			if variable.Description != nil {
-				description = variable.Description.Short
+		}
+
+		for _, v := range values {
+			if err := cmd.Flags().Set(key, v); err != nil {
+				return fmt.Errorf("could not use the value of %s from the last run: %w", key, err)
 			}
+		}
+	}
+
+	return nil
+}
+
+func renderRunbookVariableDefaults(cmd *cobra.Command, runbookName string, runbookAction *runbooks.RunbookAction, runbookStringArguments map[string]*string) {
+	if _, seeded := random.GetSeed(); !seeded {
+		return
+	}
 
+	ctx := random.WithStream(clictx.Ctx, random.NewStream(runbookName, runbookAction.Name, "variables"))
+
+	// Sorted, so that the values don't depend on the order of the map
+	keys := make([]string, 0, len(runbookAction.Variables))
+	for key := range runbookAction.Variables {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !cmd.Flags().Changed(key) {
+			*runbookStringArguments[key] = templates.RenderWithContext(ctx, runbookAction.Variables[key].Default)
+		}
+	}
+}
+
+func processRunbookVariablesOnCommand(runbookActionRunActionCommand *cobra.Command, runbookStringArguments map[string]*string, variables map[string]runbooks.Variable, enableRequiredVars bool) {
+	for key, variable := range variables {
+		key := key
+		variable := variable
+
+		description := ""
+		if variable.Description != nil {
+			description = variable.Description.Short
+		}
+
+		if variable.Required && enableRequiredVars {
+			*runbookStringArguments[key] = ""
+		} else {
 			// Add ENUM options to description
-			if strings.HasPrefix(variable.Type, "ENUM:") {
-				enumValues := strings.Split(variable.Type[5:], ",")
+			if enumType := strings.TrimPrefix(variable.Type, "LIST:"); strings.HasPrefix(enumType, "ENUM:") {
+				enumValues := strings.Split(enumType[5:], ",")
 				if description != "" {
 					description += ". "
 				}
 				description += "Options: [" + strings.Join(enumValues, ", ") + "]"
 			}
 
-			runbookActionRunActionCommand.Flags().StringVar(runbookStringArguments[key], key, templates.Render(variable.Default), description)
+			*runbookStringArguments[key] = templates.Render(variable.Default)
+		}
+
+		if strings.HasPrefix(variable.Type, "LIST:") {
+			if description != "" {
+				description += ". "
+			}
+			description += "Can be given more than once"
+		}
+
+		flag := runbookActionRunActionCommand.Flags().VarPF(&runbookVariableFlag{variable: variable, value: runbookStringArguments[key]}, key, "", description)
+
+		if variable.Type == "BOOL" {
+			flag.NoOptDefVal = "true"
+		}
+
+		if variable.Required && enableRequiredVars {
+			err := runbookActionRunActionCommand.MarkFlagRequired(key)
+
+			if err != nil {
+				log.Errorf("Could not set flag as required, this is a bug of some kind %s: %v", key, err)
+			}
 		}
 
 		runbookActionRunActionCommand.RegisterFlagCompletionFunc(key, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeRunbookVariable(strings.TrimPrefix(variable.Type, "LIST:"))
+		})
+	}
+}
 
-			if strings.HasPrefix(variable.Type, "RESOURCE_ID:") {
-				if resourceInfo, ok := resources.GetResourceByName(variable.Type[12:]); ok {
-					return completion.Complete(completion.Request{
-						Type:     completion.CompleteAlias,
-						Resource: resourceInfo,
-					})
+func completeRunbookVariable(variableType string) ([]string, cobra.ShellCompDirective) {
+	if strings.HasPrefix(variableType, "RESOURCE_ID:") {
+		if resourceInfo, ok := resources.GetResourceByName(variableType[12:]); ok {
+			return completion.Complete(completion.Request{
+				Type:     completion.CompleteAlias,
+				Resource: resourceInfo,
+			})
 
+		}
+	} else if strings.HasPrefix(variableType, "ENUM:") {
+		// Extract enum values from "ENUM:val1,val2,val3"
+		enumValues := strings.Split(variableType[5:], ",")
+		return enumValues, cobra.ShellCompDirectiveNoFileComp
+	} else if variableType == "BOOL" {
+		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
+	} else if variableType == "CURRENCY" {
+		// Prefer the currencies of the store, if we've seen any
+		if resourceInfo, ok := resources.GetResourceByName("currencies"); ok {
+			codes := map[string]struct{}{}
+			for _, v := range aliases.GetAliasesForJsonApiTypeAndAlternates(resourceInfo.JsonApiType, resourceInfo.AlternateJsonApiTypesForAliases) {
+				if v.Code != "" {
+					codes[v.Code] = struct{}{}
 				}
-			} else if strings.HasPrefix(variable.Type, "ENUM:") {
-				// Extract enum values from "ENUM:val1,val2,val3"
-				enumValues := strings.Split(variable.Type[5:], ",")
-				return enumValues, cobra.ShellCompDirectiveNoFileComp
 			}
-			return []string{}, cobra.ShellCompDirectiveNoFileComp
 
+			if len(codes) > 0 {
+				return slices.Sorted(maps.Keys(codes)), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+
+		return completion.Complete(completion.Request{
+			Type: completion.CompleteCurrency,
 		})
+	} else if variableType == "FILE" {
+		return []string{}, cobra.ShellCompDirectiveDefault
+	}
+
+	return []string{}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// runbookVariableFlag is the flag for a runbook variable, values are checked when the flag is parsed, and LIST variables can be given more than once
+type runbookVariableFlag struct {
+	variable runbooks.Variable
+	value    *string
+	changed  bool
+}
+
+func (f *runbookVariableFlag) String() string {
+	return *f.value
+}
+
+func (f *runbookVariableFlag) Set(value string) error {
+	if !strings.HasPrefix(f.variable.Type, "LIST:") {
+		if _, err := runbooks.ParseVariableValue(f.variable.Type, value); err != nil {
+			return err
+		}
+
+		*f.value = value
+		return nil
+	}
+
+	if _, err := runbooks.ParseVariableValue(f.variable.Type[5:], value); err != nil {
+		return err
+	}
+
+	// The first value replaces the default
+	var elements []string
+	if f.changed {
+		elements, _ = runbooks.SplitListValue(*f.value)
+	}
+
+	*f.value = runbooks.JoinListValue(append(elements, value))
+	f.changed = true
+
+	return nil
+}
+
+func (f *runbookVariableFlag) Type() string {
+	switch {
+	case f.variable.Type == "INT", f.variable.Type == "FLOAT", f.variable.Type == "BOOL", f.variable.Type == "CURRENCY",
+		f.variable.Type == "DATE", f.variable.Type == "DURATION", f.variable.Type == "JSON", f.variable.Type == "FILE":
+		return strings.ToLower(f.variable.Type)
+	case strings.HasPrefix(f.variable.Type, "LIST:"):
+		return "list"
+	default:
+		return "string"
 	}
 }
 
@@ -573,6 +1307,7 @@ func generateRunbookCmd() *CommandAndReset {
 	resetDeleteCmd := NewDeleteCommand(root)
 	resetGetCmd := NewGetCommand(root)
 	resetDeleteAllCmd := NewDeleteAllCommand(root)
+	resetAssertCmd := NewAssertCommand(root)
 	getDevCommands(root)
 
 	return &CommandAndReset{
@@ -584,10 +1319,41 @@ func generateRunbookCmd() *CommandAndReset {
 			resetDeleteCmd()
 			resetGetCmd()
 			resetDeleteAllCmd()
+			resetAssertCmd()
 		},
 	}
 }
 
+// The commands used to parse the flags of epcc commands when runbooks are validated
+var runbookLintCmd *CommandAndReset
+var runbookLintCmdLock sync.Mutex
+
+// parseRunbookCommandFlags parses the flags of an epcc command in a runbook (e.g., [create customer --auto-fill name Ron]), with the same commands that run it,
+// and returns the other arguments (e.g., [create customer name Ron])
+func parseRunbookCommandFlags(args []string) ([]string, error) {
+	runbookLintCmdLock.Lock()
+	defer runbookLintCmdLock.Unlock()
+
+	if runbookLintCmd == nil {
+		runbookLintCmd = generateRunbookCmd()
+	}
+
+	defer runbookLintCmd.reset()
+
+	c, rest, err := runbookLintCmd.cmd.Find(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ParseFlags(rest); err != nil {
+		return nil, err
+	}
+
+	// The command path is epcc followed by the verb and resource
+	return append(strings.Fields(c.CommandPath())[1:], c.Flags().Args()...), nil
+}
+
 func initRunbookDevCommands() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "dev",
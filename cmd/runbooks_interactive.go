@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buildkite/shellwords"
+	"github.com/elasticpath/epcc-cli/external/completion"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The most aliases that are shown when prompting for a RESOURCE_ID variable
+const maxPromptAliases = 10
+
+// shouldPromptForRunbookVariables returns whether to prompt for the variables of an action, which is what --interactive says,
+// or if it isn't set, whether stdin is a terminal and required variables are missing
+func shouldPromptForRunbookVariables(cmd *cobra.Command, interactive bool, variables map[string]runbooks.Variable) bool {
+	if cmd.Flags().Changed("interactive") {
+		return interactive
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false
+	}
+
+	for key, variable := range variables {
+		if variable.Required && !cmd.Flags().Changed(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// promptForRunbookVariables asks for the value of each variable that wasn't set on the command line (required variables first), and sets its flag
+func promptForRunbookVariables(cmd *cobra.Command, variables map[string]runbooks.Variable, in io.Reader, out io.Writer) error {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if variables[keys[i]].Required != variables[keys[j]].Required {
+			return variables[keys[i]].Required
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	reader := bufio.NewReader(in)
+
+	for _, key := range keys {
+		if cmd.Flags().Changed(key) {
+			continue
+		}
+
+		variable := variables[key]
+		elementType := strings.TrimPrefix(variable.Type, "LIST:")
+
+		fmt.Fprintf(out, "\n%s (%s)", key, variable.Type)
+		if variable.Description != nil && variable.Description.Short != "" {
+			fmt.Fprintf(out, ": %s", variable.Description.Short)
+		}
+		fmt.Fprintln(out)
+
+		var choices []string
+		if strings.HasPrefix(elementType, "ENUM:") {
+			choices = strings.Split(elementType[5:], ",")
+
+			for idx, choice := range choices {
+				fmt.Fprintf(out, "  %d) %s\n", idx+1, choice)
+			}
+		} else if strings.HasPrefix(elementType, "RESOURCE_ID:") {
+			if resourceInfo, ok := resources.GetResourceByName(elementType[12:]); ok {
+				aliases, _ := completion.Complete(completion.Request{
+					Type:     completion.CompleteAlias,
+					Resource: resourceInfo,
+				})
+
+				if len(aliases) > maxPromptAliases {
+					fmt.Fprintf(out, "  Aliases: %s (and %d more)\n", strings.Join(aliases[:maxPromptAliases], ", "), len(aliases)-maxPromptAliases)
+				} else if len(aliases) > 0 {
+					fmt.Fprintf(out, "  Aliases: %s\n", strings.Join(aliases, ", "))
+				}
+			}
+		}
+
+		defaultValue := cmd.Flags().Lookup(key).DefValue
+
+		for {
+			if strings.HasPrefix(variable.Type, "LIST:") {
+				fmt.Fprint(out, "Values, separated by commas")
+			} else {
+				fmt.Fprint(out, "Value")
+			}
+
+			if variable.Required {
+				fmt.Fprint(out, " (required): ")
+			} else {
+				fmt.Fprintf(out, " [%s]: ", defaultValue)
+			}
+
+			line, err := reader.ReadString('\n')
+
+			if err != nil && (err != io.EOF || line == "") {
+				return fmt.Errorf("could not read a value for %s: %w", key, err)
+			}
+
+			line = strings.TrimSpace(line)
+
+			if line == "" {
+				if variable.Required {
+					fmt.Fprintf(out, "A value is required for %s\n", key)
+					continue
+				}
+
+				// The default is used
+				break
+			}
+
+			values := []string{line}
+			if strings.HasPrefix(variable.Type, "LIST:") {
+				values = strings.Split(line, ",")
+			}
+
+			for idx, value := range values {
+				value = strings.TrimSpace(value)
+
+				// Choices can be given by number, unless the number is a choice (e.g., ENUM:3,2,1)
+				if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= len(choices) && !slices.Contains(choices, value) {
+					value = choices[n-1]
+				}
+
+				values[idx] = value
+			}
+
+			// All the values are checked before any is set, so that a list isn't partially set
+			value := values[0]
+			if strings.HasPrefix(variable.Type, "LIST:") {
+				value = runbooks.JoinListValue(values)
+			}
+
+			if _, err := runbooks.ParseVariableValue(variable.Type, value); err != nil {
+				fmt.Fprintf(out, "Invalid value: %v\n", err)
+				continue
+			}
+
+			for _, value := range values {
+				if err := cmd.Flags().Set(key, value); err != nil {
+					return err
+				}
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// nonInteractiveCommandLine returns a command line that runs the command again with the same flags, without prompting
+func nonInteractiveCommandLine(cmd *cobra.Command) string {
+	words := []string{cmd.CommandPath()}
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == "interactive" {
+			return
+		}
+
+		var values []string
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			values = sliceValue.GetSlice()
+		} else if variableFlag, ok := flag.Value.(*runbookVariableFlag); ok && strings.HasPrefix(variableFlag.variable.Type, "LIST:") {
+			values, _ = runbooks.SplitListValue(variableFlag.String())
+		} else {
+			values = []string{flag.Value.String()}
+		}
+
+		for _, value := range values {
+			if flag.NoOptDefVal != "" && value == flag.NoOptDefVal {
+				words = append(words, "--"+flag.Name)
+			} else if flag.NoOptDefVal != "" {
+				words = append(words, "--"+flag.Name+"="+shellwords.Quote(value))
+			} else {
+				words = append(words, "--"+flag.Name, shellwords.Quote(value))
+			}
+		}
+	})
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptForRunbookVariablesSetsTheFlagsThatWereNotGiven(t *testing.T) {
+	// Fixture Setup
+	variables := map[string]runbooks.Variable{
+		"name":  {Name: "name", Type: "STRING", Required: true, Description: &runbooks.RunbookDescription{Short: "The name of the customer"}},
+		"size":  {Name: "size", Type: "ENUM:small,large", Default: "small"},
+		"count": {Name: "count", Type: "INT", Default: "1"},
+		"tags":  {Name: "tags", Type: "LIST:STRING", Default: "a"},
+	}
+
+	rootCmd := &cobra.Command{Use: "epcc"}
+	actionCmd := &cobra.Command{Use: "action"}
+	rootCmd.AddCommand(actionCmd)
+
+	runbookStringArguments := runbooks.CreateMapForRunbookArgumentPointers(&runbooks.RunbookAction{Variables: variables})
+	processRunbookVariablesOnCommand(actionCmd, runbookStringArguments, variables, true)
+	require.NoError(t, actionCmd.ParseFlags([]string{"--count", "3"}))
+
+	// The name is required, and the first size isn't valid
+	in := strings.NewReader("\nRon Swanson\nmedium\n2\nx, y\n")
+	out := &bytes.Buffer{}
+
+	// Execute SUT
+	err := promptForRunbookVariables(actionCmd, variables, in, out)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, "Ron Swanson", *runbookStringArguments["name"])
+	require.Equal(t, "large", *runbookStringArguments["size"])
+	require.Equal(t, "3", *runbookStringArguments["count"])
+	require.Equal(t, `["x","y"]`, *runbookStringArguments["tags"])
+
+	require.Contains(t, out.String(), "name (STRING): The name of the customer")
+	require.Contains(t, out.String(), "A value is required for name")
+	require.Contains(t, out.String(), "  2) large")
+	require.Contains(t, out.String(), "Invalid value: value \"medium\" is not a valid enum option")
+	require.NotContains(t, out.String(), "count (INT)")
+
+	require.Equal(t, `epcc action --count 3 --name "Ron Swanson" --size large --tags x --tags y`, nonInteractiveCommandLine(actionCmd))
+}
+
+func TestPromptForRunbookVariablesUsesNumbersThatAreChoicesAsValues(t *testing.T) {
+	// Fixture Setup
+	variables := map[string]runbooks.Variable{
+		"priority": {Name: "priority", Type: "ENUM:3,2,1", Default: "2"},
+		"size":     {Name: "size", Type: "ENUM:10,20,small", Default: "small"},
+	}
+
+	rootCmd := &cobra.Command{Use: "epcc"}
+	actionCmd := &cobra.Command{Use: "action"}
+	rootCmd.AddCommand(actionCmd)
+
+	runbookStringArguments := runbooks.CreateMapForRunbookArgumentPointers(&runbooks.RunbookAction{Variables: variables})
+	processRunbookVariablesOnCommand(actionCmd, runbookStringArguments, variables, true)
+
+	in := strings.NewReader("1\n3\n")
+	out := &bytes.Buffer{}
+
+	// Execute SUT
+	err := promptForRunbookVariables(actionCmd, variables, in, out)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, "1", *runbookStringArguments["priority"])
+	require.Equal(t, "small", *runbookStringArguments["size"])
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/config"
+	"github.com/elasticpath/epcc-cli/external/httpclient"
+	"github.com/elasticpath/epcc-cli/external/runbooks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintRunbookChecksCommandsAgainstTheResources(t *testing.T) {
+	// Fixture Setup
+	runbooks.SetCommandFlagParser(parseRunbookCommandFlags)
+	t.Cleanup(func() { runbooks.SetCommandFlagParser(nil) })
+
+	err := runbooks.AddRunbookFromYaml(`
+name: lint-test-runbook
+description:
+  short: "A runbook with mistakes"
+actions:
+  create-things:
+    commands:
+      - |
+        epcc create customer name Ron email ron@example.com --auto-fill
+        epcc create customer-address name=Ron name Home
+        epcc create customer-address
+        epcc create customer-adress name=Ron name Home
+        epcc get customers --not-a-flag
+        epcc create custom-field name=Size name Size field_type text
+        epcc create custom-field name=Size name Color field_type integer validation.string.min_length 1
+        epcc create pcm-hierarchy name Leslie favourite_colour blue
+        epcc get account name=Parks
+`)
+	require.NoError(t, err)
+	runbook := runbooks.GetRunbooks()["lint-test-runbook"]
+
+	// Execute SUT
+	problems, err := runbooks.LintRunbook(&runbook)
+
+	// Verification
+	require.NoError(t, err)
+
+	var errs, warnings []string
+	for _, problem := range problems {
+		if problem.Warning {
+			warnings = append(warnings, problem.Error())
+		} else {
+			errs = append(errs, problem.Error())
+		}
+	}
+
+	require.ElementsMatch(t, []string{
+		"lint-test-runbook/create-things step 1 line 3: create customer-address needs 1 id(s) (customer), but got 0",
+		"lint-test-runbook/create-things step 1 line 4: unknown resource customer-adress",
+		"lint-test-runbook/create-things step 1 line 5: unknown flag: --not-a-flag",
+	}, errs)
+
+	require.ElementsMatch(t, []string{
+		"lint-test-runbook/create-things step 1 line 6: alias name=Size is for a custom-api, but no earlier command in the runbook creates or retrieves one",
+		"lint-test-runbook/create-things step 1 line 6: field_type text is not one of [string,integer,boolean,float,any,list]",
+		"lint-test-runbook/create-things step 1 line 7: alias name=Size is for a custom-api, but no earlier command in the runbook creates or retrieves one",
+		"lint-test-runbook/create-things step 1 line 7: validation.string.min_length is only an attribute of custom-field when field_type == \"string\"",
+		"lint-test-runbook/create-things step 1 line 8: favourite_colour is not a known attribute of pcm-hierarchy",
+		"lint-test-runbook/create-things step 1 line 9: alias name=Parks is for a account, but no earlier command in the runbook creates or retrieves one",
+	}, warnings)
+}
+
+func TestLintRunbookAllowsFlowFieldsGuardedAliasesAndIgnoredAttributes(t *testing.T) {
+	// Fixture Setup
+	runbooks.SetCommandFlagParser(parseRunbookCommandFlags)
+	t.Cleanup(func() { runbooks.SetCommandFlagParser(nil) })
+
+	err := runbooks.AddRunbookFromYaml(`
+name: lint-allowed-test-runbook
+description:
+  short: "A runbook without mistakes"
+actions:
+  create-things:
+    commands:
+      - |
+        epcc create customer name Ron email ron@example.com favourite_colour blue
+        epcc delete account name=Parks --if-alias-exists name=Parks
+      - run: epcc create account name Leslie favourite_colour blue
+        lint_ignore_attributes:
+          - favourite_colour
+`)
+	require.NoError(t, err)
+	runbook := runbooks.GetRunbooks()["lint-allowed-test-runbook"]
+
+	// Execute SUT
+	problems, err := runbooks.LintRunbook(&runbook)
+
+	// Verification
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+func TestBuiltInRunbooksValidateWithStrict(t *testing.T) {
+	// Fixture Setup
+	runbooks.SetCommandFlagParser(parseRunbookCommandFlags)
+	t.Cleanup(func() { runbooks.SetCommandFlagParser(nil) })
+	runbooks.Reset()
+	runbooks.InitializeBuiltInRunbooks()
+
+	validateCmd := initRunbookValidateCommands()
+	validateCmd.SetArgs([]string{"--strict"})
+
+	// Execute SUT
+	err := validateCmd.Execute()
+
+	// Verification
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(runbooks.GetRunbookNames()), 1, "Expected that some runbooks should be loaded.")
+}
+
+func TestRunbookStepWithSeedAutoFillsTheSameValuesEveryRun(t *testing.T) {
+	// Fixture Setup
+	var bodies []string
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		bodies = append(bodies, string(body))
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"123","type":"customer"}}`))
+	}))
+	defer server.Close()
+
+	originalEnv := config.GetEnv()
+	config.SetEnv(&config.Env{EPCC_API_BASE_URL: server.URL})
+	t.Cleanup(func() { config.SetEnv(originalEnv) })
+	httpclient.Initialize(1, 60, 0)
+
+	err := runbooks.AddRunbookFromYaml(`
+name: seed-test-runbook
+actions:
+  create-customer:
+    commands:
+      - |
+        {{ seed 42 }}
+        epcc create customer --auto-fill
+`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		runbooks.Reset()
+		runbooks.InitializeBuiltInRunbooks()
+	})
+
+	runbookAction := runbooks.GetRunbooks()["seed-test-runbook"].RunbookActions["create-customer"]
+	maxConcurrency := 1
+	execTimeout := int64(60)
+	cleanupTimeout := int64(60)
+	noReport := ""
+
+	// Execute SUT
+	for i := 0; i < 2; i++ {
+		err = processRunBookCommands("seed-test-runbook", map[string]*string{}, runbookAction, &maxConcurrency, &execTimeout, &cleanupTimeout, nil, runbooks.StepRange{}, runbookReportFiles{json: &noReport, junit: &noReport})
+		require.NoError(t, err)
+	}
+
+	// Verification
+	require.Len(t, bodies, 2)
+	require.Contains(t, bodies[0], "email")
+	require.Equal(t, bodies[0], bodies[1])
+}
+
+func TestRunbookResumeUsesTheRequiredVariablesOfTheLastRun(t *testing.T) {
+	// Fixture Setup
+	err := runbooks.AddRunbookFromYaml(`
+name: resume-test-runbook
+description:
+  short: "A runbook to resume"
+actions:
+  create-customer:
+    description:
+      short: "Create a customer"
+    variables:
+      name:
+        type: STRING
+        required: true
+    commands:
+      - epcc create customer name "{{ .name }}" email ron@example.com
+`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		runbooks.Reset()
+		runbooks.InitializeBuiltInRunbooks()
+	})
+
+	// The step already ran, so resuming doesn't run anything
+	checkpoint := runbooks.NewCheckpoint("resume-test-runbook", "create-customer")
+	checkpoint.Variables["name"] = "Ron"
+	checkpoint.LastCompletedStep = "1"
+	require.NoError(t, checkpoint.Save())
+	t.Cleanup(checkpoint.Delete)
+
+	runCmd := initRunbookRunCommands()
+	runCmd.SetArgs([]string{"resume-test-runbook", "create-customer", "--resume"})
+
+	// Execute SUT
+	err = runCmd.Execute()
+
+	// Verification
+	require.NoError(t, err)
+}
@@ -49,7 +49,13 @@ func NewUpdateCommand(parentCmd *cobra.Command) func() {
 	var logOnSuccess = ""
 	var logOnFailure = ""
 	var disableConstants = false
+	var noValidate = false
+	var merge = false
+	var unset []string
+	var showDiff = false
 	var data = ""
+	var responseFormat = ResponseFormatJson
+	var columns []string
 
 	resetFunc := func() {
 		overrides.QueryParameters = nil
@@ -67,7 +73,13 @@ func NewUpdateCommand(parentCmd *cobra.Command) func() {
 		logOnSuccess = ""
 		logOnFailure = ""
 		disableConstants = false
+		noValidate = false
+		merge = false
+		unset = nil
+		showDiff = false
 		data = ""
+		responseFormat = ResponseFormatJson
+		columns = nil
 	}
 
 	var updateCmd = &cobra.Command{
@@ -141,12 +153,24 @@ func NewUpdateCommand(parentCmd *cobra.Command) func() {
 						}
 					}
 
-					body, err := rest.UpdateInternal(clictx.Ctx, overrides, skipAliases, disableConstants, append([]string{resourceName}, args...), data)
+					var mergeOptions *rest.UpdateMergeOptions
+					if merge || len(unset) > 0 || showDiff {
+						mergeOptions = &rest.UpdateMergeOptions{
+							Unset:    unset,
+							ShowDiff: showDiff,
+						}
+					}
+
+					body, err := rest.UpdateInternal(commandContext(cmd), overrides, skipAliases, disableConstants, noValidate, mergeOptions, append([]string{resourceName}, args...), data)
 
 					if err != nil {
 						return err
 					}
 
+					if err := captureOutput(cmd, body); err != nil {
+						return err
+					}
+
 					if outputJq != "" {
 						output, err := json.RunJQOnStringWithArray(outputJq, body)
 
@@ -173,6 +197,8 @@ func NewUpdateCommand(parentCmd *cobra.Command) func() {
 
 					if noBodyPrint {
 						return nil
+					} else if ok, err := printBodyInResponseFormat(body, responseFormat, columns, resource); ok {
+						return err
 					} else if outputKeyVal {
 						return json.PrintJsonAsKeyValue(body)
 					} else {
@@ -302,16 +328,25 @@ func NewUpdateCommand(parentCmd *cobra.Command) func() {
 	updateCmd.PersistentFlags().StringVarP(&ifAliasDoesNotExist, "if-alias-does-not-exist", "", "", "If the alias does not exist we will run this command, otherwise exit with no error")
 	updateCmd.MarkFlagsMutuallyExclusive("if-alias-exists", "if-alias-does-not-exist")
 	updateCmd.PersistentFlags().BoolVarP(&skipAliases, "skip-alias-processing", "", false, "if set, we don't process the response for aliases")
-	_ = updateCmd.RegisterFlagCompletionFunc("output-jq", jqCompletionFunc)
+	_ = updateCmd.RegisterFlagCompletionFunc("output-jq", getJqCompletionFunc(completion.Update))
 	updateCmd.PersistentFlags().Uint32VarP(&repeat, "repeat", "", 1, "Number of times to repeat the command")
 	updateCmd.PersistentFlags().Uint32VarP(&repeatDelay, "repeat-delay", "", 100, "Delay (in ms) between repeats")
 
 	updateCmd.PersistentFlags().BoolVarP(&disableConstants, "no-auto-constants", "", false, "Disable setting of known constant values in the request body (e.g., `type`)")
+	updateCmd.PersistentFlags().BoolVarP(&noValidate, "no-validate", "", false, "Don't check arguments against the attribute types and OpenAPI request schema before sending the request")
+	updateCmd.PersistentFlags().BoolVarP(&merge, "merge", "", false, "Get the current state of the entity and apply the arguments on top of it, for endpoints where an update replaces the entire entity")
+	updateCmd.PersistentFlags().StringSliceVarP(&unset, "unset", "", []string{}, "Remove a key (and anything nested under it) from the current state of the entity (implies --merge)")
+	updateCmd.PersistentFlags().BoolVarP(&showDiff, "show-diff", "", false, "Print the changes that will be made to the entity before sending the request (implies --merge)")
 	updateCmd.PersistentFlags().StringVarP(&logOnSuccess, "log-on-success", "", "", "Output the following message as an info if the result is successful")
 	updateCmd.PersistentFlags().StringVarP(&logOnFailure, "log-on-failure", "", "", "Output the following message as an error if the result fails")
 	updateCmd.PersistentFlags().StringVarP(&data, "data", "d", "", "Raw JSON data to use as the request body. If provided, positional arguments will be ignored.")
 
 	updateCmd.MarkFlagsMutuallyExclusive("output-key-val", "output-jq", "silent", "compact")
+	addResponseFormatFlags(updateCmd, &responseFormat, &columns)
+	updateCmd.MarkFlagsMutuallyExclusive("output", "output-jq")
+	updateCmd.MarkFlagsMutuallyExclusive("merge", "data")
+	updateCmd.MarkFlagsMutuallyExclusive("unset", "data")
+	updateCmd.MarkFlagsMutuallyExclusive("show-diff", "data")
 	parentCmd.AddCommand(updateCmd)
 
 	return resetFunc
@@ -10,11 +10,13 @@ type Env struct {
 	EPCC_CLI_RATE_LIMIT                 uint16   `env:"EPCC_CLI_RATE_LIMIT"`
 	EPCC_CLI_SUPPRESS_NO_AUTH_MESSAGES  bool     `env:"EPCC_CLI_SUPPRESS_NO_AUTH_MESSAGES"`
 	EPCC_RUNBOOK_DIRECTORY              string   `env:"EPCC_RUNBOOK_DIRECTORY"`
+	EPCC_RESOURCE_DIRECTORY             string   `env:"EPCC_RESOURCE_DIRECTORY"`
 	EPCC_DISABLE_LEGACY_RESOURCES       bool     `env:"EPCC_DISABLE_LEGACY_RESOURCES"`
 	EPCC_CLI_DISABLE_RESOURCES          []string `env:"EPCC_CLI_DISABLE_RESOURCES" envSeparator:","`
 	EPCC_CLI_DISABLE_TEMPLATE_EXECUTION bool     `env:"EPCC_CLI_DISABLE_TEMPLATE_EXECUTION"`
 	EPCC_CLI_DISABLE_HTTP_LOGGING       bool     `env:"EPCC_CLI_DISABLE_HTTP_LOGGING"`
 	EPCC_CLI_READ_ONLY                  bool     `env:"EPCC_CLI_READ_ONLY"`
+	EPCC_CLI_SEED                       string   `env:"EPCC_CLI_SEED"`
 }
 
 var env = atomic.Pointer[Env]{}
@@ -7,6 +7,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -135,22 +136,39 @@ func getAliasesForSingleJsonApiType(jsonApiType string) map[string]*id.IdableAtt
 	return aliasMap
 }
 
-func ResolveAliasValuesOrReturnIdentity(jsonApiType string, alternateJsonApiTypes []string, aliasName string, attribute string) string {
-	splitAlias := strings.Split(aliasName, "/")
+// ParseFullyQualifiedAlias splits an alias of the form alias/<type>/<alias name>/<attribute>.
+// The alias name is allowed to contain a / since values like names frequently do.
+func ParseFullyQualifiedAlias(s string) (jsonApiType string, aliasName string, attribute string, ok bool) {
+	if !strings.HasPrefix(s, "alias/") {
+		return "", "", "", false
+	}
+
+	rest := strings.TrimPrefix(s, "alias/")
+	typeIdx := strings.Index(rest, "/")
+	attributeIdx := strings.LastIndex(rest, "/")
 
-	if len(splitAlias) == 2 {
-		//
-		alternateJsonApiTypes = append(alternateJsonApiTypes, splitAlias[0])
-		aliasName = splitAlias[1]
+	if typeIdx <= 0 || attributeIdx <= typeIdx+1 || attributeIdx == len(rest)-1 {
+		return "", "", "", false
 	}
 
-	if len(splitAlias) == 4 {
-		if splitAlias[0] == "alias" {
-			return ResolveAliasValuesOrReturnIdentity(splitAlias[1], []string{}, splitAlias[2], splitAlias[3])
-		}
+	return rest[0:typeIdx], rest[typeIdx+1 : attributeIdx], rest[attributeIdx+1:], true
+}
+
+func ResolveAliasValuesOrReturnIdentity(jsonApiType string, alternateJsonApiTypes []string, aliasName string, attribute string) string {
+	if aliasType, name, aliasAttribute, ok := ParseFullyQualifiedAlias(aliasName); ok {
+		return ResolveAliasValuesOrReturnIdentity(aliasType, []string{}, name, aliasAttribute)
 	}
 
-	if result, ok := GetAliasesForJsonApiTypeAndAlternates(jsonApiType, alternateJsonApiTypes)[aliasName]; ok {
+	nameToLookup := aliasName
+
+	// A type prefixed alias (e.g., customer/name=John Smith), the value of an alias can contain a / so we only
+	// treat it as a type if the prefix isn't part of the key=value.
+	if idx := strings.Index(aliasName, "/"); idx > 0 && !strings.Contains(aliasName[0:idx], "=") {
+		alternateJsonApiTypes = append(alternateJsonApiTypes, aliasName[0:idx])
+		nameToLookup = aliasName[idx+1:]
+	}
+
+	if result, ok := GetAliasesForJsonApiTypeAndAlternates(jsonApiType, alternateJsonApiTypes)[nameToLookup]; ok {
 
 		if attribute == "id" {
 			return result.Id
@@ -167,12 +185,39 @@ func ResolveAliasValuesOrReturnIdentity(jsonApiType string, alternateJsonApiType
 			return result.Code
 		}
 
-		log.Warnf("Alias was found for for %s, but the attribute is unknown, must be one of {id, slug, sku, code}, but got %s", aliasName, attribute)
+		log.Warnf("Alias was found for for %s, but the attribute is unknown, must be one of {id, slug, sku, code}, but got %s", nameToLookup, attribute)
 
 	}
 	return aliasName
 }
 
+// The attributes of alias names in order of preference, when picking an alias for an id
+var aliasAttributePreference = []string{"name", "sku", "slug", "code", "email", "external_ref"}
+
+// GetBestAliasForId returns the most human readable alias for an id (e.g., name=John Smith), or false if the id doesn't have any alias other than
+// the id itself.
+func GetBestAliasForId(jsonApiType string, alternateJsonApiTypes []string, idStr string) (string, bool) {
+	candidates := make([]string, 0)
+
+	for aliasName, v := range GetAliasesForJsonApiTypeAndAlternates(jsonApiType, alternateJsonApiTypes) {
+		if v.Id == idStr && v.AlternateFor == "" {
+			candidates = append(candidates, aliasName)
+		}
+	}
+
+	sort.Strings(candidates)
+
+	for _, attribute := range aliasAttributePreference {
+		for _, c := range candidates {
+			if strings.HasPrefix(c, attribute+"=") {
+				return c, true
+			}
+		}
+	}
+
+	return "", false
+}
+
 func SaveAliasesForResources(jsonTxt string) {
 	var jsonStruct = map[string]interface{}{}
 	err := json.Unmarshal([]byte(jsonTxt), &jsonStruct)
@@ -218,7 +263,7 @@ func SetAliasForResource(jsonTxt string, name string) {
 							}
 
 							for aliasKey, aliasValue := range aliases {
-								results[typeKeyValue][aliasKey] = aliasValue
+								addAlias(typeKeyValue, results[typeKeyValue], aliasKey, aliasValue)
 							}
 						}
 					}
@@ -243,8 +288,10 @@ func SetAliasForResource(jsonTxt string, name string) {
 	for resourceType, foundAliases := range results {
 		for _, v := range foundAliases {
 			// We may have found multiple aliases, but lets just save one
+			namedAlias := *v
+			namedAlias.AlternateFor = ""
 			saveAliasesForResource(resourceType, map[string]*id.IdableAttributes{
-				name: v,
+				name: &namedAlias,
 			})
 			return
 		}
@@ -306,10 +353,40 @@ func modifyAliases(jsonApiType string, fn func(map[string]*id.IdableAttributes,
 
 }
 
+// addAlias adds an alias to a set of found aliases, if two resources produce the same alias name, an
+// alias from the actual value of an attribute wins over the legacy alternate of another.
+func addAlias(jsonApiType string, foundAliases map[string]*id.IdableAttributes, aliasName string, value *id.IdableAttributes) {
+	if existing, ok := foundAliases[aliasName]; ok && existing.Id != value.Id {
+		if value.AlternateFor != "" && existing.AlternateFor == "" {
+			log.Warnf("Alias %s for %s with id %s collides with an alias for id %s, use %s instead", aliasName, jsonApiType, value.Id, existing.Id, value.AlternateFor)
+			return
+		}
+
+		if value.AlternateFor == "" && existing.AlternateFor != "" {
+			log.Warnf("Alias %s for %s with id %s collides with an alias for id %s, use %s instead", aliasName, jsonApiType, existing.Id, value.Id, existing.AlternateFor)
+		}
+	}
+
+	foundAliases[aliasName] = value
+}
+
 // This function saves all the aliases for a specific resource.
 func saveAliasesForResource(jsonApiType string, newAliases map[string]*id.IdableAttributes) {
 
 	modifyAliases(jsonApiType, func(aliasMap map[string]*id.IdableAttributes, aliasesById map[string]map[string]bool) {
+		// Legacy alternate aliases (e.g., name=John_Smith for name=John Smith) never replace an alias for a different resource
+		// that actually has that value, but an actual value will replace a legacy alternate.
+		for newAliasName, newAliasReferencedId := range newAliases {
+			if oldAlias, ok := aliasMap[newAliasName]; ok && oldAlias.Id != newAliasReferencedId.Id {
+				if newAliasReferencedId.AlternateFor != "" && oldAlias.AlternateFor == "" {
+					log.Warnf("Alias %s for %s with id %s collides with an existing alias for id %s, use %s instead", newAliasName, jsonApiType, newAliasReferencedId.Id, oldAlias.Id, newAliasReferencedId.AlternateFor)
+					delete(newAliases, newAliasName)
+				} else if newAliasReferencedId.AlternateFor == "" && oldAlias.AlternateFor != "" {
+					log.Warnf("Alias %s for %s previously referred to id %s (as an alternate for %s) and now refers to id %s", newAliasName, jsonApiType, oldAlias.Id, oldAlias.AlternateFor, newAliasReferencedId.Id)
+				}
+			}
+		}
+
 		// Aliases have the format KEY=VALUE and this maps to an ID.
 		// This code checks for where two aliases have the same KEY and same ID, and replaces the old value, with the new one.
 		// This happens in cases where we store a name like "name=John_Smith" and then the user renames it to "name=Jane_Doe".
@@ -388,7 +465,7 @@ func visitResources(data map[string]interface{}, prefix string, parentAliasType
 					}
 
 					for aliasKey, aliasValue := range aliases {
-						results[typeKeyValue][aliasKey] = aliasValue
+						addAlias(typeKeyValue, results[typeKeyValue], aliasKey, aliasValue)
 					}
 				}
 			}
@@ -449,7 +526,11 @@ func generateAliasesForStruct(prefix string, parentAliasType string, parentAlias
 		//related_buz_for_foo_id_123
 		keyPrefix := "related_" + matches[1] + "_for_" + parentAliasType + "_"
 
-		for k := range parentAliases {
+		for k, v := range parentAliases {
+			if v.AlternateFor != "" {
+				// Filled in below once the result is complete
+				continue
+			}
 			results[keyPrefix+k] = &result
 		}
 
@@ -496,15 +577,35 @@ func generateAliasesForStruct(prefix string, parentAliasType string, parentAlias
 
 	}
 
+	// Older versions replaced spaces with underscores in alias names, so we keep that form around as an alternate
+	// to not break existing runbooks and scripts.
+	aliasNames := make([]string, 0, len(results))
+	for aliasName := range results {
+		aliasNames = append(aliasNames, aliasName)
+	}
+
+	for _, aliasName := range aliasNames {
+		if legacyAliasName := GetLegacyAliasName(aliasName); legacyAliasName != aliasName {
+			if _, ok := results[legacyAliasName]; !ok {
+				alternate := result
+				alternate.AlternateFor = aliasName
+				results[legacyAliasName] = &alternate
+			}
+		}
+	}
+
 	return results
 }
 
+// GetLegacyAliasName returns the alias name as it would have been generated by older versions (i.e., with spaces replaced by underscores).
+func GetLegacyAliasName(aliasName string) string {
+	return strings.ReplaceAll(aliasName, " ", "_")
+}
+
 func getAliasForKey(key string, data map[string]interface{}) string {
 	if val, ok := data[key]; ok {
 		if strVal, ok := val.(string); ok {
-			// TODO not sure why spaces don't work with auto completes.
-			retVal := strings.ReplaceAll(fmt.Sprintf("%s=%s", key, strVal), " ", "_")
-			return retVal
+			return fmt.Sprintf("%s=%s", key, strVal)
 		} else {
 			return ""
 		}
@@ -566,10 +566,14 @@ func TestSavedAliasIsReturnedForANameInLegacyObjectResponse(t *testing.T) {
 
 	// Verification
 
-	require.Len(t, aliases, 3, "There should be %d typeToAliasNameToIdMap in map not %d", 3, len(aliases))
+	require.Len(t, aliases, 4, "There should be %d typeToAliasNameToIdMap in map not %d", 4, len(aliases))
+
+	require.Contains(t, aliases, "name=Test Testerson")
+	require.Equal(t, "123", aliases["name=Test Testerson"].Id)
 
 	require.Contains(t, aliases, "name=Test_Testerson")
 	require.Equal(t, "123", aliases["name=Test_Testerson"].Id)
+	require.Equal(t, "name=Test Testerson", aliases["name=Test_Testerson"].AlternateFor)
 
 	require.Contains(t, aliases, "id=123")
 	require.Equal(t, "123", aliases["id=123"].Id)
@@ -822,10 +826,14 @@ func TestSavedAliasIsReturnedForANameInComplaintObjectResponse(t *testing.T) {
 
 	// Verification
 
-	require.Len(t, aliases, 3, "There should be %d typeToAliasNameToIdMap in map not %d", 3, len(aliases))
+	require.Len(t, aliases, 4, "There should be %d typeToAliasNameToIdMap in map not %d", 4, len(aliases))
+
+	require.Contains(t, aliases, "name=Test Testerson")
+	require.Equal(t, "123", aliases["name=Test Testerson"].Id)
 
 	require.Contains(t, aliases, "name=Test_Testerson")
 	require.Equal(t, "123", aliases["name=Test_Testerson"].Id)
+	require.Equal(t, "name=Test Testerson", aliases["name=Test_Testerson"].AlternateFor)
 
 	require.Contains(t, aliases, "id=123")
 	require.Equal(t, "123", aliases["id=123"].Id)
@@ -876,7 +884,7 @@ func TestSavedAliasIsReturnedForARelationshipObjectInArrayResponse(t *testing.T)
 
 	aliases := GetAliasesForJsonApiTypeAndAlternates("bar", []string{})
 
-	require.Len(t, aliases, 8, "There should be %d typeToAliasNameToIdMap in map not %d", 8, len(aliases))
+	require.Len(t, aliases, 10, "There should be %d typeToAliasNameToIdMap in map not %d", 10, len(aliases))
 
 	require.Contains(t, aliases, "id=abc")
 	require.Equal(t, "abc", aliases["id=abc"].Id)
@@ -887,6 +895,9 @@ func TestSavedAliasIsReturnedForARelationshipObjectInArrayResponse(t *testing.T)
 	require.Contains(t, aliases, "related_buz_for_foo_last_read=array[0]")
 	require.Equal(t, "abc", aliases["related_buz_for_foo_last_read=array[0]"].Id)
 
+	require.Contains(t, aliases, "related_buz_for_foo_name=Test Testerson")
+	require.Equal(t, "abc", aliases["related_buz_for_foo_name=Test Testerson"].Id)
+
 	require.Contains(t, aliases, "related_buz_for_foo_name=Test_Testerson")
 	require.Equal(t, "abc", aliases["related_buz_for_foo_name=Test_Testerson"].Id)
 
@@ -899,6 +910,9 @@ func TestSavedAliasIsReturnedForARelationshipObjectInArrayResponse(t *testing.T)
 	require.Contains(t, aliases, "related_buz_for_foo_last_read=array[1]")
 	require.Equal(t, "def", aliases["related_buz_for_foo_last_read=array[1]"].Id)
 
+	require.Contains(t, aliases, "related_buz_for_foo_name=Bob Robertson")
+	require.Equal(t, "def", aliases["related_buz_for_foo_name=Bob Robertson"].Id)
+
 	require.Contains(t, aliases, "related_buz_for_foo_name=Bob_Robertson")
 	require.Equal(t, "def", aliases["related_buz_for_foo_name=Bob_Robertson"].Id)
 }
@@ -932,7 +946,7 @@ func TestSavedAliasIsReturnedForARelationshipObjectInSingleResponse(t *testing.T
 
 	aliases := GetAliasesForJsonApiTypeAndAlternates("bar", []string{})
 
-	require.Len(t, aliases, 4, "There should be %d typeToAliasNameToIdMap in map not %d", 4, len(aliases))
+	require.Len(t, aliases, 5, "There should be %d typeToAliasNameToIdMap in map not %d", 5, len(aliases))
 
 	require.Contains(t, aliases, "id=456")
 	require.Equal(t, "456", aliases["id=456"].Id)
@@ -943,6 +957,9 @@ func TestSavedAliasIsReturnedForARelationshipObjectInSingleResponse(t *testing.T
 	require.Contains(t, aliases, "related_buz_for_foo_last_read=entity")
 	require.Equal(t, "456", aliases["related_buz_for_foo_last_read=entity"].Id)
 
+	require.Contains(t, aliases, "related_buz_for_foo_name=Test Testerson")
+	require.Equal(t, "456", aliases["related_buz_for_foo_name=Test Testerson"].Id)
+
 	require.Contains(t, aliases, "related_buz_for_foo_name=Test_Testerson")
 	require.Equal(t, "456", aliases["related_buz_for_foo_name=Test_Testerson"].Id)
 }
@@ -1382,3 +1399,145 @@ func TestThatCorruptAliasFileDoesntCrashProgramWhenSavingAliases(t *testing.T) {
 	require.Equal(t, "456", aliases["id=456"].Id)
 
 }
+
+func TestSavedAliasWithSpecialCharactersIsReturnedUnchanged(t *testing.T) {
+	// Fixture Setup
+	err := ClearAllAliases()
+	if err != nil {
+		t.Fatalf("Could not clear typeToAliasNameToIdMap")
+	}
+
+	// Execute SUT
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "123",
+		"type": "foo",
+		"name": "Wäsche & Co / a=b"
+	}
+}`)
+
+	// Verification
+	require.Equal(t, "123", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Wäsche & Co / a=b", "id"))
+	require.Equal(t, "123", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Wäsche_&_Co_/_a=b", "id"))
+	require.Equal(t, "123", ResolveAliasValuesOrReturnIdentity("bar", []string{}, "alias/foo/name=Wäsche & Co / a=b/id", "id"))
+}
+
+func TestLegacyAliasDoesNotReplaceAliasForAnotherResource(t *testing.T) {
+	// Fixture Setup
+	err := ClearAllAliases()
+	if err != nil {
+		t.Fatalf("Could not clear typeToAliasNameToIdMap")
+	}
+
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "123",
+		"type": "foo",
+		"name": "Summer_Sale"
+	}
+}`)
+
+	// Execute SUT
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "456",
+		"type": "foo",
+		"name": "Summer Sale"
+	}
+}`)
+
+	// Verification
+	require.Equal(t, "123", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Summer_Sale", "id"))
+	require.Equal(t, "456", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Summer Sale", "id"))
+}
+
+func TestAliasReplacesLegacyAliasForAnotherResource(t *testing.T) {
+	// Fixture Setup
+	err := ClearAllAliases()
+	if err != nil {
+		t.Fatalf("Could not clear typeToAliasNameToIdMap")
+	}
+
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "456",
+		"type": "foo",
+		"name": "Summer Sale"
+	}
+}`)
+
+	// Execute SUT
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": {
+		"id": "123",
+		"type": "foo",
+		"name": "Summer_Sale"
+	}
+}`)
+
+	// Verification
+	require.Equal(t, "123", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Summer_Sale", "id"))
+	require.Equal(t, "456", ResolveAliasValuesOrReturnIdentity("foo", []string{}, "name=Summer Sale", "id"))
+}
+
+func TestParseFullyQualifiedAlias(t *testing.T) {
+	// Execute SUT
+	jsonApiType, aliasName, attribute, ok := ParseFullyQualifiedAlias("alias/customer/name=AC/DC/id")
+
+	// Verification
+	require.True(t, ok)
+	require.Equal(t, "customer", jsonApiType)
+	require.Equal(t, "name=AC/DC", aliasName)
+	require.Equal(t, "id", attribute)
+
+	_, _, _, ok = ParseFullyQualifiedAlias("alias/customer/id")
+	require.False(t, ok)
+
+	_, _, _, ok = ParseFullyQualifiedAlias("name=AC/DC")
+	require.False(t, ok)
+}
+
+func TestGetBestAliasForIdPrefersNameOverOtherAliases(t *testing.T) {
+	// Fixture Setup
+	err := ClearAllAliases()
+	require.NoError(t, err)
+
+	SaveAliasesForResources(
+		// language=JSON
+		`
+{
+	"data": [{
+		"id": "123",
+		"type": "foo",
+		"sku": "ring",
+		"name": "Gold Ring"
+	}, {
+		"id": "456",
+		"type": "foo"
+	}]
+}`)
+
+	// Execute SUT
+	alias, ok := GetBestAliasForId("foo", []string{}, "123")
+	_, otherOk := GetBestAliasForId("foo", []string{}, "456")
+
+	// Verification
+	require.True(t, ok)
+	require.Equal(t, "name=Gold Ring", alias)
+	require.False(t, otherOk)
+}
@@ -1,78 +1,89 @@
 package apihelper
 
 import (
+	"bytes"
 	json2 "encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/elasticpath/epcc-cli/external/id"
-	log "github.com/sirupsen/logrus"
+	"github.com/elasticpath/epcc-cli/external/json"
 )
 
 func GetResourceIdsFromHttpResponse(resp *http.Response) ([]id.IdableAttributes, int, error) {
-	// Read the body
-	body, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return GetResourceIdsFromBody(body)
+	return getResourceIdsFromReader(resp.Body)
 }
 
 // GetResourceIdsFromBody parses a JSON response body and extracts resource IDs.
 // This is useful when you need to process the raw body bytes separately.
 func GetResourceIdsFromBody(body []byte) ([]id.IdableAttributes, int, error) {
-	var jsonStruct = map[string]interface{}{}
-	err := json2.Unmarshal(body, &jsonStruct)
-	if err != nil {
-		return nil, 0, fmt.Errorf("response for get was not JSON: %w", err)
-	}
+	return getResourceIdsFromReader(bytes.NewReader(body))
+}
 
+// getResourceIdsFromReader extracts resource IDs one element at a time, so that large pages aren't unmarshalled into maps.
+func getResourceIdsFromReader(r io.Reader) ([]id.IdableAttributes, int, error) {
 	// Collect ids from GET Collection output
 	var ids []id.IdableAttributes
 
-	totalResources := -1
-	if meta, ok := jsonStruct["meta"].(map[string]interface{}); ok {
-		if result, ok := meta["results"].(map[string]interface{}); ok {
-			if total, ok := result["total"].(float64); ok {
-				totalResources = int(total)
-			}
+	others, err := json.StreamJsonArrays(r, func(key string, element json2.RawMessage) error {
+		if len(element) == 0 || element[0] != '{' {
+			return nil
+		}
+
+		// interface{} so that values that aren't strings are ignored rather than being an error
+		var attributes struct {
+			Id   interface{} `json:"id"`
+			Slug interface{} `json:"slug"`
+			Sku  interface{} `json:"sku"`
+		}
+
+		if err := json2.Unmarshal(element, &attributes); err != nil {
+			return err
 		}
-	}
 
-	for _, val := range jsonStruct {
-		if arrayType, ok := val.([]interface{}); ok {
-			for _, value := range arrayType {
-				if mapValue, ok := value.(map[string]interface{}); ok {
-					match := false
+		match := false
 
-					idAttr := id.IdableAttributes{}
-					if id, ok := mapValue["id"].(string); ok {
+		idAttr := id.IdableAttributes{}
+		if id, ok := attributes.Id.(string); ok {
+			match = true
+			idAttr.Id = id
+		}
 
-						match = true
-						idAttr.Id = id
-					}
+		if slug, ok := attributes.Slug.(string); ok {
+			match = true
+			idAttr.Slug = slug
+		}
 
-					if slug, ok := mapValue["slug"].(string); ok {
+		if sku, ok := attributes.Sku.(string); ok {
+			match = true
+			idAttr.Sku = sku
+		}
 
-						match = true
-						idAttr.Slug = slug
-					}
+		if match {
+			ids = append(ids, idAttr)
+		}
 
-					if sku, ok := mapValue["sku"].(string); ok {
+		return nil
+	})
 
-						match = true
-						idAttr.Sku = sku
-					}
+	if err != nil {
+		return nil, 0, fmt.Errorf("response for get was not JSON: %w", err)
+	}
+
+	totalResources := -1
 
-					if match {
-						ids = append(ids, idAttr)
-					}
-				}
-			}
+	var meta struct {
+		Results struct {
+			Total *json2.Number `json:"total"`
+		} `json:"results"`
+	}
+
+	if rawMeta, ok := others["meta"]; ok && json2.Unmarshal(rawMeta, &meta) == nil && meta.Results.Total != nil {
+		if total, err := meta.Results.Total.Float64(); err == nil {
+			totalResources = int(total)
 		}
 	}
+
 	return ids, totalResources, nil
 }
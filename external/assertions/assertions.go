@@ -0,0 +1,235 @@
+package assertions
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/itchyny/gojq"
+)
+
+// AssertionError is returned when a predicate isn't true. If the predicate compares two values (i.e., actual == expected),
+// the values are shown, or the differences between them if they are objects or arrays
+type AssertionError struct {
+	Predicate string
+
+	// Whether the predicate compares two values, and they should be equal (==) or not (!=)
+	Compared bool
+	Equal    bool
+	Actual   interface{}
+	Expected interface{}
+
+	// The result of the predicate, if it didn't compare two values
+	Result interface{}
+}
+
+func (e *AssertionError) Error() string {
+	sb := &strings.Builder{}
+	sb.WriteString("assertion failed: " + e.Predicate)
+
+	if !e.Compared {
+		sb.WriteString("\n  result:   " + formatValue(e.Result))
+		return sb.String()
+	}
+
+	if !e.Equal {
+		sb.WriteString("\n  expected a value other than: " + formatValue(e.Expected))
+		return sb.String()
+	}
+
+	if diff := formatDiff(e.Expected, e.Actual); diff != "" {
+		sb.WriteString("\n  differences (- only expected, + only actual, ~ expected => actual):\n")
+		sb.WriteString(diff)
+		return strings.TrimRight(sb.String(), "\n")
+	}
+
+	sb.WriteString("\n  expected: " + formatValue(e.Expected))
+	sb.WriteString("\n  actual:   " + formatValue(e.Actual))
+
+	return sb.String()
+}
+
+// Jq checks that a jq predicate is true for a JSON document (every result must be true, and there must be at least one)
+func Jq(predicate string, document string) error {
+	var doc interface{}
+
+	if err := gojson.Unmarshal([]byte(document), &doc); err != nil {
+		return fmt.Errorf("could not parse JSON: %w", err)
+	}
+
+	query, err := gojq.Parse(predicate)
+
+	if err != nil {
+		return fmt.Errorf("could not parse jq predicate %s: %w", predicate, err)
+	}
+
+	results, err := json.RunJQWithArray(predicate, doc)
+
+	if err != nil {
+		return fmt.Errorf("error evaluating jq predicate %s: %w", predicate, err)
+	}
+
+	if isTrue(results) {
+		return nil
+	}
+
+	assertionErr := &AssertionError{Predicate: predicate, Result: jqResult(results)}
+
+	if query.Op == gojq.OpEq || query.Op == gojq.OpNe {
+		actual, actualErr := json.RunJQWithArray(query.Left.String(), doc)
+		expected, expectedErr := json.RunJQWithArray(query.Right.String(), doc)
+
+		if actualErr == nil && expectedErr == nil {
+			assertionErr.Compared = true
+			assertionErr.Equal = query.Op == gojq.OpEq
+			assertionErr.Actual = jqResult(actual)
+			assertionErr.Expected = jqResult(expected)
+		}
+	}
+
+	return assertionErr
+}
+
+// Expr checks that an expr (https://expr-lang.org/) predicate is true for a JSON document, the keys of the document are the variables (e.g., data.id),
+// and any other JSON value (e.g., a captured string) is the variable value
+func Expr(predicate string, document string) error {
+	var value interface{}
+
+	if err := gojson.Unmarshal([]byte(document), &value); err != nil {
+		return fmt.Errorf("could not parse JSON: %w", err)
+	}
+
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		doc = map[string]interface{}{"value": value}
+	}
+
+	result, err := evalExpr(predicate, doc)
+
+	if err != nil {
+		return fmt.Errorf("error evaluating expr predicate %s: %w", predicate, err)
+	}
+
+	if b, ok := result.(bool); ok && b {
+		return nil
+	}
+
+	assertionErr := &AssertionError{Predicate: predicate, Result: result}
+
+	tree, err := parser.Parse(predicate)
+
+	if err != nil {
+		return assertionErr
+	}
+
+	if binary, ok := tree.Node.(*ast.BinaryNode); ok && (binary.Operator == "==" || binary.Operator == "!=") {
+		actual, actualErr := evalExpr(binary.Left.String(), doc)
+		expected, expectedErr := evalExpr(binary.Right.String(), doc)
+
+		if actualErr == nil && expectedErr == nil {
+			assertionErr.Compared = true
+			assertionErr.Equal = binary.Operator == "=="
+			assertionErr.Actual = actual
+			assertionErr.Expected = expected
+		}
+	}
+
+	return assertionErr
+}
+
+func evalExpr(code string, env map[string]interface{}) (interface{}, error) {
+	program, err := expr.Compile(code, expr.Env(env), expr.AllowUndefinedVariables())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expr.Run(program, env)
+}
+
+func isTrue(results []interface{}) bool {
+	if len(results) == 0 {
+		return false
+	}
+
+	for _, r := range results {
+		if b, ok := r.(bool); !ok || !b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jqResult returns the only result of a query as is, or all of them if there are more
+func jqResult(results []interface{}) interface{} {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	return results
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+
+	b, err := gojson.Marshal(v)
+
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+// formatDiff returns the differences between two objects or arrays, one per line, or an empty string if either isn't one
+func formatDiff(expected interface{}, actual interface{}) string {
+	if !isContainer(expected) || !isContainer(actual) {
+		return ""
+	}
+
+	expectedJson, err := gojson.Marshal(expected)
+
+	if err != nil {
+		return ""
+	}
+
+	actualJson, err := gojson.Marshal(actual)
+
+	if err != nil {
+		return ""
+	}
+
+	diffs, err := json.DiffJson(string(expectedJson), string(actualJson), nil)
+
+	if err != nil || len(diffs) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := json.WriteJsonDiff(diffs, buf); err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	return "  " + strings.Join(lines, "\n  ") + "\n"
+}
+
+func isContainer(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	kind := reflect.TypeOf(v).Kind()
+	return kind == reflect.Map || kind == reflect.Slice
+}
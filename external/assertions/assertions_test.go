@@ -0,0 +1,104 @@
+package assertions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const cart = `{"data":{"id":"123","meta":{"display_price":{"discount":{"amount":-400,"currency":"USD"}}},"items":[{"sku":"a"},{"sku":"b"}]}}`
+
+func TestThatJqPredicateThatIsTrueSucceeds(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Jq(`.data.meta.display_price.discount.currency == "USD"`, cart)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatJqComparisonThatIsFalseShowsExpectedAndActual(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Jq(`.data.meta.display_price.discount.amount == -500`, cart)
+
+	// Verification
+	require.Error(t, err)
+	require.Equal(t, "assertion failed: .data.meta.display_price.discount.amount == -500\n  expected: -500\n  actual:   -400", err.Error())
+}
+
+func TestThatJqComparisonOfObjectsShowsDifferences(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Jq(`.data.meta.display_price.discount == {"amount": -500, "currency": "USD"}`, cart)
+
+	// Verification
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "~ /amount: -500 => -400")
+}
+
+func TestThatJqPredicateWithoutResultsFails(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Jq(`.data.items[] | select(.sku == "c") | true`, cart)
+
+	// Verification
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "result:   []")
+}
+
+func TestThatInvalidJqPredicateReturnsAnError(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Jq(`.data ==`, cart)
+
+	// Verification
+	require.ErrorContains(t, err, "could not parse jq predicate")
+}
+
+func TestThatExprPredicateThatIsTrueSucceeds(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Expr(`len(data.items) == 2 && data.meta.display_price.discount.amount < 0`, cart)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatExprComparisonThatIsFalseShowsExpectedAndActual(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Expr(`data.meta.display_price.discount.amount == -500`, cart)
+
+	// Verification
+	require.Error(t, err)
+	require.Equal(t, "assertion failed: data.meta.display_price.discount.amount == -500\n  expected: -500\n  actual:   -400", err.Error())
+}
+
+func TestThatExprNotEqualComparisonThatIsFalseShowsValue(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Expr(`data.id != "123"`, cart)
+
+	// Verification
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `expected a value other than: "123"`)
+}
+
+func TestThatExprPredicateOnAValueThatIsNotAnObjectUsesValue(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	err := Expr(`value == "a@b.com"`, `"a@b.com"`)
+
+	// Verification
+	require.NoError(t, err)
+}
@@ -1,46 +1,91 @@
 package autofill
 
 import (
+	"context"
+	"sort"
 	"strings"
 
 	"github.com/elasticpath/epcc-cli/external/faker"
+	"github.com/elasticpath/epcc-cli/external/random"
 	"github.com/elasticpath/epcc-cli/external/resources"
+	log "github.com/sirupsen/logrus"
 )
 
-func GetAutoFillQueryParameters(resourceName string, qps []resources.QueryParameter) []string {
+func GetAutoFillQueryParameters(ctx context.Context, resourceName string, qps []resources.QueryParameter) []string {
+	s := random.FromContext(ctx)
+
 	args := make([]string, 0)
 
 	for _, v := range qps {
 		key := v.Name
 		autoFill := v.AutoFill
-		args = processAutoFill(autoFill, args, key)
+		args = processAutoFill(s, autoFill, args, key)
 	}
 
 	return args
 }
 
-func GetJsonArrayForResource(r *resources.Resource) []string {
+// GetJsonArrayForResource returns the auto filled values for a resource, values next to a country in args (e.g., billing_address.city for billing_address.country)
+// are for the locale of the country.
+func GetJsonArrayForResource(ctx context.Context, r *resources.Resource, args []string) []string {
+	s := random.FromContext(ctx)
 
-	args := make([]string, 0)
+	localesByPrefix := getLocalesForCountries(args)
+
+	jsonArgs := make([]string, 0)
+
+	// The attributes are filled in order, so that the same seed always gives the same values
+	attributeNames := make([]string, 0, len(r.Attributes))
+	for name := range r.Attributes {
+		attributeNames = append(attributeNames, name)
+	}
+
+	sort.Strings(attributeNames)
 
-	for _, data := range r.Attributes {
+	for _, name := range attributeNames {
+		data := r.Attributes[name]
 		key := data.Key
 		key = strings.Replace(key, "data[n]", "data[0]", 1)
 		autofill := data.AutoFill
 
-		args = processAutoFill(autofill, args, key)
+		if locale, ok := localesByPrefix[key[:strings.LastIndex(key, ".")+1]]; ok && strings.HasPrefix(autofill, "FUNC:") && !strings.Contains(autofill[5:], ":") {
+			autofill = autofill + ":" + locale
+		}
+
+		jsonArgs = processAutoFill(s, autofill, jsonArgs, key)
 
 	}
-	return args
+	return jsonArgs
+
+}
+
+// getLocalesForCountries returns the locale for each country in args (e.g., DE or Germany), by the prefix of the key (e.g., billing_address.)
+func getLocalesForCountries(args []string) map[string]string {
+	localesByPrefix := map[string]string{}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		key := strings.Replace(args[i], "data[n]", "data[0]", 1)
+
+		if key != "country" && !strings.HasSuffix(key, ".country") {
+			continue
+		}
+
+		if locale, ok := faker.GetLocaleForCountry(args[i+1]); ok {
+			localesByPrefix[strings.TrimSuffix(key, "country")] = locale
+		} else {
+			log.Debugf("No locale for country %s, auto filled values will not be for the country", args[i+1])
+		}
+	}
 
+	return localesByPrefix
 }
 
-func processAutoFill(autofill string, args []string, key string) []string {
+func processAutoFill(s *random.Stream, autofill string, args []string, key string) []string {
 	if strings.HasPrefix(autofill, "FUNC:") {
 
 		methodName := strings.Trim(autofill[5:], " ")
 
-		v := faker.CallFakeFunc(methodName)
+		v := faker.CallFakeFuncFrom(s, methodName)
 		args = append(args, key, v)
 
 	} else if strings.HasPrefix(autofill, "VALUE:") {
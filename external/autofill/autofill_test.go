@@ -0,0 +1,46 @@
+package autofill
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/random"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJsonArrayForResourceReturnsTheSameValuesForTheSameStreamWithASeed(t *testing.T) {
+	// Fixture Setup
+	random.SetSeed(42)
+
+	resource, ok := resources.GetResourceByName("customer")
+	require.True(t, ok)
+
+	// Execute SUT
+	first := GetJsonArrayForResource(random.WithStream(context.Background(), random.NewStream("customer")), &resource, []string{})
+	second := GetJsonArrayForResource(random.WithStream(context.Background(), random.NewStream("customer")), &resource, []string{})
+
+	// Verification
+	require.NotEmpty(t, first)
+	require.Equal(t, first, second)
+}
+
+func TestGetJsonArrayForResourceReturnsValuesForTheLocaleOfTheCountry(t *testing.T) {
+	// Fixture Setup
+	resource, ok := resources.GetResourceByName("customer-address")
+	require.True(t, ok)
+
+	// Execute SUT
+	args := GetJsonArrayForResource(context.Background(), &resource, []string{"country", "DE"})
+
+	// Verification
+	values := map[string]string{}
+	for i := 0; i < len(args); i += 2 {
+		values[args[i]] = args[i+1]
+	}
+
+	require.Regexp(t, regexp.MustCompile(`^"[0-9]{5}"$`), values["postcode"])
+	require.Regexp(t, regexp.MustCompile(`^\+49 30 [0-9]{8}$`), values["phone_number"])
+	require.Equal(t, "DE", values["country"])
+}
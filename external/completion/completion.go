@@ -388,7 +388,11 @@ func Complete(c Request) ([]string, cobra.ShellCompDirective) {
 		if !c.NoAliases {
 			aliasesForJsonApiType := aliases.GetAliasesForJsonApiTypeAndAlternates(jsonApiType, c.Resource.AlternateJsonApiTypesForAliases)
 
-			for alias := range aliasesForJsonApiType {
+			for alias, v := range aliasesForJsonApiType {
+				if v.AlternateFor != "" {
+					// Legacy forms still resolve, but we only suggest the actual name
+					continue
+				}
 				results = append(results, alias)
 			}
 		}
@@ -520,14 +524,8 @@ func Complete(c Request) ([]string, cobra.ShellCompDirective) {
 		results = append(results, currencies...)
 	}
 
-	// This is dead code since I hacked the aliases to never return spaces.
-	newResults := make([]string, 0, len(results))
-
-	for _, result := range results {
-		newResults = append(newResults, strings.ReplaceAll(result, " ", "\\ "))
-	}
-
-	return newResults, compDir
+	// Cobra's completion scripts for each shell handle escaping of spaces and special characters
+	return results, compDir
 }
 
 func completeAttributeTypes(c Request, aType string, compDir cobra.ShellCompDirective) ([]string, cobra.ShellCompDirective) {
@@ -560,10 +558,14 @@ func completeAttributeTypes(c Request, aType string, compDir cobra.ShellCompDire
 				}
 				compDir = compDir | cobra.ShellCompDirectiveNoSpace
 
-			case 3, 4:
+			default:
+				// Alias names may themselves contain a /
 				if aliasType, ok := resources.GetResourceByName(fullyQualifiedAlias[1]); ok {
 					if !c.NoAliases {
-						for alias := range aliases.GetAliasesForJsonApiTypeAndAlternates(aliasType.JsonApiType, aliasType.AlternateJsonApiTypesForAliases) {
+						for alias, v := range aliases.GetAliasesForJsonApiTypeAndAlternates(aliasType.JsonApiType, aliasType.AlternateJsonApiTypesForAliases) {
+							if v.AlternateFor != "" {
+								continue
+							}
 							results = append(results, "alias/"+aliasType.JsonApiType+"/"+alias+"/id")
 
 							if _, ok2 := aliasType.Attributes["sku"]; ok2 {
@@ -587,7 +589,10 @@ func completeAttributeTypes(c Request, aType string, compDir cobra.ShellCompDire
 		} else if aliasType, ok := resources.GetResourceByName(resourceType); ok {
 
 			if !c.NoAliases {
-				for alias := range aliases.GetAliasesForJsonApiTypeAndAlternates(aliasType.JsonApiType, aliasType.AlternateJsonApiTypesForAliases) {
+				for alias, v := range aliases.GetAliasesForJsonApiTypeAndAlternates(aliasType.JsonApiType, aliasType.AlternateJsonApiTypesForAliases) {
+					if v.AlternateFor != "" {
+						continue
+					}
 					results = append(results, alias)
 				}
 			}
@@ -348,7 +348,8 @@ func TestCompleteAlias(t *testing.T) {
 
 	// Verify Results
 	require.Equal(t, compDir, cobra.ShellCompDirectiveNoFileComp)
-	require.Contains(t, completions, "name=John_Smith")
+	require.Contains(t, completions, "name=John Smith")
+	require.NotContains(t, completions, "name=John_Smith")
 }
 
 func TestCompleteQueryParamValue(t *testing.T) {
@@ -549,8 +550,8 @@ func TestAttributeValueWithTemplating(t *testing.T) {
 	require.Equal(t, compDir, cobra.ShellCompDirectiveNoFileComp)
 	require.Contains(t, completions, "any")
 	require.Contains(t, completions, "email")
-	require.Contains(t, completions, `{{\ randAlphaNum\ |`)
-	require.Contains(t, completions, `{{\ randAlphaNum\ }}`)
+	require.Contains(t, completions, `{{ randAlphaNum |`)
+	require.Contains(t, completions, `{{ randAlphaNum }}`)
 }
 
 func TestAttributeValueWithTemplatingAndPipe(t *testing.T) {
@@ -573,8 +574,8 @@ func TestAttributeValueWithTemplatingAndPipe(t *testing.T) {
 	require.Equal(t, compDir, cobra.ShellCompDirectiveNoFileComp)
 	require.Contains(t, completions, "any")
 	require.Contains(t, completions, "email")
-	require.Contains(t, completions, `{{\ randAlphaNum\ 3\ |\ upper\ |`)
-	require.Contains(t, completions, `{{\ randAlphaNum\ 3\ |\ lower\ }}`)
+	require.Contains(t, completions, `{{ randAlphaNum 3 | upper |`)
+	require.Contains(t, completions, `{{ randAlphaNum 3 | lower }}`)
 }
 
 func TestCompleteAttributeKeyWithEmptyExistingValuesReturnsAll(t *testing.T) {
@@ -663,7 +664,7 @@ func TestCompleteAttributeKeyWithWildcardReturnsIncrementedArrayIndexes(t *testi
 		Attributes: map[string]string{
 			"name":                         "snowplow",
 			"sku":                          "prod-002",
-			"components.bar.options.id[0]": "red",
+			"components.bar.options[0].id": "red",
 		},
 	}
 
@@ -672,7 +673,7 @@ func TestCompleteAttributeKeyWithWildcardReturnsIncrementedArrayIndexes(t *testi
 
 	// Verify Results
 	require.Equal(t, compDir, cobra.ShellCompDirectiveNoFileComp)
-	require.Contains(t, completions, "components.bar.options.id[1]")
+	require.Contains(t, completions, "components.bar.options[1].id")
 }
 
 func TestCompleteAttributeKeyWithWithMultipleArrayIndexesIncrementsAppropriately(t *testing.T) {
@@ -0,0 +1,278 @@
+package customapis
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/httpclient"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	log "github.com/sirupsen/logrus"
+)
+
+const customApiEntriesDocs = "https://elasticpath.dev/docs/api/commerce-extensions/custom-api-entries"
+
+// GeneratedFileHeader starts the first line of the files written by epcc resources sync-custom-apis
+const GeneratedFileHeader = "# Generated by epcc resources sync-custom-apis"
+
+type CustomApi struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	ApiType     string `json:"api_type"`
+	Description string `json:"description"`
+
+	Fields []CustomField `json:"-"`
+}
+
+type CustomField struct {
+	Id          string                            `json:"id"`
+	Name        string                            `json:"name"`
+	Slug        string                            `json:"slug"`
+	FieldType   string                            `json:"field_type"`
+	Description string                            `json:"description"`
+	Validation  map[string]map[string]interface{} `json:"validation"`
+}
+
+// GetCustomApis retrieves all the custom APIs in the store along with their fields.
+func GetCustomApis(ctx context.Context) ([]CustomApi, error) {
+	apis := make([]CustomApi, 0)
+	if err := getAllPages(ctx, "/v2/settings/extensions/custom-apis", &apis); err != nil {
+		return nil, fmt.Errorf("could not retrieve custom apis: %w", err)
+	}
+
+	for i := range apis {
+		apis[i].Fields = make([]CustomField, 0)
+		if err := getAllPages(ctx, fmt.Sprintf("/v2/settings/extensions/custom-apis/%s/fields", apis[i].Id), &apis[i].Fields); err != nil {
+			return nil, fmt.Errorf("could not retrieve fields for custom api %s: %w", apis[i].Slug, err)
+		}
+	}
+
+	return apis, nil
+}
+
+func getAllPages[T any](ctx context.Context, path string, result *[]T) error {
+	const pageLength = 100
+
+	for offset := 0; ; offset += pageLength {
+		if offset >= 10000 {
+			// Most pagination limits have a max offset of 10,000
+			return fmt.Errorf("maximum pagination offset reached, could not retrieve all records from %s", path)
+		}
+
+		params := url.Values{}
+		params.Add("page[limit]", fmt.Sprintf("%d", pageLength))
+		params.Add("page[offset]", fmt.Sprintf("%d", offset))
+
+		resp, err := httpclient.DoRequest(ctx, "GET", path, params.Encode(), nil)
+
+		if err != nil {
+			return err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("%s: %s", resp.Status, string(body))
+		}
+
+		page := struct {
+			Data []T `json:"data"`
+		}{}
+
+		if err := gojson.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("could not parse response from %s: %w", path, err)
+		}
+
+		*result = append(*result, page.Data...)
+
+		if len(page.Data) < pageLength {
+			return nil
+		}
+	}
+}
+
+// FindStaleResourceFiles returns the files in the directory that were generated for a custom API, but aren't one of the current files (e.g., the custom API was deleted)
+func FindStaleResourceFiles(dir string, currentFiles []string) ([]string, error) {
+	current := make(map[string]bool, len(currentFiles))
+	for _, f := range currentFiles {
+		current[filepath.Clean(f)] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "custom-api-*.yaml"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make([]string, 0)
+	for _, f := range files {
+		if current[filepath.Clean(f)] {
+			continue
+		}
+
+		contents, err := os.ReadFile(f)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", f, err)
+		}
+
+		// Only files we generated, so that resources the user wrote aren't removed
+		if strings.HasPrefix(string(contents), GeneratedFileHeader) {
+			stale = append(stale, f)
+		}
+	}
+
+	return stale, nil
+}
+
+// GenerateResources creates a resource definition for the entries of each custom API.
+func GenerateResources(apis []CustomApi) map[string]resources.Resource {
+	result := make(map[string]resources.Resource, len(apis))
+
+	for _, api := range apis {
+		pluralName, singularName := GetResourceNames(api.Slug)
+		urlVariable := strings.ReplaceAll(pluralName, "-", "_")
+
+		collectionUrl := fmt.Sprintf("/v2/extensions/%s", api.Slug)
+		entityUrl := fmt.Sprintf("/v2/extensions/%s/{%s}", api.Slug, urlVariable)
+
+		attributes := make(map[string]*resources.CrudEntityAttribute, len(api.Fields))
+		for _, field := range api.Fields {
+			for key, attribute := range getAttributesForField(field) {
+				attributes[key] = attribute
+			}
+		}
+
+		result[pluralName] = resources.Resource{
+			Docs:          customApiEntriesDocs,
+			JsonApiType:   api.ApiType,
+			JsonApiFormat: "legacy",
+			SingularName:  singularName,
+			GetCollectionInfo: &resources.CrudEntityInfo{
+				Docs: customApiEntriesDocs,
+				Url:  collectionUrl,
+				QueryParameters: []resources.QueryParameter{
+					{Name: "page[offset]", Type: "INT"},
+					{Name: "page[limit]", Type: "INT"},
+					{Name: "filter", Type: "STRING"},
+					{Name: "sort", Type: "ENUM:id,-id,created_at,-created_at,updated_at,-updated_at"},
+				},
+			},
+			GetEntityInfo: &resources.CrudEntityInfo{
+				Docs: customApiEntriesDocs,
+				Url:  entityUrl,
+			},
+			CreateEntityInfo: &resources.CrudEntityInfo{
+				Docs: customApiEntriesDocs,
+				Url:  collectionUrl,
+			},
+			UpdateEntityInfo: &resources.CrudEntityInfo{
+				Docs: customApiEntriesDocs,
+				Url:  entityUrl,
+			},
+			DeleteEntityInfo: &resources.CrudEntityInfo{
+				Docs: customApiEntriesDocs,
+				Url:  entityUrl,
+			},
+			Attributes: attributes,
+		}
+	}
+
+	return result
+}
+
+// GetResourceNames returns the plural and singular resource names to use for a custom API slug (e.g., wishlists => wishlists, wishlist)
+func GetResourceNames(slug string) (string, string) {
+	plural := strings.ToLower(strings.ReplaceAll(slug, "_", "-"))
+
+	var singular string
+	switch {
+	case strings.HasSuffix(plural, "ies"):
+		singular = strings.TrimSuffix(plural, "ies") + "y"
+	case strings.HasSuffix(plural, "ses"), strings.HasSuffix(plural, "xes"):
+		singular = strings.TrimSuffix(plural, "es")
+	case strings.HasSuffix(plural, "s") && !strings.HasSuffix(plural, "ss"):
+		singular = strings.TrimSuffix(plural, "s")
+	default:
+		singular = plural + "-entry"
+	}
+
+	return plural, singular
+}
+
+func getAttributesForField(field CustomField) map[string]*resources.CrudEntityAttribute {
+	validation := field.Validation[field.FieldType]
+
+	usage := field.Description
+	if constraints := describeValidation(validation); constraints != "" {
+		if usage != "" {
+			usage += " "
+		}
+		usage += "(" + constraints + ")"
+	}
+
+	switch field.FieldType {
+	case "list":
+		allowedType, _ := validation["allowed_type"].(string)
+		return map[string]*resources.CrudEntityAttribute{
+			field.Slug + "[n]": {
+				Type:  getAttributeTypeForFieldType(allowedType),
+				Usage: usage,
+			},
+		}
+	default:
+		return map[string]*resources.CrudEntityAttribute{
+			field.Slug: {
+				Type:  getAttributeTypeForFieldType(field.FieldType),
+				Usage: usage,
+			},
+		}
+	}
+}
+
+func getAttributeTypeForFieldType(fieldType string) string {
+	switch fieldType {
+	case "string":
+		return "STRING"
+	case "integer":
+		return "INT"
+	case "float":
+		return "FLOAT"
+	case "boolean":
+		return "BOOL"
+	case "any", "":
+		return "PRIMITIVE"
+	default:
+		log.Warnf("Unknown custom field type %s, treating as a string", fieldType)
+		return "STRING"
+	}
+}
+
+func describeValidation(validation map[string]interface{}) string {
+	constraints := make([]string, 0)
+
+	for _, k := range []string{"min_length", "max_length", "min_value", "max_value", "regex"} {
+		if v, ok := validation[k]; ok && v != nil {
+			constraints = append(constraints, fmt.Sprintf("%s: %v", k, v))
+		}
+	}
+
+	for _, k := range []string{"unique", "immutable", "allow_null_values"} {
+		if v, ok := validation[k]; ok && v != nil && v != false && v != "no" {
+			constraints = append(constraints, k)
+		}
+	}
+
+	return strings.Join(constraints, ", ")
+}
@@ -0,0 +1,104 @@
+package customapis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateResourcesCreatesResourceForCustomApi(t *testing.T) {
+	// Fixture Setup
+	apis := []CustomApi{
+		{
+			Id:      "123",
+			Name:    "Wishlists",
+			Slug:    "wishlists",
+			ApiType: "wishlist_ext",
+			Fields: []CustomField{
+				{Slug: "name", FieldType: "string", Description: "The name", Validation: map[string]map[string]interface{}{
+					"string": {"max_length": 255, "immutable": false},
+				}},
+				{Slug: "priority", FieldType: "integer"},
+				{Slug: "weight", FieldType: "float"},
+				{Slug: "public", FieldType: "boolean"},
+				{Slug: "tags", FieldType: "list", Validation: map[string]map[string]interface{}{
+					"list": {"allowed_type": "string"},
+				}},
+			},
+		},
+	}
+
+	// Execute SUT
+	r := GenerateResources(apis)
+
+	// Verification
+	require.Contains(t, r, "wishlists")
+	wishlists := r["wishlists"]
+
+	require.Equal(t, "wishlist", wishlists.SingularName)
+	require.Equal(t, "wishlist_ext", wishlists.JsonApiType)
+	require.Equal(t, "/v2/extensions/wishlists", wishlists.CreateEntityInfo.Url)
+	require.Equal(t, "/v2/extensions/wishlists/{wishlists}", wishlists.GetEntityInfo.Url)
+
+	require.Equal(t, "STRING", wishlists.Attributes["name"].Type)
+	require.Equal(t, "The name (max_length: 255)", wishlists.Attributes["name"].Usage)
+	require.Equal(t, "INT", wishlists.Attributes["priority"].Type)
+	require.Equal(t, "FLOAT", wishlists.Attributes["weight"].Type)
+	require.Equal(t, "BOOL", wishlists.Attributes["public"].Type)
+	require.Equal(t, "STRING", wishlists.Attributes["tags[n]"].Type)
+}
+
+func TestGeneratedResourcesAreValid(t *testing.T) {
+	// Fixture Setup
+	apis := []CustomApi{
+		{Id: "123", Slug: "loyalty_points", ApiType: "loyalty_point_ext", Fields: []CustomField{{Slug: "points", FieldType: "integer"}}},
+	}
+
+	// Execute SUT
+	yamlBytes, err := yaml.Marshal(GenerateResources(apis))
+	require.NoError(t, err)
+
+	r, err := resources.LoadResourcesFromYaml("custom-api-loyalty-points.yaml", yamlBytes)
+
+	// Verification
+	require.NoError(t, err)
+	require.Contains(t, r, "loyalty-points")
+	require.Equal(t, "loyalty-point", r["loyalty-points"].SingularName)
+}
+
+func TestGetResourceNames(t *testing.T) {
+	for slug, expected := range map[string][2]string{
+		"wishlists":  {"wishlists", "wishlist"},
+		"categories": {"categories", "category"},
+		"boxes":      {"boxes", "box"},
+		"inventory":  {"inventory", "inventory-entry"},
+		"Loyalty_Ps": {"loyalty-ps", "loyalty-p"},
+	} {
+		plural, singular := GetResourceNames(slug)
+		require.Equal(t, expected[0], plural)
+		require.Equal(t, expected[1], singular)
+	}
+}
+
+func TestFindStaleResourceFilesOnlyReturnsGeneratedFilesThatAreNotCurrent(t *testing.T) {
+	// Fixture Setup
+	dir := t.TempDir()
+	current := filepath.Join(dir, "custom-api-wishlists.yaml")
+	deleted := filepath.Join(dir, "custom-api-loyalty-points.yaml")
+	userWritten := filepath.Join(dir, "custom-api-reviews.yaml")
+
+	require.NoError(t, os.WriteFile(current, []byte(GeneratedFileHeader+" from custom api wishlists (1)\n"), 0600))
+	require.NoError(t, os.WriteFile(deleted, []byte(GeneratedFileHeader+" from custom api loyalty_points (2)\n"), 0600))
+	require.NoError(t, os.WriteFile(userWritten, []byte("reviews:\n"), 0600))
+
+	// Execute SUT
+	stale, err := FindStaleResourceFiles(dir, []string{current})
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{deleted}, stale)
+}
@@ -6,20 +6,55 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/brianvoe/gofakeit/v6"
+	"github.com/elasticpath/epcc-cli/external/random"
 	log "github.com/sirupsen/logrus"
 )
 
-var faker = gofakeit.New(1)
-
+// Seed restarts the default stream of fake data from a seed
 func Seed(n int64) {
-	faker = gofakeit.New(n)
+	random.Default().Reseed(n)
 }
 
+// CallFakeFunc returns the value of a gofakeit function (e.g., Email) from the default stream
 func CallFakeFunc(methodName string) string {
+	return CallFakeFuncFrom(random.Default(), methodName)
+}
+
+// CallFakeFuncFrom returns the value of a gofakeit function (e.g., Email) from a stream, the function can have a locale (e.g., Address:de_DE)
+func CallFakeFuncFrom(s *random.Stream, methodName string) string {
+	if idx := strings.Index(methodName, ":"); idx >= 0 {
+		return CallLocaleFakeFuncFrom(s, methodName[idx+1:], methodName[:idx])
+	}
+
+	return callFakeFunc(s, methodName)
+}
+
+// CallLocaleFakeFuncFrom returns the value of a function for a locale (e.g., de_DE) from a stream,
+// functions that don't depend on the locale (e.g., Email) return the same values as CallFakeFuncFrom
+func CallLocaleFakeFuncFrom(s *random.Stream, localeName string, methodName string) string {
+	l, ok := locales[localeName]
+	if !ok {
+		log.Warnf("Unknown locale %s for %s, supported locales are %s", localeName, methodName, strings.Join(GetLocales(), ", "))
+		return callFakeFunc(s, methodName)
+	}
+
+	if v, ok := callLocaleFakeFunc(s, l, methodName); ok {
+		if _, err := strconv.Atoi(v); err == nil {
+			// If we get an integer value back, lets just quote it.
+			v = fmt.Sprintf("\"%s\"", v)
+		}
+
+		return v
+	}
+
+	log.Debugf("%s doesn't depend on the locale, ignoring %s", methodName, localeName)
+	return callFakeFunc(s, methodName)
+}
+
+func callFakeFunc(s *random.Stream, methodName string) string {
 
 	var arg string
-	v := reflect.ValueOf(faker)
+	v := reflect.ValueOf(s.Faker())
 
 	var rejectZero = false
 	if strings.HasPrefix(methodName, "NonZero") {
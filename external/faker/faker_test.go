@@ -0,0 +1,86 @@
+package faker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallFakeFuncFromWithLocaleReturnsValuesForTheLocale(t *testing.T) {
+	// Fixture Setup
+	s := random.NewStream()
+
+	// Execute SUT
+	country := CallFakeFuncFrom(s, "CountryAbr:de_DE")
+	postcode := CallFakeFuncFrom(s, "Zip:nl_NL")
+	phone := CallFakeFuncFrom(s, "PhoneFormatted:fr_FR")
+	currency := CallFakeFuncFrom(s, "CurrencyShort:ja_JP")
+
+	// Verification
+	require.Equal(t, "DE", country)
+	require.Regexp(t, regexp.MustCompile(`^[0-9]{4} [A-Z]{2}$`), postcode)
+	require.Regexp(t, regexp.MustCompile(`^\+33 1( [0-9]{2}){4}$`), phone)
+	require.Equal(t, "JPY", currency)
+}
+
+func TestCallFakeFuncFromWithLocaleQuotesIntegers(t *testing.T) {
+	// Fixture Setup
+	s := random.NewStream()
+
+	// Execute SUT
+	postcode := CallFakeFuncFrom(s, "Zip:de_DE")
+
+	// Verification
+	require.Regexp(t, regexp.MustCompile(`^"[0-9]{5}"$`), postcode)
+}
+
+func TestCallFakeFuncFromWithLocaleReturnsAnAddressForTheLocale(t *testing.T) {
+	// Fixture Setup
+	s := random.NewStream()
+
+	// Execute SUT
+	address := CallFakeFuncFrom(s, "Address:de_DE")
+
+	// Verification
+	require.Regexp(t, regexp.MustCompile(`^[^,]+ [0-9]+, [0-9]{5} .+$`), address)
+}
+
+func TestCallFakeFuncFromWithLocaleFallsBackForFunctionsThatDontDependOnTheLocale(t *testing.T) {
+	// Fixture Setup
+	s := random.NewStream()
+
+	// Execute SUT
+	email := CallFakeFuncFrom(s, "Email:de_DE")
+
+	// Verification
+	require.Contains(t, email, "@")
+}
+
+func TestCallFakeFuncFromWithUnknownLocaleFallsBackToTheFunction(t *testing.T) {
+	// Fixture Setup
+	s := random.NewStream()
+
+	// Execute SUT
+	country := CallFakeFuncFrom(s, "CountryAbr:xx_XX")
+
+	// Verification
+	require.Len(t, country, 2)
+}
+
+func TestGetLocaleForCountryReturnsTheLocaleForCodesAndNames(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	byCode, byCodeOk := GetLocaleForCountry("de")
+	byName, byNameOk := GetLocaleForCountry("France")
+	_, unknownOk := GetLocaleForCountry("Atlantis")
+
+	// Verification
+	require.True(t, byCodeOk)
+	require.Equal(t, "de_DE", byCode)
+	require.True(t, byNameOk)
+	require.Equal(t, "fr_FR", byName)
+	require.False(t, unknownOk)
+}
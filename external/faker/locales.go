@@ -0,0 +1,283 @@
+package faker
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/random"
+)
+
+// region is a state, province, county, etc...
+type region struct {
+	name string
+	abr  string
+}
+
+// locale is the data used to generate names, addresses, phone numbers and currencies that look like they are from a country
+type locale struct {
+	country    string
+	countryAbr string
+
+	currencyShort string
+	currencyLong  string
+
+	firstNames  []string
+	lastNames   []string
+	streetNames []string
+	cities      []string
+	regions     []region
+
+	// # is replaced with a digit and ? with an upper case letter
+	postcodeFormat string
+	phoneFormat    string
+
+	// {street}, {number}, {postcode}, {city}, {region} (the abbreviation) and {regionName} are replaced with their values
+	streetFormat  string
+	addressFormat string
+}
+
+var locales = map[string]*locale{
+	"en_US": {
+		country:        "United States of America",
+		countryAbr:     "US",
+		currencyShort:  "USD",
+		currencyLong:   "United States Dollar",
+		firstNames:     []string{"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth", "William", "Barbara"},
+		lastNames:      []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Wilson", "Anderson"},
+		streetNames:    []string{"Main Street", "Oak Avenue", "Maple Drive", "Cedar Lane", "Elm Street", "Washington Boulevard", "Park Avenue", "Lakeview Drive"},
+		cities:         []string{"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Philadelphia", "San Antonio", "Seattle", "Denver", "Boston"},
+		regions:        []region{{"California", "CA"}, {"Texas", "TX"}, {"New York", "NY"}, {"Florida", "FL"}, {"Illinois", "IL"}, {"Washington", "WA"}, {"Colorado", "CO"}, {"Massachusetts", "MA"}},
+		postcodeFormat: "#####",
+		phoneFormat:    "(###) ###-####",
+		streetFormat:   "{number} {street}",
+		addressFormat:  "{street}, {city}, {region} {postcode}",
+	},
+	"en_GB": {
+		country:        "United Kingdom of Great Britain and Northern Ireland",
+		countryAbr:     "GB",
+		currencyShort:  "GBP",
+		currencyLong:   "United Kingdom Pound",
+		firstNames:     []string{"Oliver", "Amelia", "George", "Isla", "Harry", "Ava", "Jack", "Emily", "Charlie", "Sophie", "Thomas", "Grace"},
+		lastNames:      []string{"Smith", "Jones", "Taylor", "Brown", "Williams", "Wilson", "Johnson", "Davies", "Robinson", "Wright", "Evans", "Walker"},
+		streetNames:    []string{"High Street", "Station Road", "Church Lane", "Victoria Road", "Green Lane", "Manor Road", "Park Road", "Kings Road"},
+		cities:         []string{"London", "Birmingham", "Manchester", "Leeds", "Glasgow", "Liverpool", "Bristol", "Edinburgh", "Cardiff", "Belfast"},
+		regions:        []region{{"Greater London", "LND"}, {"West Midlands", "WMD"}, {"Greater Manchester", "GTM"}, {"West Yorkshire", "WYK"}, {"Merseyside", "MSY"}, {"Kent", "KEN"}},
+		postcodeFormat: "??# #??",
+		phoneFormat:    "+44 20 #### ####",
+		streetFormat:   "{number} {street}",
+		addressFormat:  "{street}, {city} {postcode}",
+	},
+	"de_DE": {
+		country:        "Germany",
+		countryAbr:     "DE",
+		currencyShort:  "EUR",
+		currencyLong:   "Euro Member Countries",
+		firstNames:     []string{"Lukas", "Anna", "Leon", "Lena", "Maximilian", "Laura", "Felix", "Julia", "Jonas", "Sophie", "Paul", "Marie"},
+		lastNames:      []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann", "Koch", "Richter"},
+		streetNames:    []string{"Hauptstraße", "Bahnhofstraße", "Gartenstraße", "Schulstraße", "Dorfstraße", "Bergstraße", "Lindenstraße", "Goethestraße"},
+		cities:         []string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt am Main", "Stuttgart", "Düsseldorf", "Leipzig", "Dresden", "Hannover"},
+		regions:        []region{{"Berlin", "BE"}, {"Bayern", "BY"}, {"Hamburg", "HH"}, {"Hessen", "HE"}, {"Nordrhein-Westfalen", "NW"}, {"Sachsen", "SN"}, {"Baden-Württemberg", "BW"}, {"Niedersachsen", "NI"}},
+		postcodeFormat: "#####",
+		phoneFormat:    "+49 30 ########",
+		streetFormat:   "{street} {number}",
+		addressFormat:  "{street}, {postcode} {city}",
+	},
+	"fr_FR": {
+		country:        "France",
+		countryAbr:     "FR",
+		currencyShort:  "EUR",
+		currencyLong:   "Euro Member Countries",
+		firstNames:     []string{"Gabriel", "Louise", "Raphaël", "Jade", "Léo", "Ambre", "Louis", "Emma", "Lucas", "Alice", "Hugo", "Chloé"},
+		lastNames:      []string{"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau", "Simon", "Laurent"},
+		streetNames:    []string{"Rue de la République", "Rue Victor Hugo", "Avenue Jean Jaurès", "Rue de la Paix", "Boulevard Voltaire", "Rue Pasteur", "Place de la Mairie", "Rue du Moulin"},
+		cities:         []string{"Paris", "Marseille", "Lyon", "Toulouse", "Nice", "Nantes", "Strasbourg", "Montpellier", "Bordeaux", "Lille"},
+		regions:        []region{{"Île-de-France", "IDF"}, {"Auvergne-Rhône-Alpes", "ARA"}, {"Provence-Alpes-Côte d'Azur", "PAC"}, {"Occitanie", "OCC"}, {"Nouvelle-Aquitaine", "NAQ"}, {"Grand Est", "GES"}, {"Hauts-de-France", "HDF"}},
+		postcodeFormat: "#####",
+		phoneFormat:    "+33 1 ## ## ## ##",
+		streetFormat:   "{number} {street}",
+		addressFormat:  "{street}, {postcode} {city}",
+	},
+	"es_ES": {
+		country:        "Spain",
+		countryAbr:     "ES",
+		currencyShort:  "EUR",
+		currencyLong:   "Euro Member Countries",
+		firstNames:     []string{"Hugo", "Lucía", "Martín", "Sofía", "Pablo", "Martina", "Alejandro", "María", "Daniel", "Julia", "Mateo", "Paula"},
+		lastNames:      []string{"García", "Rodríguez", "González", "Fernández", "López", "Martínez", "Sánchez", "Pérez", "Gómez", "Martín", "Jiménez", "Ruiz"},
+		streetNames:    []string{"Calle Mayor", "Calle Real", "Avenida de la Constitución", "Calle de Alcalá", "Gran Vía", "Calle del Sol", "Plaza de España", "Calle Nueva"},
+		cities:         []string{"Madrid", "Barcelona", "Valencia", "Sevilla", "Zaragoza", "Málaga", "Murcia", "Palma", "Bilbao", "Alicante"},
+		regions:        []region{{"Madrid", "MD"}, {"Cataluña", "CT"}, {"Andalucía", "AN"}, {"Comunidad Valenciana", "VC"}, {"Aragón", "AR"}, {"País Vasco", "PV"}, {"Galicia", "GA"}},
+		postcodeFormat: "#####",
+		phoneFormat:    "+34 9## ### ###",
+		streetFormat:   "{street}, {number}",
+		addressFormat:  "{street}, {postcode} {city}",
+	},
+	"it_IT": {
+		country:        "Italy",
+		countryAbr:     "IT",
+		currencyShort:  "EUR",
+		currencyLong:   "Euro Member Countries",
+		firstNames:     []string{"Leonardo", "Sofia", "Francesco", "Giulia", "Alessandro", "Aurora", "Lorenzo", "Alice", "Mattia", "Ginevra", "Andrea", "Emma"},
+		lastNames:      []string{"Rossi", "Russo", "Ferrari", "Esposito", "Bianchi", "Romano", "Colombo", "Ricci", "Marino", "Greco", "Bruno", "Gallo"},
+		streetNames:    []string{"Via Roma", "Via Garibaldi", "Via Giuseppe Mazzini", "Corso Vittorio Emanuele", "Via Dante", "Piazza del Duomo", "Via Verdi", "Via Cavour"},
+		cities:         []string{"Roma", "Milano", "Napoli", "Torino", "Palermo", "Genova", "Bologna", "Firenze", "Bari", "Venezia"},
+		regions:        []region{{"Lazio", "RM"}, {"Lombardia", "MI"}, {"Campania", "NA"}, {"Piemonte", "TO"}, {"Sicilia", "PA"}, {"Toscana", "FI"}, {"Veneto", "VE"}},
+		postcodeFormat: "#####",
+		phoneFormat:    "+39 06 #### ####",
+		streetFormat:   "{street} {number}",
+		addressFormat:  "{street}, {postcode} {city} {region}",
+	},
+	"nl_NL": {
+		country:        "Netherlands",
+		countryAbr:     "NL",
+		currencyShort:  "EUR",
+		currencyLong:   "Euro Member Countries",
+		firstNames:     []string{"Noah", "Emma", "Liam", "Julia", "Lucas", "Mila", "Finn", "Tess", "Daan", "Sophie", "Sem", "Zoë"},
+		lastNames:      []string{"de Jong", "Jansen", "de Vries", "van den Berg", "van Dijk", "Bakker", "Janssen", "Visser", "Smit", "Meijer", "de Boer", "Mulder"},
+		streetNames:    []string{"Kerkstraat", "Schoolstraat", "Molenstraat", "Dorpsstraat", "Stationsweg", "Markt", "Nieuwstraat", "Julianastraat"},
+		cities:         []string{"Amsterdam", "Rotterdam", "Den Haag", "Utrecht", "Eindhoven", "Groningen", "Tilburg", "Almere", "Breda", "Nijmegen"},
+		regions:        []region{{"Noord-Holland", "NH"}, {"Zuid-Holland", "ZH"}, {"Utrecht", "UT"}, {"Noord-Brabant", "NB"}, {"Groningen", "GR"}, {"Gelderland", "GE"}},
+		postcodeFormat: "#### ??",
+		phoneFormat:    "+31 20 ### ####",
+		streetFormat:   "{street} {number}",
+		addressFormat:  "{street}, {postcode} {city}",
+	},
+	"en_AU": {
+		country:        "Australia",
+		countryAbr:     "AU",
+		currencyShort:  "AUD",
+		currencyLong:   "Australia Dollar",
+		firstNames:     []string{"Oliver", "Charlotte", "Noah", "Amelia", "Jack", "Isla", "William", "Olivia", "Leo", "Mia", "Henry", "Ava"},
+		lastNames:      []string{"Smith", "Jones", "Williams", "Brown", "Wilson", "Taylor", "Nguyen", "Johnson", "Martin", "White", "Anderson", "Walker"},
+		streetNames:    []string{"George Street", "Queen Street", "King Street", "Victoria Road", "Church Street", "Beach Road", "Pacific Highway", "High Street"},
+		cities:         []string{"Sydney", "Melbourne", "Brisbane", "Perth", "Adelaide", "Gold Coast", "Canberra", "Newcastle", "Hobart", "Darwin"},
+		regions:        []region{{"New South Wales", "NSW"}, {"Victoria", "VIC"}, {"Queensland", "QLD"}, {"Western Australia", "WA"}, {"South Australia", "SA"}, {"Tasmania", "TAS"}},
+		postcodeFormat: "####",
+		phoneFormat:    "+61 2 #### ####",
+		streetFormat:   "{number} {street}",
+		addressFormat:  "{street}, {city} {region} {postcode}",
+	},
+	"ja_JP": {
+		country:        "Japan",
+		countryAbr:     "JP",
+		currencyShort:  "JPY",
+		currencyLong:   "Japan Yen",
+		firstNames:     []string{"Haruto", "Himari", "Sota", "Yui", "Yuto", "Aoi", "Riku", "Sakura", "Minato", "Hina", "Ren", "Mei"},
+		lastNames:      []string{"Sato", "Suzuki", "Takahashi", "Tanaka", "Watanabe", "Ito", "Yamamoto", "Nakamura", "Kobayashi", "Kato", "Yoshida", "Yamada"},
+		streetNames:    []string{"Ginza", "Shibuya", "Umeda", "Sakae", "Tenjin", "Motomachi", "Chuo", "Minami"},
+		cities:         []string{"Tokyo", "Yokohama", "Osaka", "Nagoya", "Sapporo", "Fukuoka", "Kobe", "Kyoto", "Kawasaki", "Sendai"},
+		regions:        []region{{"Tokyo", "13"}, {"Kanagawa", "14"}, {"Osaka", "27"}, {"Aichi", "23"}, {"Hokkaido", "01"}, {"Fukuoka", "40"}, {"Kyoto", "26"}},
+		postcodeFormat: "###-####",
+		phoneFormat:    "+81 3-####-####",
+		streetFormat:   "{street} {number}",
+		addressFormat:  "{postcode} {regionName}, {city}, {street}",
+	},
+}
+
+// GetLocales returns the names of the locales that fake data can be generated for (e.g., de_DE)
+func GetLocales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// GetLocaleForCountry returns the locale for a country, either the code (e.g., DE) or the name (e.g., Germany)
+func GetLocaleForCountry(country string) (string, bool) {
+	country = strings.TrimSpace(country)
+
+	for name, l := range locales {
+		if strings.EqualFold(l.countryAbr, country) || strings.EqualFold(l.country, country) || strings.EqualFold(name, country) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// callLocaleFakeFunc returns the value of a function for a locale, and false if the function doesn't depend on the locale (e.g., Email)
+func callLocaleFakeFunc(s *random.Stream, l *locale, methodName string) (string, bool) {
+	switch methodName {
+	case "FirstName":
+		return pick(s, l.firstNames), true
+	case "LastName":
+		return pick(s, l.lastNames), true
+	case "Name":
+		return pick(s, l.firstNames) + " " + pick(s, l.lastNames), true
+	case "Street":
+		return l.street(s), true
+	case "StreetName":
+		return pick(s, l.streetNames), true
+	case "StreetNumber":
+		return strconv.Itoa(s.Intn(200) + 1), true
+	case "City":
+		return pick(s, l.cities), true
+	case "State":
+		return l.regions[s.Intn(len(l.regions))].name, true
+	case "StateAbr":
+		return l.regions[s.Intn(len(l.regions))].abr, true
+	case "Zip", "PostCode":
+		return fromFormat(s, l.postcodeFormat), true
+	case "Phone":
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, fromFormat(s, l.phoneFormat)), true
+	case "PhoneFormatted":
+		return fromFormat(s, l.phoneFormat), true
+	case "Country":
+		return l.country, true
+	case "CountryAbr":
+		return l.countryAbr, true
+	case "CurrencyShort":
+		return l.currencyShort, true
+	case "CurrencyLong":
+		return l.currencyLong, true
+	case "Address":
+		r := l.regions[s.Intn(len(l.regions))]
+		return strings.NewReplacer(
+			"{street}", l.street(s),
+			"{city}", pick(s, l.cities),
+			"{regionName}", r.name,
+			"{region}", r.abr,
+			"{postcode}", fromFormat(s, l.postcodeFormat),
+		).Replace(l.addressFormat), true
+	}
+
+	return "", false
+}
+
+func (l *locale) street(s *random.Stream) string {
+	return strings.NewReplacer(
+		"{street}", pick(s, l.streetNames),
+		"{number}", strconv.Itoa(s.Intn(200)+1),
+	).Replace(l.streetFormat)
+}
+
+func pick(s *random.Stream, values []string) string {
+	return values[s.Intn(len(values))]
+}
+
+// fromFormat replaces # in the format with a digit and ? with an upper case letter
+func fromFormat(s *random.Stream, format string) string {
+	sb := strings.Builder{}
+
+	for _, r := range format {
+		switch r {
+		case '#':
+			sb.WriteByte(byte('0' + s.Intn(10)))
+		case '?':
+			sb.WriteByte(byte('A' + s.Intn(26)))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
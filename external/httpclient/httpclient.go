@@ -199,7 +199,7 @@ var noApiEndpointUrlWarningMessageLogged = false
 // DoRequest makes a html request to the EPCC API and handles the response.
 func doRequestInternal(ctx context.Context, method string, contentType string, path string, query string, payload io.Reader) (response *http.Response, error error) {
 
-	if shutdown.ShutdownFlag.Load() {
+	if shutdown.ShutdownFlag.Load() && !shutdown.IsCleanup(ctx) {
 		return nil, fmt.Errorf("Shutting down")
 	}
 
@@ -326,7 +326,7 @@ func doRequestInternal(ctx context.Context, method string, contentType string, p
 		return nil, fmt.Errorf("rate limiter returned error %v, %w", err, err)
 	}
 
-	if shutdown.ShutdownFlag.Load() {
+	if shutdown.ShutdownFlag.Load() && !shutdown.IsCleanup(ctx) {
 		return nil, fmt.Errorf("Shutting down")
 	}
 
@@ -362,6 +362,14 @@ func doRequestInternal(ctx context.Context, method string, contentType string, p
 	requestNumber := stats.totalRequests
 	statsLock.Unlock()
 
+	if requestStats := RequestStatsFromContext(ctx); requestStats != nil {
+		if resp != nil {
+			requestStats.record(resp.StatusCode)
+		} else {
+			requestStats.record(0)
+		}
+	}
+
 	log.Tracef("Stats processing complete")
 	requestError := err
 	if requestError != nil {
@@ -0,0 +1,64 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+)
+
+type requestStatsKey struct{}
+
+// RequestStats counts the requests made with a context (e.g., by a command in a runbook)
+type RequestStats struct {
+	lock        sync.Mutex
+	total       int
+	statusCodes map[int]int
+}
+
+// WithRequestStats returns a context that counts the requests made with it in the stats
+func WithRequestStats(ctx context.Context, s *RequestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{}, s)
+}
+
+// RequestStatsFromContext returns the stats for the requests made with the context, or nil if they aren't counted
+func RequestStatsFromContext(ctx context.Context) *RequestStats {
+	if ctx == nil {
+		return nil
+	}
+
+	s, _ := ctx.Value(requestStatsKey{}).(*RequestStats)
+	return s
+}
+
+// record counts a request, connection errors have a status code of 0
+func (s *RequestStats) record(statusCode int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.statusCodes == nil {
+		s.statusCodes = map[int]int{}
+	}
+
+	s.total++
+	s.statusCodes[statusCode]++
+}
+
+// Total returns the number of requests
+func (s *RequestStats) Total() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.total
+}
+
+// StatusCodes returns the number of requests with each status code, connection errors have a status code of 0
+func (s *RequestStats) StatusCodes() map[int]int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	statusCodes := make(map[int]int, len(s.statusCodes))
+	for k, v := range s.statusCodes {
+		statusCodes[k] = v
+	}
+
+	return statusCodes
+}
@@ -6,4 +6,7 @@ type IdableAttributes struct {
 	Sku         string `yaml:"sku,omitempty" json:"sku,omitempty"`
 	Code        string `yaml:"code,omitempty" json:"code,omitempty"`
 	ExternalRef string `yaml:"external_ref,omitempty" json:"external_ref,omitempty"`
+
+	// If set, this alias is a legacy form (e.g., name=John_Smith) of the named alias (e.g., name=John Smith)
+	AlternateFor string `yaml:"alternate_for,omitempty" json:"-"`
 }
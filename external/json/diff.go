@@ -0,0 +1,248 @@
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	wincolor "github.com/gookit/color"
+)
+
+// JsonDifference is a single value that differs between two JSON documents
+type JsonDifference struct {
+	// A JSON pointer to the value (e.g., /data/attributes/name)
+	Path string
+
+	// The value in the first document, only set if InBefore is true
+	Before interface{}
+
+	// The value in the second document, only set if InAfter is true
+	After interface{}
+
+	InBefore bool
+
+	InAfter bool
+}
+
+var (
+	diffRemovedColor = newColor("31", "<red>")    // Red
+	diffAddedColor   = newColor("32", "<green>")  // Green
+	diffChangedColor = newColor("33", "<yellow>") // Yellow
+)
+
+// DiffJson returns the differences between two JSON documents. Values at (or under) an ignored JSON pointer are skipped, and
+// a * in an ignored pointer matches any key or array index (e.g., /data/relationships/*/data/id).
+func DiffJson(before string, after string, ignoredPointers []string) ([]JsonDifference, error) {
+	var b, a interface{}
+
+	if err := gojson.Unmarshal([]byte(before), &b); err != nil {
+		return nil, fmt.Errorf("could not parse first document: %w", err)
+	}
+
+	if err := gojson.Unmarshal([]byte(after), &a); err != nil {
+		return nil, fmt.Errorf("could not parse second document: %w", err)
+	}
+
+	ignored := make([][]string, 0, len(ignoredPointers))
+	for _, p := range ignoredPointers {
+		if p == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("ignored path %s is not a JSON pointer, it should start with /", p)
+		}
+
+		ignored = append(ignored, splitJsonPointer(p))
+	}
+
+	diffs := make([]JsonDifference, 0)
+	diffValues([]string{}, b, a, ignored, &diffs)
+
+	return diffs, nil
+}
+
+func diffValues(path []string, before interface{}, after interface{}, ignored [][]string, diffs *[]JsonDifference) {
+	if isIgnoredPath(path, ignored) {
+		return
+	}
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(b)+len(a))
+			for k := range b {
+				keys = append(keys, k)
+			}
+
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				childPath := append(path[:len(path):len(path)], k)
+				bv, inBefore := b[k]
+				av, inAfter := a[k]
+
+				switch {
+				case !inAfter:
+					if !isIgnoredPath(childPath, ignored) {
+						*diffs = append(*diffs, JsonDifference{Path: joinJsonPointer(childPath), Before: bv, InBefore: true})
+					}
+				case !inBefore:
+					if !isIgnoredPath(childPath, ignored) {
+						*diffs = append(*diffs, JsonDifference{Path: joinJsonPointer(childPath), After: av, InAfter: true})
+					}
+				default:
+					diffValues(childPath, bv, av, ignored, diffs)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			for i := 0; i < max(len(b), len(a)); i++ {
+				childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+
+				switch {
+				case i >= len(a):
+					if !isIgnoredPath(childPath, ignored) {
+						*diffs = append(*diffs, JsonDifference{Path: joinJsonPointer(childPath), Before: b[i], InBefore: true})
+					}
+				case i >= len(b):
+					if !isIgnoredPath(childPath, ignored) {
+						*diffs = append(*diffs, JsonDifference{Path: joinJsonPointer(childPath), After: a[i], InAfter: true})
+					}
+				default:
+					diffValues(childPath, b[i], a[i], ignored, diffs)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*diffs = append(*diffs, JsonDifference{Path: joinJsonPointer(path), Before: before, InBefore: true, After: after, InAfter: true})
+	}
+}
+
+func isIgnoredPath(path []string, ignored [][]string) bool {
+nextPointer:
+	for _, pointer := range ignored {
+		if len(pointer) != len(path) {
+			continue
+		}
+
+		for i, segment := range pointer {
+			if segment != "*" && segment != path[i] {
+				continue nextPointer
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func splitJsonPointer(pointer string) []string {
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+
+	return segments
+}
+
+func joinJsonPointer(path []string) string {
+	if len(path) == 0 {
+		return "/"
+	}
+
+	sb := strings.Builder{}
+	for _, s := range path {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+
+	return sb.String()
+}
+
+// PrintJsonDiff prints each difference on a line, prefixed with - if the value is only in the first document, + if it is only in
+// the second, and ~ if it has changed.
+func PrintJsonDiff(diffs []JsonDifference) error {
+	defer os.Stdout.Sync()
+	return printJsonDiffToWriter(diffs, shouldPrintMonochrome(), os.Stdout)
+}
+
+// WriteJsonDiff writes the differences without colors (e.g., for an error message)
+func WriteJsonDiff(diffs []JsonDifference, w io.Writer) error {
+	return printJsonDiffToWriter(diffs, true, w)
+}
+
+func printJsonDiffToWriter(diffs []JsonDifference, monoOutput bool, w io.Writer) error {
+	e := NewEncoder(false, 0, monoOutput)
+	line := &bytes.Buffer{}
+
+	for _, d := range diffs {
+		line.Reset()
+
+		switch {
+		case !d.InAfter:
+			writeDiffMarker(line, "-", d.Path, diffRemovedColor, monoOutput)
+			line.WriteString(encodeDiffValue(e, d.Before))
+		case !d.InBefore:
+			writeDiffMarker(line, "+", d.Path, diffAddedColor, monoOutput)
+			line.WriteString(encodeDiffValue(e, d.After))
+		default:
+			writeDiffMarker(line, "~", d.Path, diffChangedColor, monoOutput)
+			line.WriteString(encodeDiffValue(e, d.Before))
+			line.WriteString(" => ")
+			line.WriteString(encodeDiffValue(e, d.After))
+		}
+
+		line.WriteString("\n")
+
+		if monoOutput {
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+		} else {
+			wincolor.Fprint(w, line.String())
+		}
+	}
+
+	return nil
+}
+
+func writeDiffMarker(line *bytes.Buffer, marker string, path string, color colorInfo, monoOutput bool) {
+	if !monoOutput {
+		line.WriteString(color.colorString)
+	}
+
+	line.WriteString(marker + " " + path)
+
+	if !monoOutput {
+		line.WriteString(resetColor.colorString)
+	}
+
+	line.WriteString(": ")
+}
+
+// encodeDiffValue returns a value as compact JSON, coloured the same way as our other JSON output.
+func encodeDiffValue(e *encoder, v interface{}) string {
+	defer e.w.Reset()
+	e.encode(v)
+	return e.w.String()
+}
@@ -0,0 +1,90 @@
+package json
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffJsonWithSameDocumentsReturnsNoDifferences(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	before := `{"data": {"id": "1", "attributes": {"name": "Ring", "tags": ["a", "b"], "price": 5}}}`
+	// language=json
+	after := `{"data": {"attributes": {"price": 5.0, "tags": ["a", "b"], "name": "Ring"}, "id": "1"}}`
+
+	// Execute SUT
+	diffs, err := DiffJson(before, after, []string{})
+
+	// Verification
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+}
+
+func TestDiffJsonReturnsAddedRemovedAndChangedValues(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	before := `{"data": {"attributes": {"name": "Ring", "tags": ["a", "b"], "description": "Gold"}}}`
+	// language=json
+	after := `{"data": {"attributes": {"name": "Band", "tags": ["a"], "status": "live"}}}`
+
+	// Execute SUT
+	diffs, err := DiffJson(before, after, []string{})
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []JsonDifference{
+		{Path: "/data/attributes/description", Before: "Gold", InBefore: true},
+		{Path: "/data/attributes/name", Before: "Ring", InBefore: true, After: "Band", InAfter: true},
+		{Path: "/data/attributes/status", After: "live", InAfter: true},
+		{Path: "/data/attributes/tags/1", Before: "b", InBefore: true},
+	}, diffs)
+}
+
+func TestDiffJsonSkipsIgnoredPointers(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	before := `{"data": {"id": "1", "meta": {"timestamps": {"created_at": "2024"}}, "relationships": {"files": {"data": [{"id": "a"}]}}, "attributes": {"a~b/c": 1}}}`
+	// language=json
+	after := `{"data": {"id": "2", "meta": {"timestamps": {"created_at": "2025"}}, "relationships": {"files": {"data": [{"id": "b"}]}}, "attributes": {"a~b/c": 2}}}`
+
+	// Execute SUT
+	diffs, err := DiffJson(before, after, []string{"/data/id", "/data/meta/timestamps", "/data/relationships/*/data/*/id", "/data/attributes/a~0b~1c"})
+
+	// Verification
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+}
+
+func TestDiffJsonWithInvalidIgnoredPointerReturnsError(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, err := DiffJson(`{}`, `{}`, []string{"data.id"})
+
+	// Verification
+	require.ErrorContains(t, err, "is not a JSON pointer")
+}
+
+func TestPrintJsonDiffInMonochrome(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	before := `{"data": {"name": "Ring", "tags": ["a"], "price": {"amount": 5}}}`
+	// language=json
+	after := `{"data": {"name": "Band", "tags": ["a", "b"]}}`
+	buf := bytes.Buffer{}
+
+	diffs, err := DiffJson(before, after, []string{})
+	require.NoError(t, err)
+
+	// Execute SUT
+	err = printJsonDiffToWriter(diffs, true, &buf)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, `~ /data/name: "Ring" => "Band"
+- /data/price: {"amount":5}
++ /data/tags/1: "b"
+`, buf.String())
+}
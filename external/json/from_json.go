@@ -1,16 +1,22 @@
 package json
 
 import (
+	"bytes"
 	gojson "encoding/json"
 	"fmt"
+	"io"
 	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/yukithm/json2csv"
-	"github.com/yukithm/json2csv/jsonpointer"
 )
 
-// FromJson is the inverse operation of ToJson it converts a json object into the key value pairs we would type on the command line
+// FromJson is the inverse operation of ToJson it converts a json object into the key value pairs we would type on the command line.
+//
+// For a document with data, ToJson(FromJson(x)) is x (with compliant set if data has attributes), so:
+//
+// * Keys with dots or brackets in them are quoted (e.g., locales["en.US"].name)
+// * null, [] and {} are output as is
+// * Keys outside of data (e.g., links) or that ToJson would put somewhere else start with $. (e.g., $.links.self)
 func FromJson(json string) ([]string, error) {
 
 	o, err := FromJsonToMap(json)
@@ -40,71 +46,237 @@ func FromJsonToMap(json string) (map[string]string, error) {
 
 	out := map[string]string{}
 
-	err := gojson.Unmarshal([]byte(json), &obj)
+	dec := gojson.NewDecoder(strings.NewReader(json))
+	// Numbers are kept as they are, so that they can be output exactly
+	dec.UseNumber()
 
-	if err != nil {
+	if err := dec.Decode(&obj); err != nil {
 		return nil, err
 	}
 
-	kvs, err := json2csv.JSON2CSV(obj)
-	if err != nil {
-
-		return nil, fmt.Errorf("error during processing (csv): %v", err)
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
 
+	root, ok := obj.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("error during processing, expected a JSON object but got %T", obj)
 	}
 
-	if len(kvs) == 0 {
+	data, hasData := root["data"]
+
+	if !hasData {
+		// Without data, there is nothing for keys to be relative to
+		for k, v := range root {
+			if err := flattenToKeys(out, "", []keySegment{{name: k}}, v); err != nil {
+				return nil, err
+			}
+		}
+
 		return out, nil
 	}
 
-	if len(kvs) != 1 {
-		return nil, fmt.Errorf("more than one result came back")
+	for k, v := range root {
+		if k == "data" {
+			continue
+		}
+
+		if err := flattenToKeys(out, RootKeyPrefix, []keySegment{{name: k}}, v); err != nil {
+			return nil, err
+		}
+	}
+
+	switch d := data.(type) {
+	case map[string]any:
+		// ToJson always creates data, so there is nothing to output for {}
+		return out, flattenDataToKeys(out, d)
+	case []any:
+		if len(d) > 0 {
+			// The array syntax (e.g., [0].name)
+			return out, flattenToKeys(out, "", []keySegment{}, d)
+		}
+	}
+
+	return out, flattenToKeys(out, RootKeyPrefix, []keySegment{{name: "data"}}, data)
+}
+
+// FromJsonObjectToMap converts a json object into key value pairs like FromJsonToMap, but the keys are relative to the object
+// (e.g., an element of data in a get-all response), so attributes keep their prefix (e.g., attributes.name).
+func FromJsonObjectToMap(json string) (map[string]string, error) {
+	var obj any
+
+	dec := gojson.NewDecoder(strings.NewReader(json))
+	dec.UseNumber()
+
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
 	}
 
-	if len(kvs) != 1 {
-		return nil, fmt.Errorf("more than one result came back")
+	root, ok := obj.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("error during processing, expected a JSON object but got %T", obj)
 	}
 
-	for _, kv := range kvs {
+	out := map[string]string{}
 
-		keys := kv.Keys()
+	for k, v := range root {
+		if err := flattenToKeys(out, "", []keySegment{{name: k}}, v); err != nil {
+			return nil, err
+		}
+	}
 
-		sort.Strings(keys)
+	return out, nil
+}
+
+// flattenDataToKeys outputs the keys in data, compliant documents (i.e., where data has attributes) output attributes without the attributes prefix,
+// as that is what ToJson does when compliant is set.
+func flattenDataToKeys(out map[string]string, data map[string]any) error {
+	attributes, compliant := data["attributes"].(map[string]any)
+
+	for k, v := range data {
+		var prefix string
+		var key []keySegment
 
-		for _, k := range keys {
-			v := kv[k]
+		switch {
+		case !compliant && k == "included":
+			// Otherwise ToJson would move it out of data
+			prefix, key = RootKeyPrefix, []keySegment{{name: "data"}, {name: k}}
+		case !compliant:
+			key = []keySegment{{name: k}}
+		case k == "attributes":
+			if len(attributes) == 0 {
+				key = []keySegment{{name: k}}
+				break
+			}
+
+			for attributeName, attributeValue := range attributes {
+				attributeKey := []keySegment{{name: attributeName}}
 
-			jp, err := jsonpointer.New(k)
+				switch attributeName {
+				case "type", "id", "attributes", "relationships":
+					// Otherwise ToJson would put it outside of attributes
+					attributeKey = []keySegment{{name: "attributes"}, {name: attributeName}}
+				}
 
-			if err != nil {
-				return nil, fmt.Errorf("error during processing (jp): %v", err)
+				if err := flattenToKeys(out, "", attributeKey, attributeValue); err != nil {
+					return err
+				}
 			}
 
-			outKey := jp.DotNotation(true)
+			continue
+		case k == "relationships":
+			key = []keySegment{{name: k}}
+		case (k == "type" || k == "id") && isJsonScalar(v):
+			key = []keySegment{{name: k}}
+		default:
+			// e.g., meta and links, which ToJson would put in attributes
+			prefix, key = RootKeyPrefix, []keySegment{{name: "data"}, {name: k}}
+		}
 
-			// We need to check first for prefixes, because a key like data.attributes.data should end up like data
-			if strings.HasPrefix(outKey, "data.attributes.") {
-				outKey = strings.TrimPrefix(outKey, "data.attributes.")
-			} else if strings.HasPrefix(outKey, "data.") {
-				outKey = strings.TrimPrefix(outKey, "data.")
+		if err := flattenToKeys(out, prefix, key, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flattenToKeys outputs a key for every value in v that isn't an object or array (or is an empty one)
+func flattenToKeys(out map[string]string, prefix string, key []keySegment, v any) error {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) > 0 {
+			for k, child := range val {
+				if err := flattenToKeys(out, prefix, append(key[:len(key):len(key)], keySegment{name: k}), child); err != nil {
+					return err
+				}
 			}
 
-			if s, ok := v.(string); ok {
-				out[outKey] = fmt.Sprintf("\"%s\"", s)
-			} else if s, ok := v.(float64); ok {
-				if s == float64(int(s)) {
-					out[outKey] = fmt.Sprintf("%d", int(s))
-				} else {
-					out[outKey] = fmt.Sprintf("%g", s)
+			return nil
+		}
+	case []any:
+		if len(val) > 0 {
+			for i, child := range val {
+				if err := flattenToKeys(out, prefix, append(key[:len(key):len(key)], keySegment{index: i, isIndex: true}), child); err != nil {
+					return err
 				}
-			} else if s, ok := v.(bool); ok {
-				out[outKey] = fmt.Sprintf("%t", s)
-			} else {
-				return nil, fmt.Errorf("error during processing (jp value), unknown type (%T) for %v", v, v)
 			}
+
+			return nil
 		}
+	}
 
+	formatted, err := formatJsonLiteral(v)
+	if err != nil {
+		return fmt.Errorf("error during processing of %s: %w", prefix+formatKey(key), err)
 	}
 
-	return out, nil
+	out[prefix+formatKey(key)] = formatted
+	return nil
+}
+
+func isJsonScalar(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return false
+	default:
+		return true
+	}
+}
+
+// formatJsonLiteral returns the value as we would type it on the command line for ToJson
+func formatJsonLiteral(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		// The brace is escaped, so that the value isn't processed as a template
+		return strings.ReplaceAll(quoteString(val), "{{", `{\u007b`), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case gojson.Number:
+		return formatJsonNumber(val)
+	case map[string]any:
+		return "{}", nil
+	case []any:
+		return "[]", nil
+	default:
+		return "", fmt.Errorf("unknown type (%T) for %v", v, v)
+	}
+}
+
+// formatJsonNumber returns the number as it was in the JSON, except exponents (e.g., 1e21) which ToJson doesn't treat as numbers,
+// they are written out instead, with a decimal point so that they stay floating point numbers (e.g., 1000000000000000000000.0)
+func formatJsonNumber(n gojson.Number) (string, error) {
+	s := n.String()
+
+	if !strings.ContainsAny(s, "eE") {
+		return s, nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return "", err
+	}
+
+	s = strconv.FormatFloat(f, 'f', -1, 64)
+
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+
+	return s, nil
+}
+
+// quoteString returns a string as a JSON string literal (e.g., with quotes and new lines escaped)
+func quoteString(s string) string {
+	buf := bytes.Buffer{}
+	enc := gojson.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(s); err != nil {
+		return fmt.Sprintf("\"%s\"", s)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
 }
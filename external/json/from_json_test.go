@@ -83,3 +83,143 @@ func TestFromJsonWithSimpleObjectWithArrayAttributes(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, result, []string{"names[0]", `"Ron"`, "names[1]", `"Ulysses"`, "names[2]", `"Swanson"`, "type", `"account"`})
 }
+
+func TestFromJsonEscapesStrings(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{
+     "data": {
+        "description": "A \"quoted\" <b>value</b>\non two lines"
+    }
+  }`
+
+	// Execute SUT
+	result, err := FromJson(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"description", `"A \"quoted\" <b>value</b>\non two lines"`}, result)
+}
+
+func TestFromJsonKeepsNullsEmptyContainersAndNumbers(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{
+     "data": {
+        "description": null,
+        "tags": [],
+        "custom_inputs": {},
+        "price": 1.50,
+        "views": 12345678901234567890,
+        "weight": 1.5e-7,
+        "sku": ""
+    }
+  }`
+
+	// Execute SUT
+	result, err := FromJson(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"custom_inputs", "{}", "description", "null", "price", "1.50", "sku", `""`, "tags", "[]", "views", "12345678901234567890", "weight", "0.00000015"}, result)
+}
+
+func TestFromJsonQuotesKeysWithDotsAndBrackets(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{
+     "data": {
+        "locales": {"en.US": {"name": "Ring"}},
+        "key[0]": "value",
+        "$ref": "dollar",
+        "": "empty"
+    }
+  }`
+
+	// Execute SUT
+	result, err := FromJson(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{`[""]`, `"empty"`, `["$ref"]`, `"dollar"`, `["key[0]"]`, `"value"`, `locales["en.US"].name`, `"Ring"`}, result)
+}
+
+func TestFromJsonUsesRootKeysOutsideOfData(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{
+     "data": {
+        "type": "product",
+        "attributes": {
+           "name": "Ring",
+           "type": "physical"
+        },
+        "meta": {
+           "owner": "store"
+        }
+    },
+    "links": {
+      "self": "https://example.com"
+    }
+  }`
+
+	// Execute SUT
+	result, err := FromJson(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"$.data.meta.owner", `"store"`, "$.links.self", `"https://example.com"`, "attributes.type", `"physical"`, "name", `"Ring"`, "type", `"product"`}, result)
+}
+
+func TestFromJsonEscapesTemplates(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{"data": {"description": "Hello {{ .Name }}"}}`
+
+	// Execute SUT
+	result, err := FromJson(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"description", `"Hello {\u007b .Name }}"`}, result)
+}
+
+func TestFromJsonObjectToMapKeepsAttributesAndRoundTripsThroughToJson(t *testing.T) {
+	// Fixture Setup
+	//language=json
+	json := `{
+        "type": "pcm_product",
+        "attributes": {
+          "name": "Ring",
+          "sku": "12345",
+          "description": null,
+          "tags": [],
+          "custom_inputs": {},
+          "locales": {"en.US": {"name": "Ring"}}
+        }
+  }`
+
+	// Execute SUT
+	result, err := FromJsonObjectToMap(json)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"type":                             `"pcm_product"`,
+		"attributes.name":                  `"Ring"`,
+		"attributes.sku":                   `"12345"`,
+		"attributes.description":           "null",
+		"attributes.tags":                  "[]",
+		"attributes.custom_inputs":         "{}",
+		`attributes.locales["en.US"].name`: `"Ring"`,
+	}, result)
+
+	args := make([]string, 0, len(result)*2)
+	for k, v := range result {
+		args = append(args, k, v)
+	}
+
+	toJson, err := ToJson(args, false, true, map[string]*resources.CrudEntityAttribute{}, false, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data": `+json+`}`, toJson)
+}
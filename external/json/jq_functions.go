@@ -0,0 +1,149 @@
+package json
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elasticpath/epcc-cli/external/aliases"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/elasticpath/epcc-cli/external/templates"
+	"github.com/itchyny/gojq"
+)
+
+// The format the API uses for timestamps (e.g., meta.timestamps.created_at), so that now and ago() can be compared with them
+const jqTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+var jqNow = time.Now
+
+// Functions that are available in every jq query, in addition to the gojq builtins
+var jqCompilerOptions = []gojq.CompilerOption{
+	// .data[].id | alias("customer") returns the best alias for an id (e.g., name=John Smith)
+	gojq.WithFunction("alias", 1, 1, func(v any, args []any) any {
+		idStr, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("alias/1 should be given an id, but got: %v", v)
+		}
+
+		resourceType, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("alias/1 should be given a resource, but got: %v", args[0])
+		}
+
+		jsonApiType, alternateJsonApiTypes := getJsonApiTypesForJq(resourceType)
+
+		if alias, ok := aliases.GetBestAliasForId(jsonApiType, alternateJsonApiTypes, idStr); ok {
+			return alias
+		}
+
+		return nil
+	}),
+	// resolve("customer"; "name=John Smith") returns the id for an alias
+	gojq.WithFunction("resolve", 2, 2, func(v any, args []any) any {
+		resourceType, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("resolve/2 should be given a resource, but got: %v", args[0])
+		}
+
+		aliasName, ok := args[1].(string)
+		if !ok {
+			return fmt.Errorf("resolve/2 should be given an alias, but got: %v", args[1])
+		}
+
+		jsonApiType, alternateJsonApiTypes := getJsonApiTypesForJq(resourceType)
+
+		if resolved := aliases.ResolveAliasValuesOrReturnIdentity(jsonApiType, alternateJsonApiTypes, aliasName, "id"); resolved != aliasName {
+			return resolved
+		}
+
+		return nil
+	}),
+	// money(.amount; .currency) formats an amount in the smallest unit of the currency (e.g., 1000 and USD is $10.00)
+	gojq.WithFunction("money", 2, 2, func(v any, args []any) any {
+		switch args[0].(type) {
+		case int, float64:
+		default:
+			return fmt.Errorf("money/2 should be given a number, but got: %v", args[0])
+		}
+
+		currency, ok := args[1].(string)
+		if !ok {
+			return fmt.Errorf("money/2 should be given a currency, but got: %v", args[1])
+		}
+
+		return templates.FormatPrice(currency, args[0])
+	}),
+	// env("HOME") returns the value of an environment variable, or null if it is not set
+	gojq.WithFunction("env", 1, 1, func(v any, args []any) any {
+		name, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("env/1 should be given a string, but got: %v", args[0])
+		}
+
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+
+		return nil
+	}),
+	// api_now returns the current time as a timestamp that can be compared with ones from the API (the gojq builtin now returns seconds since the epoch)
+	gojq.WithFunction("api_now", 0, 0, func(v any, args []any) any {
+		return jqNow().UTC().Format(jqTimestampFormat)
+	}),
+	// ago("5m") returns the time 5 minutes ago as a timestamp that can be compared with ones from the API
+	gojq.WithFunction("ago", 1, 1, func(v any, args []any) any {
+		durationStr, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("ago/1 should be given a duration, but got: %v", args[0])
+		}
+
+		duration, err := parseJqDuration(durationStr)
+		if err != nil {
+			return fmt.Errorf("ago/1 could not parse duration %s: %w", durationStr, err)
+		}
+
+		return jqNow().UTC().Add(-duration).Format(jqTimestampFormat)
+	}),
+}
+
+func compileJQ(queryStr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(queryStr)
+
+	if err != nil {
+		// %w causes the error to be wrapped.
+		return nil, fmt.Errorf("error parsing json key %s: %w", queryStr, err)
+	}
+
+	code, err := gojq.Compile(query, jqCompilerOptions...)
+
+	if err != nil {
+		return nil, fmt.Errorf("error compiling json key %s: %w", queryStr, err)
+	}
+
+	return code, nil
+}
+
+// getJsonApiTypesForJq returns the types to use for aliases, the argument can be a resource (e.g., customers) or a JSON API type.
+func getJsonApiTypesForJq(resourceType string) (string, []string) {
+	if resource, ok := resources.GetResourceByName(resourceType); ok {
+		return resource.JsonApiType, resource.AlternateJsonApiTypesForAliases
+	}
+
+	return resourceType, []string{}
+}
+
+// parseJqDuration parses a Go duration (e.g., 1h30m), with support for days (e.g., 7d)
+func parseJqDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		d, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(d) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
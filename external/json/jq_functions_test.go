@@ -0,0 +1,103 @@
+package json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elasticpath/epcc-cli/external/aliases"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJqAliasAndResolveUseAliases(t *testing.T) {
+	// Fixture Setup
+	err := aliases.ClearAllAliases()
+	require.NoError(t, err)
+
+	aliases.SaveAliasesForResources(
+		// language=JSON
+		`{"data": {"id": "123", "type": "customer", "name": "John Smith", "email": "john@example.com"}}`)
+
+	// Execute SUT
+	result, err := RunJQOnStringWithArray(`.data[].id | alias("customers"), resolve("customer"; "email=john@example.com"), resolve("customer"; "name=Jane")`, `{"data": [{"id": "123"}]}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"name=John Smith", "123", nil}, result)
+}
+
+func TestJqMoneyFormatsAmountInCurrency(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	result, err := RunJQOnString(`.data.price | money(.amount; .currency)`, `{"data": {"price": {"amount": 123456, "currency": "USD"}}}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, "$1,234.56", result)
+}
+
+func TestJqEnvReturnsEnvironmentVariable(t *testing.T) {
+	// Fixture Setup
+	t.Setenv("EPCC_JQ_TEST", "foo")
+
+	// Execute SUT
+	result, err := RunJQOnStringWithArray(`env("EPCC_JQ_TEST"), env("EPCC_JQ_TEST_UNSET"), ($ENV | has("EPCC_JQ_TEST"))`, `{}`)
+
+	// Verification
+	require.NoError(t, err)
+	// The whole environment (e.g., EPCC_CLIENT_SECRET) isn't in $ENV
+	require.Equal(t, []interface{}{"foo", nil, false}, result)
+}
+
+func TestJqAgoReturnsTimestamp(t *testing.T) {
+	// Fixture Setup
+	jqNow = func() time.Time {
+		return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	}
+	defer func() { jqNow = time.Now }()
+
+	// Execute SUT
+	result, err := RunJQOnStringWithArray(`ago("5m"), ago("2d"), ([.data[] | select(.created_at > ago("1h")) | .id])`,
+		`{"data": [{"id": "1", "created_at": "2024-03-10T11:30:00.000Z"}, {"id": "2", "created_at": "2024-03-09T11:30:00.000Z"}]}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"2024-03-10T11:55:00.000Z", "2024-03-08T12:00:00.000Z", []interface{}{"1"}}, result)
+}
+
+func TestJqApiNowCanBeComparedWithAgoAndTimestamps(t *testing.T) {
+	// Fixture Setup
+	jqNow = func() time.Time {
+		return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	}
+	defer func() { jqNow = time.Now }()
+
+	// Execute SUT
+	result, err := RunJQOnStringWithArray(`api_now, (api_now > ago("5m")), ([.data[] | select(.updated_at < api_now) | .id])`,
+		`{"data": [{"id": "1", "updated_at": "2024-03-10T11:30:00.000Z"}, {"id": "2", "updated_at": "2024-03-10T12:30:00.000Z"}]}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"2024-03-10T12:00:00.000Z", true, []interface{}{"1"}}, result)
+}
+
+func TestJqAgoWithInvalidDurationReturnsError(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, err := RunJQOnString(`ago("soon")`, `{}`)
+
+	// Verification
+	require.ErrorContains(t, err, "could not parse duration soon")
+}
+
+func TestJqNowIsTheGojqBuiltin(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	result, err := RunJQOnStringWithArray(`(now | type), (now - 3600 | todate | type)`, `{}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"number", "string"}, result)
+}
@@ -0,0 +1,140 @@
+package json
+
+import (
+	gojson "encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RootKeyPrefix marks a key that starts at the root of the document instead of data (e.g., $.links.self or $.meta.results.total)
+const RootKeyPrefix = "$."
+
+var keyIndex = regexp.MustCompile(`^\[([0-9]+)]`)
+
+// arrayKey matches keys that use the array syntax (e.g., [0].name)
+var arrayKey = regexp.MustCompile(`^\[[0-9]`)
+
+// keySegment is a single part of a key, either the name of a property or an index into an array
+type keySegment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parseKey splits a key into segments, names are separated by dots (e.g., attributes.name), array indexes use brackets (e.g., tags[0])
+// and names with dots or brackets in them can be quoted (e.g., locales["en.US"].name).
+func parseKey(key string) []keySegment {
+	segments := make([]keySegment, 0)
+
+	name := strings.Builder{}
+
+	endName := func() {
+		if name.Len() > 0 {
+			segments = append(segments, keySegment{name: name.String()})
+			name.Reset()
+		}
+	}
+
+	for i := 0; i < len(key); {
+		switch key[i] {
+		case '.':
+			endName()
+			i++
+			continue
+		case '[':
+			rest := key[i:]
+
+			if m := keyIndex.FindStringSubmatch(rest); m != nil {
+				if idx, err := strconv.Atoi(m[1]); err == nil {
+					endName()
+					segments = append(segments, keySegment{index: idx, isIndex: true})
+					i += len(m[0])
+					continue
+				}
+			}
+
+			if quotedName, length, ok := parseQuotedKeyName(rest); ok {
+				endName()
+				segments = append(segments, keySegment{name: quotedName})
+				i += length
+				continue
+			}
+		}
+
+		// Anything else (including a [ that isn't an index or a quoted name) is part of the name
+		name.WriteByte(key[i])
+		i++
+	}
+
+	endName()
+
+	return segments
+}
+
+// parseQuotedKeyName parses a quoted name at the start of s (e.g., ["en.US"]) and returns the name and the length of the quoted name in s
+func parseQuotedKeyName(s string) (string, int, bool) {
+	if !strings.HasPrefix(s, `["`) {
+		return "", 0, false
+	}
+
+	dec := gojson.NewDecoder(strings.NewReader(s[1:]))
+
+	var name string
+	if err := dec.Decode(&name); err != nil {
+		return "", 0, false
+	}
+
+	end := 1 + int(dec.InputOffset())
+	if end >= len(s) || s[end] != ']' {
+		return "", 0, false
+	}
+
+	return name, end + 1, true
+}
+
+// formatKey is the inverse of parseKey, names are only quoted when they need to be
+func formatKey(segments []keySegment) string {
+	sb := strings.Builder{}
+
+	for i, segment := range segments {
+		switch {
+		case segment.isIndex:
+			sb.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case keyNameNeedsQuotes(segment.name, i == 0):
+			sb.WriteString("[" + quoteString(segment.name) + "]")
+		default:
+			if i > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(segment.name)
+		}
+	}
+
+	return sb.String()
+}
+
+func keyNameNeedsQuotes(name string, first bool) bool {
+	if name == "" || strings.ContainsAny(name, ".[") {
+		return true
+	}
+
+	// Otherwise the key could look like it starts at the root
+	return first && strings.HasPrefix(name, "$")
+}
+
+// jqPathForKey returns the jq path for a key (e.g., attributes.tags[0] is .["attributes"]["tags"][0])
+func jqPathForKey(key string) string {
+	sb := strings.Builder{}
+	sb.WriteString(".")
+
+	for _, segment := range parseKey(key) {
+		if segment.isIndex {
+			sb.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		} else {
+			sb.WriteString("[" + quoteString(segment.name) + "]")
+		}
+	}
+
+	return sb.String()
+}
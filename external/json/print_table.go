@@ -0,0 +1,201 @@
+package json
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The narrowest we will truncate a column to, when the table doesn't fit in the terminal
+const minimumColumnWidth = 8
+
+func PrintJsonAsTable(json string, columns []string) error {
+	defer os.Stdout.Sync()
+
+	width := 0
+	if !shouldPrintMonochrome() || os.Getenv("COLUMNS") != "" {
+		width = getTerminalWidth()
+	}
+
+	return printJsonAsTableToWriter(json, columns, width, os.Stdout)
+}
+
+// printJsonAsTableToWriter prints each object in data (or the root if there is no data) as a row, if width is greater than zero
+// the columns are truncated to fit.
+func printJsonAsTableToWriter(json string, columns []string, width int, w io.Writer) error {
+	if json == "" {
+		return nil
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns to print, use --columns to select some")
+	}
+
+	var v interface{}
+	if err := gojson.Unmarshal([]byte(json), &v); err != nil {
+		return fmt.Errorf("could not convert response to a table: %w", err)
+	}
+
+	queries := make([]string, 0, len(columns))
+	for _, c := range columns {
+		queries = append(queries, getColumnQuery(c))
+	}
+
+	table := [][]string{columns}
+
+	for _, row := range getTableRows(v) {
+		cells := make([]string, 0, len(columns))
+
+		for i, q := range queries {
+			result, err := RunJQWithArray(q, row)
+
+			if err != nil {
+				return fmt.Errorf("could not get value for column %s: %w", columns[i], err)
+			}
+
+			values := make([]string, 0, len(result))
+			for _, r := range result {
+				values = append(values, formatTableCell(r))
+			}
+
+			cells = append(cells, strings.Join(values, ","))
+		}
+
+		table = append(table, cells)
+	}
+
+	widths := getColumnWidths(table, width)
+
+	for _, row := range table {
+		line := make([]string, 0, len(row))
+
+		for i, cell := range row {
+			cell = truncateTableCell(cell, widths[i])
+
+			if i < len(row)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+
+			line = append(line, cell)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(line, "  "), " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getColumnQuery converts a column into a jq query, columns can be a JSON pointer (e.g., /attributes/name), a jq path (e.g., .attributes.name)
+// or a path without the leading . (e.g., attributes.name)
+func getColumnQuery(column string) string {
+	switch {
+	case strings.HasPrefix(column, "/"):
+		query := ""
+		for _, segment := range strings.Split(strings.TrimPrefix(column, "/"), "/") {
+			segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+
+			if _, err := strconv.Atoi(segment); err == nil {
+				query += "[" + segment + "]"
+			} else {
+				query += "[" + strconv.Quote(segment) + "]"
+			}
+		}
+		return "." + query + "?"
+	case strings.HasPrefix(column, "."):
+		return column
+	default:
+		// Names are quoted, as jq doesn't allow some characters in them after a . (e.g., attributes.display-name)
+		query := ""
+		for _, segment := range parseKey(column) {
+			if segment.isIndex {
+				query += "[" + strconv.Itoa(segment.index) + "]"
+			} else {
+				query += "[" + strconv.Quote(segment.name) + "]"
+			}
+		}
+		return "." + query + "?"
+	}
+}
+
+func getTableRows(v interface{}) []interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		if data, ok := m["data"]; ok {
+			v = data
+		}
+	}
+
+	if rows, ok := v.([]interface{}); ok {
+		return rows
+	}
+
+	return []interface{}{v}
+}
+
+func formatTableCell(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ReplaceAll(t, "\n", " ")
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		b, err := gojson.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// getColumnWidths returns the width of each column, shrinking the widest columns until the table fits (if maxWidth > 0)
+func getColumnWidths(table [][]string, maxWidth int) []int {
+	widths := make([]int, len(table[0]))
+
+	for _, row := range table {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	if maxWidth <= 0 {
+		return widths
+	}
+
+	separators := 2 * (len(widths) - 1)
+
+	for {
+		total := separators
+		widest := 0
+
+		for i, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+
+		if total <= maxWidth || widths[widest] <= minimumColumnWidth {
+			return widths
+		}
+
+		widths[widest] = max(minimumColumnWidth, widths[widest]-(total-maxWidth))
+	}
+}
+
+func truncateTableCell(cell string, width int) string {
+	if utf8.RuneCountInString(cell) <= width {
+		return cell
+	}
+
+	runes := []rune(cell)
+	return string(runes[0:width-1]) + "…"
+}
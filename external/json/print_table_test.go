@@ -0,0 +1,86 @@
+package json
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintJsonAsTableWithCollection(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	body := `{"data": [
+	  {"id": "1", "attributes": {"name": "Ring", "tags": ["a", "b"]}, "meta": {"count": 3}},
+	  {"id": "2", "attributes": {"name": "Band"}}
+	]}`
+	buf := bytes.Buffer{}
+
+	// Execute SUT
+	err := printJsonAsTableToWriter(body, []string{"id", "/attributes/name", ".attributes.tags", "meta.count"}, 0, &buf)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, `id  /attributes/name  .attributes.tags  meta.count
+1   Ring              ["a","b"]         3
+2   Band
+`, buf.String())
+}
+
+func TestPrintJsonAsTableWithColumnsThatNeedQuotingInJq(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	body := `{"data": [
+	  {"id": "1", "attributes": {"display-name": "Ring", "locales": {"en.US": {"name": "Ring (US)"}}, "tags": ["a", "b"]}},
+	  {"id": "2", "attributes": {"display-name": "Band"}}
+	]}`
+	buf := bytes.Buffer{}
+
+	// Execute SUT
+	err := printJsonAsTableToWriter(body, []string{"id", "attributes.display-name", `attributes.locales["en.US"].name`, "attributes.tags[1]"}, 0, &buf)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, `id  attributes.display-name  attributes.locales["en.US"].name  attributes.tags[1]
+1   Ring                     Ring (US)                         b
+2   Band
+`, buf.String())
+}
+
+func TestPrintJsonAsTableWithSingleObjectTruncatesToWidth(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	body := `{"data": {"id": "1", "description": "A very long description that will not fit in the terminal"}}`
+	buf := bytes.Buffer{}
+
+	// Execute SUT
+	err := printJsonAsTableToWriter(body, []string{"id", "description"}, 20, &buf)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, `id  description
+1   A very long des…
+`, buf.String())
+}
+
+func TestPrintJsonAsYamlPreservesOrder(t *testing.T) {
+	// Fixture Setup
+	// language=json
+	body := `{"data": {"type": "product", "id": "1", "attributes": {"sku": "123", "enabled": "true", "tags": [], "price": 5}}}`
+	buf := bytes.Buffer{}
+
+	// Execute SUT
+	err := printJsonAsYamlToWriter(body, &buf)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, `data:
+  type: product
+  id: "1"
+  attributes:
+    sku: "123"
+    enabled: "true"
+    tags: []
+    price: 5
+`, buf.String())
+}
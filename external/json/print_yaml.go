@@ -0,0 +1,46 @@
+package json
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+func PrintJsonAsYaml(json string) error {
+	defer os.Stdout.Sync()
+	return printJsonAsYamlToWriter(json, os.Stdout)
+}
+
+func printJsonAsYamlToWriter(json string, w io.Writer) error {
+	if json == "" {
+		return nil
+	}
+
+	// JSON is valid YAML, so we parse it as a node to preserve the order of keys
+	node := yaml.Node{}
+	if err := yaml.Unmarshal([]byte(json), &node); err != nil {
+		return fmt.Errorf("could not convert response to yaml: %w", err)
+	}
+
+	useBlockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("could not convert response to yaml: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// useBlockStyle removes the flow style and quoting that comes from JSON, the encoder will still quote strings that need it.
+func useBlockStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, c := range node.Content {
+		useBlockStyle(c)
+	}
+}
@@ -0,0 +1,143 @@
+package json
+
+import (
+	gojson "encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+// Responses from the API, and the ones the wiremock tests use
+var roundTripFixtureGlobs = []string{"testdata/responses/*.json", "../../wiremock/files/*.json"}
+
+func TestFromJsonToJsonRoundTripForResponseFixtures(t *testing.T) {
+	for _, fixture := range getRoundTripFixtures(t) {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			// Fixture Setup
+			contents, err := os.ReadFile(fixture)
+			require.NoError(t, err)
+
+			// Execute SUT
+			result, err := roundTripThroughKeyValues(string(contents))
+
+			// Verification
+			require.NoError(t, err)
+			require.Equal(t, canonicalizeJson(t, string(contents)), canonicalizeJson(t, result))
+		})
+	}
+}
+
+func FuzzFromJsonToJsonRoundTrip(f *testing.F) {
+	for _, fixture := range getRoundTripFixtures(f) {
+		contents, err := os.ReadFile(fixture)
+		require.NoError(f, err)
+		f.Add(string(contents))
+	}
+
+	f.Add(`{"data": {"a": {"b.c": [1.0, -0, "", null, [], {}]}}}`)
+	f.Add(`{"data": {"attributes": {"type": "t", "$": {"[0]": "{{"}}, "id": {"": 1e2}}}`)
+	f.Add(`{"data": [[{"x": true}], [], 3], "included": [{"data": {}}]}`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var doc map[string]interface{}
+		if err := gojson.Unmarshal([]byte(input), &doc); err != nil {
+			t.Skip("not a JSON object")
+		}
+
+		if _, ok := doc["data"]; !ok {
+			t.Skip("not a document with data")
+		}
+
+		if _, err := FromJson(input); err != nil {
+			// e.g., numbers that are too large
+			t.Skip(err.Error())
+		}
+
+		result, err := roundTripThroughKeyValues(input)
+
+		require.NoError(t, err)
+		require.Equal(t, canonicalizeJson(t, input), canonicalizeJson(t, result))
+	})
+}
+
+// roundTripThroughKeyValues returns ToJson(FromJson(json))
+func roundTripThroughKeyValues(json string) (string, error) {
+	args, err := FromJson(json)
+	if err != nil {
+		return "", err
+	}
+
+	var doc struct {
+		Data interface{} `json:"data"`
+	}
+
+	if err := gojson.Unmarshal([]byte(json), &doc); err != nil {
+		return "", err
+	}
+
+	compliant := false
+	if data, ok := doc.Data.(map[string]interface{}); ok {
+		_, compliant = data["attributes"].(map[string]interface{})
+	}
+
+	return ToJson(args, false, compliant, map[string]*resources.CrudEntityAttribute{}, false, false)
+}
+
+func getRoundTripFixtures(t testing.TB) []string {
+	fixtures := make([]string, 0)
+
+	for _, glob := range roundTripFixtureGlobs {
+		matches, err := filepath.Glob(glob)
+		require.NoError(t, err)
+		fixtures = append(fixtures, matches...)
+	}
+
+	require.NotEmpty(t, fixtures)
+
+	return fixtures
+}
+
+// canonicalizeJson decodes JSON so that equal documents are equal, integers are compared exactly and other numbers as float64s (e.g., 1.50 and 1.5 are equal)
+func canonicalizeJson(t testing.TB, json string) interface{} {
+	dec := gojson.NewDecoder(strings.NewReader(json))
+	dec.UseNumber()
+
+	var v interface{}
+	require.NoError(t, dec.Decode(&v))
+
+	return canonicalizeJsonValue(t, v)
+}
+
+func canonicalizeJsonValue(t testing.TB, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = canonicalizeJsonValue(t, child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = canonicalizeJsonValue(t, child)
+		}
+	case gojson.Number:
+		if i, ok := new(big.Int).SetString(val.String(), 10); ok {
+			return i.String()
+		}
+
+		f, err := val.Float64()
+		require.NoError(t, err)
+
+		if i, accuracy := big.NewFloat(f).Int(nil); accuracy == big.Exact {
+			return i.String()
+		}
+
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return v
+}
@@ -0,0 +1,145 @@
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamJsonArrays reads a JSON object (e.g., a collection response) and calls fn with each element of the arrays at the top level (e.g., data),
+// one at a time, so that the whole document never needs to be in memory. The other top level values (e.g., meta and links) are returned.
+func StreamJsonArrays(r io.Reader, fn func(key string, element gojson.RawMessage) error) (map[string]gojson.RawMessage, error) {
+	dec := gojson.NewDecoder(r)
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("could not read JSON: %w", err)
+	}
+
+	if tok != gojson.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object but got %v", tok)
+	}
+
+	others := map[string]gojson.RawMessage{}
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("could not read JSON: %w", err)
+		}
+
+		key, ok := keyTok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a key but got %v", keyTok)
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("could not read value of %s: %w", key, err)
+		}
+
+		if tok != gojson.Delim('[') {
+			buf := bytes.Buffer{}
+			if err := writeJsonValueFromTokens(dec, tok, &buf); err != nil {
+				return nil, fmt.Errorf("could not read value of %s: %w", key, err)
+			}
+			others[key] = buf.Bytes()
+			continue
+		}
+
+		for dec.More() {
+			element := gojson.RawMessage{}
+
+			if err := dec.Decode(&element); err != nil {
+				return nil, fmt.Errorf("could not read element of %s: %w", key, err)
+			}
+
+			if err := fn(key, element); err != nil {
+				return nil, err
+			}
+		}
+
+		// Closing ]
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("could not read end of %s: %w", key, err)
+		}
+	}
+
+	// Closing }
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("could not read end of JSON: %w", err)
+	}
+
+	return others, nil
+}
+
+// writeJsonValueFromTokens writes the JSON value that starts with tok, the decoder has already consumed tok so we rebuild the value from
+// the remaining tokens.
+func writeJsonValueFromTokens(dec *gojson.Decoder, tok gojson.Token, buf *bytes.Buffer) error {
+	switch tok {
+	case gojson.Delim('{'):
+		buf.WriteByte('{')
+
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			if err := writeJsonValueFromTokens(dec, key, buf); err != nil {
+				return err
+			}
+
+			buf.WriteByte(':')
+
+			value, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			if err := writeJsonValueFromTokens(dec, value, buf); err != nil {
+				return err
+			}
+		}
+
+		_, err := dec.Token()
+		buf.WriteByte('}')
+		return err
+	case gojson.Delim('['):
+		buf.WriteByte('[')
+
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			value, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			if err := writeJsonValueFromTokens(dec, value, buf); err != nil {
+				return err
+			}
+		}
+
+		_, err := dec.Token()
+		buf.WriteByte(']')
+		return err
+	default:
+		// Strings, numbers (json.Number), booleans and null
+		b, err := gojson.Marshal(tok)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(b)
+		return nil
+	}
+}
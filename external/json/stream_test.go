@@ -0,0 +1,124 @@
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukithm/json2csv"
+)
+
+func TestStreamJsonArraysCallsFunctionForEachElementAndReturnsOtherValues(t *testing.T) {
+	// Fixture Setup
+	input := `{"data": [{"id": "1", "tags": ["a", {"b": null}]}, "two", 3], "meta": {"results": {"total": 3}, "empty": {}}, "links": null, "included": []}`
+
+	elements := make([]string, 0)
+
+	// Execute SUT
+	others, err := StreamJsonArrays(strings.NewReader(input), func(key string, element gojson.RawMessage) error {
+		elements = append(elements, key+"="+string(element))
+		return nil
+	})
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{`data={"id": "1", "tags": ["a", {"b": null}]}`, `data="two"`, `data=3`}, elements)
+	require.Len(t, others, 2)
+	require.JSONEq(t, `{"results": {"total": 3}, "empty": {}}`, string(others["meta"]))
+	require.Equal(t, `null`, string(others["links"]))
+}
+
+func TestStreamJsonArraysReturnsErrorForInvalidJson(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, err := StreamJsonArrays(strings.NewReader(`{"data": [{"id": "1"}, `), func(key string, element gojson.RawMessage) error {
+		return nil
+	})
+
+	// Verification
+	require.Error(t, err)
+}
+
+func TestStreamJsonArraysReturnsErrorIfNotAnObject(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, err := StreamJsonArrays(strings.NewReader(`[{"id": "1"}]`), func(key string, element gojson.RawMessage) error {
+		return nil
+	})
+
+	// Verification
+	require.ErrorContains(t, err, "expected a JSON object")
+}
+
+func TestJsonArrayWriterWritesSameOutputAsMarshal(t *testing.T) {
+	// Fixture Setup
+	objs := []interface{}{
+		map[string]interface{}{"data": map[string]interface{}{"id": "1", "name": "<Foo & Bar>"}},
+		map[string]interface{}{"data": map[string]interface{}{"id": "2", "price": 1.5}},
+	}
+
+	expected, err := gojson.Marshal(objs)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	writer := NewJsonArrayWriter(buf)
+
+	// Execute SUT
+	for _, obj := range objs {
+		require.NoError(t, writer.Write(obj))
+	}
+	require.NoError(t, writer.Close())
+
+	// Verification
+	require.Equal(t, string(expected)+"\n", buf.String())
+}
+
+func TestJsonArrayWriterWithNoObjectsWritesEmptyArray(t *testing.T) {
+	// Fixture Setup
+	buf := &bytes.Buffer{}
+	writer := NewJsonArrayWriter(buf)
+
+	// Execute SUT
+	err := writer.Close()
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", buf.String())
+}
+
+func TestCsvWriterWritesSameOutputAsJson2Csv(t *testing.T) {
+	// Fixture Setup
+	var objs []interface{}
+	err := gojson.Unmarshal([]byte(`[
+		{"data": {"id": "1", "name": "Foo, Inc", "tags": ["a", "b"], "meta": {"count": 10}}},
+		{"data": {"id": "2", "price": {"amount": 1.5, "includes_tax": true}, "aa": "x"}},
+		{"data": {"id": "3", "tags": ["a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"]}}
+	]`), &objs)
+	require.NoError(t, err)
+
+	results, err := json2csv.JSON2CSV(objs)
+	require.NoError(t, err)
+
+	expected := &bytes.Buffer{}
+	json2csvWriter := json2csv.NewCSVWriter(expected)
+	json2csvWriter.HeaderStyle = json2csv.DotBracketStyle
+	require.NoError(t, json2csvWriter.WriteCSV(results))
+
+	buf := &bytes.Buffer{}
+	writer, err := NewCsvWriter(buf)
+	require.NoError(t, err)
+
+	// Execute SUT
+	for _, obj := range objs {
+		require.NoError(t, writer.Write(obj))
+	}
+	err = writer.Close()
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), buf.String())
+}
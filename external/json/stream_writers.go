@@ -0,0 +1,193 @@
+package json
+
+import (
+	"bufio"
+	"encoding/csv"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yukithm/json2csv"
+	"github.com/yukithm/json2csv/jsonpointer"
+)
+
+// JsonArrayWriter writes objects as a single JSON array, one at a time, so that the array is never in memory.
+type JsonArrayWriter struct {
+	w     io.Writer
+	count int
+}
+
+func NewJsonArrayWriter(w io.Writer) *JsonArrayWriter {
+	return &JsonArrayWriter{w: w}
+}
+
+func (j *JsonArrayWriter) Write(obj interface{}) error {
+	b, err := gojson.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("could not create JSON for %v: %w", obj, err)
+	}
+
+	separator := ","
+	if j.count == 0 {
+		separator = "["
+	}
+
+	j.count++
+
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+
+	_, err = j.w.Write(b)
+	return err
+}
+
+// Close ends the array, the writer that was passed in is not closed.
+func (j *JsonArrayWriter) Close() error {
+	end := "]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// CsvWriter writes objects as CSV with a column for every value (in dot bracket notation, e.g., data.tags[0]). The columns aren't known
+// until every object has been seen, so objects are saved to a temporary file instead of being kept in memory, and the CSV is written on Close.
+type CsvWriter struct {
+	w        io.Writer
+	spool    *os.File
+	spoolBuf *bufio.Writer
+	keys     map[string]bool
+	pointers []jsonpointer.JSONPointer
+}
+
+func NewCsvWriter(w io.Writer) (*CsvWriter, error) {
+	spool, err := os.CreateTemp("", "epcc-cli-csv-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("could not create temporary file for CSV: %w", err)
+	}
+
+	return &CsvWriter{
+		w:        w,
+		spool:    spool,
+		spoolBuf: bufio.NewWriter(spool),
+		keys:     map[string]bool{},
+	}, nil
+}
+
+func (c *CsvWriter) Write(obj interface{}) error {
+	kvs, err := json2csv.JSON2CSV(obj)
+	if err != nil {
+		return fmt.Errorf("could not convert to CSV: %w", err)
+	}
+
+	for _, kv := range kvs {
+		for _, k := range kv.Keys() {
+			if c.keys[k] {
+				continue
+			}
+
+			pointer, err := jsonpointer.New(k)
+			if err != nil {
+				return err
+			}
+
+			c.keys[k] = true
+			c.pointers = append(c.pointers, pointer)
+		}
+	}
+
+	b, err := gojson.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("could not create JSON for %v: %w", obj, err)
+	}
+
+	if _, err := c.spoolBuf.Write(b); err != nil {
+		return err
+	}
+
+	return c.spoolBuf.WriteByte('\n')
+}
+
+// Close writes the CSV and removes the temporary file, the writer that was passed in is not closed.
+func (c *CsvWriter) Close() error {
+	defer func() {
+		c.spool.Close()
+		if err := os.Remove(c.spool.Name()); err != nil {
+			log.Warnf("Could not remove temporary file %s: %v", c.spool.Name(), err)
+		}
+	}()
+
+	if err := c.spoolBuf.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := c.spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// Same order as json2csv, shallow keys first and then by each part of the key
+	sort.Slice(c.pointers, func(i, j int) bool {
+		a, b := c.pointers[i], c.pointers[j]
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+
+		for n := range a {
+			if a[n] != b[n] {
+				return a[n] < b[n]
+			}
+		}
+
+		return false
+	})
+
+	header := make([]string, 0, len(c.pointers))
+	for _, p := range c.pointers {
+		header = append(header, p.DotNotation(true))
+	}
+
+	csvWriter := csv.NewWriter(c.w)
+
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	dec := gojson.NewDecoder(c.spool)
+
+	for dec.More() {
+		var obj interface{}
+		if err := dec.Decode(&obj); err != nil {
+			return fmt.Errorf("could not read temporary file for CSV: %w", err)
+		}
+
+		kvs, err := json2csv.JSON2CSV(obj)
+		if err != nil {
+			return fmt.Errorf("could not convert to CSV: %w", err)
+		}
+
+		for _, kv := range kvs {
+			record := make([]string, 0, len(c.pointers))
+
+			for _, p := range c.pointers {
+				if value, ok := kv[p.String()]; ok {
+					record = append(record, fmt.Sprintf("%v", value))
+				} else {
+					record = append(record, "")
+				}
+			}
+
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
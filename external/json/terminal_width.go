@@ -0,0 +1,15 @@
+package json
+
+import (
+	"os"
+	"strconv"
+)
+
+// getTerminalWidth returns the width of the terminal, or 0 if it isn't known
+func getTerminalWidth() int {
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+
+	return getTerminalWidthFromOs()
+}
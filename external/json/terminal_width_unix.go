@@ -0,0 +1,19 @@
+//go:build !windows
+
+package json
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func getTerminalWidthFromOs() int {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+
+	if err != nil {
+		return 0
+	}
+
+	return int(ws.Col)
+}
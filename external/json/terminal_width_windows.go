@@ -0,0 +1,19 @@
+//go:build windows
+
+package json
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func getTerminalWidthFromOs() int {
+	info := windows.ConsoleScreenBufferInfo{}
+
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return 0
+	}
+
+	return int(info.Window.Right-info.Window.Left) + 1
+}
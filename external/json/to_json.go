@@ -1,6 +1,7 @@
 package json
 
 import (
+	"context"
 	gojson "encoding/json"
 	"fmt"
 	"regexp"
@@ -10,16 +11,18 @@ import (
 	"github.com/elasticpath/epcc-cli/external/aliases"
 	"github.com/elasticpath/epcc-cli/external/resources"
 	"github.com/elasticpath/epcc-cli/external/templates"
-	"github.com/itchyny/gojq"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 )
 
-var segmentRegex = regexp.MustCompile("(.+?)(\\[[0-9]+])?$")
-
 var attributeWithArrayIndex = regexp.MustCompile("\\[[0-9]+]")
 
 func ToJson(args []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool, autoAddConstantValues bool) (string, error) {
+	return ToJsonWithContext(context.Background(), args, noWrapping, compliant, attributes, useAliases, autoAddConstantValues)
+}
+
+// ToJsonWithContext is ToJson, with random values in templates from the stream in the context
+func ToJsonWithContext(ctx context.Context, args []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool, autoAddConstantValues bool) (string, error) {
 
 	if len(args)%2 == 1 {
 		if log.IsLevelEnabled(log.DebugLevel) {
@@ -44,15 +47,26 @@ func ToJson(args []string, noWrapping bool, compliant bool, attributes map[strin
 		return "", fmt.Errorf("the number of arguments %d supplied isn't even, json should be passed in key value pairs. Do you have an extra/missing id? **Tip**: Use --log debug to see a column aligned dump", len(args))
 	}
 
+	// Keys starting with $. are set on the whole document after everything else
+	dataArgs := make([]string, 0, len(args))
+	rootArgs := make([]string, 0)
+
 	firstArrayKeyIdx := -1
 	firstFieldKeyIdx := -1
 	for i := 0; i < len(args); i += 2 {
 		key := args[i]
-		if strings.HasPrefix(key, "[") {
+		if strings.HasPrefix(key, RootKeyPrefix) {
+			rootArgs = append(rootArgs, key, args[i+1])
+			continue
+		}
+
+		if arrayKey.MatchString(key) {
 			firstArrayKeyIdx = i
 		} else {
 			firstFieldKeyIdx = i
 		}
+
+		dataArgs = append(dataArgs, key, args[i+1])
 	}
 
 	if firstArrayKeyIdx >= 0 && firstFieldKeyIdx >= 0 {
@@ -60,13 +74,37 @@ func ToJson(args []string, noWrapping bool, compliant bool, attributes map[strin
 	}
 
 	if firstArrayKeyIdx >= 0 {
-		return toJsonArray(args, noWrapping, compliant, attributes, useAliases)
+		return toJsonArray(ctx, dataArgs, rootArgs, noWrapping, compliant, attributes, useAliases)
 	} else {
-		return toJsonObject(args, noWrapping, compliant, attributes, useAliases, autoAddConstantValues)
+		return toJsonObject(ctx, dataArgs, rootArgs, noWrapping, compliant, attributes, useAliases, autoAddConstantValues)
 	}
 }
 
-func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool, autoAddConstantValues bool) (string, error) {
+// setRootKeys sets keys relative to the root of the document (e.g., $.meta.results.total)
+func setRootKeys(ctx context.Context, result interface{}, args []string) (interface{}, error) {
+	for i := 0; i < len(args); i += 2 {
+		key := strings.TrimPrefix(args[i], RootKeyPrefix)
+		val := templates.RenderWithContext(ctx, args[i+1])
+
+		if sourcedVal, isSourced, err := resolveValueSource(val); err != nil {
+			return nil, fmt.Errorf("could not get value for %s: %w", args[i], err)
+		} else if isSourced {
+			val = sourcedVal
+		} else {
+			val = formatValue(val)
+		}
+
+		var err error
+		result, err = RunJQ(fmt.Sprintf("%s=%s", jqPathForKey(key), val), result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func toJsonObject(ctx context.Context, args []string, rootArgs []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool, autoAddConstantValues bool) (string, error) {
 
 	var result interface{} = make(map[string]interface{})
 
@@ -77,7 +115,7 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 	for k, v := range attributes {
 		if strings.HasPrefix(v.Type, "CONST:") {
 			val := strings.TrimSpace(strings.Replace(v.Type, "CONST:", "", 1))
-			val = templates.Render(val)
+			val = templates.RenderWithContext(ctx, val)
 			val = formatValue(val)
 
 			constAttributes[k] = val
@@ -93,13 +131,19 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 		val := args[i+1]
 
 		// Try and process the argument as a helm template
-		val = templates.Render(val)
+		val = templates.RenderWithContext(ctx, val)
+
+		sourcedVal, isSourced, err := resolveValueSource(val)
+		if err != nil {
+			return "", fmt.Errorf("could not get value for %s: %w", key, err)
+		}
 
 		jsonKey := key
 		switch {
 		case key == "type" || key == "id":
 			// These should always be in the root json object
-		case strings.HasPrefix(key, "attributes.") || strings.HasPrefix(key, "relationships."):
+		case key == "attributes" || key == "relationships" || strings.HasPrefix(key, "attributes.") || strings.HasPrefix(key, "relationships.") ||
+			strings.HasPrefix(key, "attributes[") || strings.HasPrefix(key, "relationships["):
 			// We won't double encode these.
 		case compliant:
 			jsonKey = fmt.Sprintf("attributes.%s", key)
@@ -144,7 +188,9 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 			}
 		}
 
-		if useAttribute {
+		if isSourced {
+			val = sourcedVal
+		} else if useAttribute {
 			if strings.HasPrefix(attributeInfo.Type, "RESOURCE_ID:") {
 				resourceType := strings.Replace(attributeInfo.Type, "RESOURCE_ID:", "", 1)
 
@@ -162,18 +208,17 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 				}
 			}
 		} else {
-			splitAlias := strings.Split(val, "/")
-
-			if len(splitAlias) == 4 {
-				if splitAlias[0] == "alias" {
-					if useAliases {
-						val = aliases.ResolveAliasValuesOrReturnIdentity(splitAlias[1], []string{}, splitAlias[2], splitAlias[3])
-					}
+			if jsonApiType, aliasName, attribute, ok := aliases.ParseFullyQualifiedAlias(val); ok {
+				if useAliases {
+					val = aliases.ResolveAliasValuesOrReturnIdentity(jsonApiType, []string{}, aliasName, attribute)
 				}
 			}
 		}
 
-		val = formatValue(val)
+		if !isSourced {
+			val = formatValue(val)
+		}
+
 		processedArgs = append(processedArgs, jsonKey, val)
 	}
 
@@ -189,13 +234,7 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 		k := argsWithConsts[i]
 		val := argsWithConsts[i+1]
 
-		arrayNotationPath := ""
-
-		for _, str := range strings.Split(k, ".") {
-			arrayNotationPath += segmentRegex.ReplaceAllString(str, "[\"$1\"]$2")
-		}
-
-		query := fmt.Sprintf(".%s=%s", arrayNotationPath, val)
+		query := fmt.Sprintf("%s=%s", jqPathForKey(k), val)
 
 		result, err = RunJQ(query, result)
 		if err != nil {
@@ -226,13 +265,18 @@ func toJsonObject(args []string, noWrapping bool, compliant bool, attributes map
 
 	}
 
+	result, err = setRootKeys(ctx, result, rootArgs)
+	if err != nil {
+		return "{}", err
+	}
+
 	jsonStr, err := gojson.Marshal(result)
 
 	return string(jsonStr), err
 
 }
 
-func toJsonArray(args []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool) (string, error) {
+func toJsonArray(ctx context.Context, args []string, rootArgs []string, noWrapping bool, compliant bool, attributes map[string]*resources.CrudEntityAttribute, useAliases bool) (string, error) {
 
 	var result interface{} = make([]interface{}, 0)
 
@@ -244,9 +288,15 @@ func toJsonArray(args []string, noWrapping bool, compliant bool, attributes map[
 
 		jsonKey := key
 
-		val = formatValue(val)
+		if sourcedVal, isSourced, err := resolveValueSource(val); err != nil {
+			return "[]", fmt.Errorf("could not get value for %s: %w", key, err)
+		} else if isSourced {
+			val = sourcedVal
+		} else {
+			val = formatValue(val)
+		}
 
-		query := fmt.Sprintf(".%s |= %s", jsonKey, val)
+		query := fmt.Sprintf("%s |= %s", jqPathForKey(jsonKey), val)
 
 		result, err = RunJQ(query, result)
 		if err != nil {
@@ -259,6 +309,11 @@ func toJsonArray(args []string, noWrapping bool, compliant bool, attributes map[
 		result, err = RunJQ(`{ "data": . }`, result)
 	}
 
+	result, err = setRootKeys(ctx, result, rootArgs)
+	if err != nil {
+		return "[]", err
+	}
+
 	jsonStr, err := gojson.Marshal(result)
 
 	return string(jsonStr), err
@@ -303,14 +358,13 @@ func RunJQOnStringAndMarshalResponse(queryStr string, json string, obj any) erro
 }
 
 func RunJQ(queryStr string, result interface{}) (interface{}, error) {
-	query, err := gojq.Parse(queryStr)
+	code, err := compileJQ(queryStr)
 
 	if err != nil {
-		// %w causes the error to be wrapped.
-		return nil, fmt.Errorf("error parsing json key %s: %w", queryStr, err)
+		return nil, err
 	}
 
-	iter := query.Run(result)
+	iter := code.Run(result)
 
 	for {
 		v, ok := iter.Next()
@@ -345,14 +399,13 @@ func RunJQOnStringWithArray(queryStr string, json string) ([]interface{}, error)
 }
 
 func RunJQWithArray(queryStr string, result interface{}) ([]interface{}, error) {
-	query, err := gojq.Parse(queryStr)
+	code, err := compileJQ(queryStr)
 
 	if err != nil {
-		// %w causes the error to be wrapped.
-		return nil, fmt.Errorf("error parsing json key %s: %w", queryStr, err)
+		return nil, err
 	}
 
-	iter := query.Run(result)
+	iter := code.Run(result)
 
 	queryResult := []interface{}{}
 
@@ -374,10 +427,12 @@ func RunJQWithArray(queryStr string, result interface{}) ([]interface{}, error)
 
 var TreatAsLiterals = regexp.MustCompile("^(-?[0-9]+(\\.[0-9]+)?|false|true|null)$")
 
-var QuotedString = regexp.MustCompile("^\\\".+\\\"$")
+var QuotedString = regexp.MustCompile("^\\\".*\\\"$")
 
 var EmptyArray = regexp.MustCompile("^\\[]$")
 
+var EmptyObject = regexp.MustCompile("^{}$")
+
 func formatValue(v string) string {
 	if match := TreatAsLiterals.MatchString(v); match {
 		return v
@@ -385,6 +440,8 @@ func formatValue(v string) string {
 		return v
 	} else if match := EmptyArray.MatchString(v); match {
 		return v
+	} else if match := EmptyObject.MatchString(v); match {
+		return v
 	} else {
 		v = strings.ReplaceAll(v, "\\", "\\\\")
 		v = strings.ReplaceAll(v, `"`, `\"`)
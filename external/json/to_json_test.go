@@ -758,3 +758,42 @@ func TestToJsonLegacyFormatIncludedKeyNotPromoted(t *testing.T) {
 		t.Fatalf("Testing json conversion of empty value %s did not match expected %s, actually: %s", input, expected, actual)
 	}
 }
+
+func TestToJsonLegacyFormatQuotedKeys(t *testing.T) {
+	// Fixture Setup
+	input := []string{`locales["en.US"].name`, "Ring", `["key[0]"]`, "value", "tags[0][1]", "a"}
+	expected := `{"data":{"key[0]":"value","locales":{"en.US":{"name":"Ring"}},"tags":[[null,"a"]]}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonCompliantFormatRootKeysAndEmptyValues(t *testing.T) {
+	// Fixture Setup
+	input := []string{"name", `""`, "tags", "[]", "custom_inputs", "{}", "$.data.meta.owner", "store", "$.links.self", "https://example.com", "type", "product"}
+	expected := `{"data":{"attributes":{"custom_inputs":{},"name":"","tags":[]},"meta":{"owner":"store"},"type":"product"},"links":{"self":"https://example.com"}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, true, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonArraySyntaxWithRootKeys(t *testing.T) {
+	// Fixture Setup
+	input := []string{"[0].id", "123", "$.meta.results.total", "1"}
+	expected := `{"data":[{"id":123}],"meta":{"results":{"total":1}}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
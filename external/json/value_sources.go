@@ -0,0 +1,111 @@
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Where values using @- are read from
+var stdin io.Reader = os.Stdin
+
+var stdinOnce sync.Once
+var stdinContents []byte
+var stdinErr error
+
+// resolveValueSource returns the JSON literal for a value that should be read from somewhere else:
+//
+// @path/to/file - the contents of the file as a string
+// @json:path/to/file.json - the contents of the file as JSON
+// @yaml:path/to/file.yaml - the contents of the file converted to JSON
+// @- - stdin as a string (also @json:- and @yaml:-)
+// @@value - the literal value @value
+//
+// The second return value is false if the value doesn't use a value source and should be processed normally.
+func resolveValueSource(val string) (string, bool, error) {
+	if len(val) < 2 || val[0] != '@' {
+		return val, false, nil
+	}
+
+	if strings.HasPrefix(val, "@@") {
+		return formatValue(val[1:]), true, nil
+	}
+
+	switch {
+	case strings.HasPrefix(val, "@json:"):
+		contents, err := readValueSource(strings.TrimPrefix(val, "@json:"))
+		if err != nil {
+			return "", true, err
+		}
+
+		decoder := gojson.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return "", true, fmt.Errorf("could not parse %s as json: %w", val, err)
+		}
+
+		return marshalValueSource(val, v)
+	case strings.HasPrefix(val, "@yaml:"):
+		contents, err := readValueSource(strings.TrimPrefix(val, "@yaml:"))
+		if err != nil {
+			return "", true, err
+		}
+
+		var v interface{}
+		if err := yaml.Unmarshal(contents, &v); err != nil {
+			return "", true, fmt.Errorf("could not parse %s as yaml: %w", val, err)
+		}
+
+		return marshalValueSource(val, v)
+	default:
+		contents, err := readValueSource(val[1:])
+		if err != nil {
+			return "", true, err
+		}
+
+		// Files (and echo) almost always end in a new line, that nobody wants in the value.
+		s := strings.TrimSuffix(strings.TrimSuffix(string(contents), "\n"), "\r")
+
+		return marshalValueSource(val, s)
+	}
+}
+
+func readValueSource(path string) ([]byte, error) {
+	if path == "-" {
+		stdinOnce.Do(func() {
+			stdinContents, stdinErr = io.ReadAll(stdin)
+		})
+
+		if stdinErr != nil {
+			return nil, fmt.Errorf("could not read value from stdin: %w", stdinErr)
+		}
+
+		return stdinContents, nil
+	}
+
+	contents, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read value from file %s (use @@ for values that start with a literal @): %w", path, err)
+	}
+
+	return contents, nil
+}
+
+func marshalValueSource(val string, v interface{}) (string, bool, error) {
+	jsonBytes, err := gojson.Marshal(v)
+
+	if err != nil {
+		return "", true, fmt.Errorf("could not convert %s to json: %w", val, err)
+	}
+
+	return string(jsonBytes), true, nil
+}
@@ -0,0 +1,108 @@
+package json
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJsonReadsStringValueFromFile(t *testing.T) {
+	// Fixture Setup
+	file := filepath.Join(t.TempDir(), "description.txt")
+	require.NoError(t, os.WriteFile(file, []byte("Line \"one\"\nLine two\n"), 0600))
+
+	input := []string{"description", "@" + file}
+	expected := `{"data":{"description":"Line \"one\"\nLine two"}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonEmbedsJsonAndYamlFiles(t *testing.T) {
+	// Fixture Setup
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "inputs.json")
+	yamlFile := filepath.Join(dir, "inputs.yaml")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"engraving": {"required": true, "max": 12345678901234567890}}`), 0600))
+	require.NoError(t, os.WriteFile(yamlFile, []byte("- a\n- b: 1\n"), 0600))
+
+	input := []string{"custom_inputs", "@json:" + jsonFile, "tags", "@yaml:" + yamlFile}
+	expected := `{"data":{"attributes":{"custom_inputs":{"engraving":{"max":12345678901234567890,"required":true}},"tags":["a",{"b":1}]}}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, true, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonReadsValueFromStdinOnce(t *testing.T) {
+	// Fixture Setup
+	stdin = strings.NewReader("from stdin\n")
+	stdinOnce = sync.Once{}
+	defer func() {
+		stdin = os.Stdin
+		stdinOnce = sync.Once{}
+	}()
+
+	input := []string{"a", "@-", "b", "@-"}
+	expected := `{"data":{"a":"from stdin","b":"from stdin"}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonEscapedAtIsLiteral(t *testing.T) {
+	// Fixture Setup
+	input := []string{"handle", "@@ring", "at", "@"}
+	expected := `{"data":{"at":"@","handle":"@ring"}}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonArrayReadsValueFromFile(t *testing.T) {
+	// Fixture Setup
+	file := filepath.Join(t.TempDir(), "item.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"id": "1"}`), 0600))
+
+	input := []string{"[0]", "@json:" + file}
+	expected := `{"data":[{"id":"1"}]}`
+
+	// Execute SUT
+	actual, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestToJsonReportsKeyWhenFileIsMissing(t *testing.T) {
+	// Fixture Setup
+	input := []string{"description", "@/does/not/exist.txt"}
+
+	// Execute SUT
+	_, err := ToJson(input, false, false, map[string]*resources.CrudEntityAttribute{}, true, true)
+
+	// Verification
+	require.ErrorContains(t, err, "could not get value for description")
+	require.ErrorContains(t, err, "use @@ for values that start with a literal @")
+}
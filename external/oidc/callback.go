@@ -72,7 +72,7 @@ func GetCallbackData(ctx context.Context, port uint16, r *http.Request) (*Callba
 				"oauth_authorization_code", data["code"],
 				"oauth_redirect_uri", fmt.Sprintf("http://localhost:%d/callback", port),
 				"oauth_code_verifier", verifier.Value,
-			), false, "", true, false, "")
+			), false, "", true, false, true, "")
 
 			if err != nil {
 				return nil, fmt.Errorf("could not get account tokens: %w", err)
@@ -102,7 +102,7 @@ func GetCallbackData(ctx context.Context, port uint16, r *http.Request) (*Callba
 				"oauth_authorization_code", data["code"],
 				"oauth_redirect_uri", fmt.Sprintf("http://localhost:%d/callback", port),
 				"oauth_code_verifier", verifier.Value,
-			), false, "", true, false, "")
+			), false, "", true, false, true, "")
 
 			if err != nil {
 				return nil, fmt.Errorf("could not get customer tokens: %w", err)
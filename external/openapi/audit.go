@@ -0,0 +1,422 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+)
+
+const (
+	AuditUnmappedOperation     = "unmapped-operation"
+	AuditUnknownOperationId    = "unknown-operation-id"
+	AuditAttributeNotInSpec    = "attribute-not-in-spec"
+	AuditPropertyNotInResource = "property-not-in-resource"
+	AuditEnumMismatch          = "enum-mismatch"
+	AuditMissingQueryParameter = "missing-query-parameter"
+)
+
+const (
+	SuggestAttribute   = "attribute"
+	SuggestQuery       = "query"
+	SuggestOperationId = "openapi-operation-id"
+)
+
+// AuditFinding is a difference between a resource definition and the OpenAPI specs.
+type AuditFinding struct {
+	Kind          string
+	Resource      string
+	SourceFile    string
+	OperationType string
+	OperationId   string
+	Key           string
+	Message       string
+
+	// A suggested change to the resource yaml, if one can be made
+	Suggestion *AuditSuggestion
+}
+
+type AuditSuggestion struct {
+	// One of SuggestAttribute, SuggestQuery or SuggestOperationId
+	Kind  string
+	Key   string
+	Value string
+}
+
+var urlVariableRegex = regexp.MustCompile(`\{[^}]*}`)
+
+// AuditResources compares resource definitions against the OpenAPI specs. If includeUnmappedOperations is set, operations
+// in the specs that no resource references are reported as well.
+func AuditResources(resourcesToAudit map[string]resources.Resource, includeUnmappedOperations bool) ([]AuditFinding, error) {
+	allOperationIds, err := GetAllOperationIDs()
+
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]AuditFinding, 0)
+	referencedOperationIds := map[string]bool{}
+
+	for _, resource := range resources.GetPluralResources() {
+		for _, info := range getResourceOperations(resource) {
+			if info.crud.OpenApiOperationId != "" {
+				referencedOperationIds[info.crud.OpenApiOperationId] = true
+			}
+		}
+	}
+
+	for _, resourceName := range sortedResourceNames(resourcesToAudit) {
+		resource := resourcesToAudit[resourceName]
+
+		for _, info := range getResourceOperations(resource) {
+			opId := info.crud.OpenApiOperationId
+
+			newFinding := func(kind, key, message string, suggestion *AuditSuggestion) AuditFinding {
+				return AuditFinding{
+					Kind:          kind,
+					Resource:      resourceName,
+					SourceFile:    resource.SourceFile,
+					OperationType: info.operationType,
+					OperationId:   opId,
+					Key:           key,
+					Message:       message,
+					Suggestion:    suggestion,
+				}
+			}
+
+			if opId == "" {
+				continue
+			}
+
+			if _, ok := allOperationIds[opId]; !ok {
+				findings = append(findings, newFinding(AuditUnknownOperationId, "", fmt.Sprintf("operation id %s does not exist in any OpenAPI spec", opId), nil))
+				continue
+			}
+
+			opInfo, err := FindOperationByID(opId)
+
+			if err != nil {
+				return nil, err
+			}
+
+			findings = append(findings, auditQueryParameters(info, opId, newFinding)...)
+
+			if info.operationType == "create-entity" || info.operationType == "update-entity" {
+				findings = append(findings, auditRequestSchema(resource, info, opInfo, newFinding)...)
+			}
+		}
+	}
+
+	if includeUnmappedOperations {
+		findings = append(findings, auditUnmappedOperations(allOperationIds, referencedOperationIds)...)
+	}
+
+	return findings, nil
+}
+
+type resourceOperation struct {
+	operationType string
+	crud          *resources.CrudEntityInfo
+}
+
+func getResourceOperations(resource resources.Resource) []resourceOperation {
+	result := make([]resourceOperation, 0, 5)
+
+	for _, op := range []resourceOperation{
+		{"get-collection", resource.GetCollectionInfo},
+		{"get-entity", resource.GetEntityInfo},
+		{"create-entity", resource.CreateEntityInfo},
+		{"update-entity", resource.UpdateEntityInfo},
+		{"delete-entity", resource.DeleteEntityInfo},
+	} {
+		if op.crud != nil {
+			result = append(result, op)
+		}
+	}
+
+	return result
+}
+
+func auditQueryParameters(info resourceOperation, opId string, newFinding func(kind, key, message string, suggestion *AuditSuggestion) AuditFinding) []AuditFinding {
+	findings := make([]AuditFinding, 0)
+
+	queryParams, err := GetQueryParametersForOperation(opId)
+
+	if err != nil {
+		return findings
+	}
+
+	existing := map[string]bool{}
+	for _, q := range info.crud.QueryParameters {
+		existing[q.Name] = true
+	}
+
+	for _, q := range queryParams {
+		if !existing[q.Name] {
+			findings = append(findings, newFinding(AuditMissingQueryParameter, q.Name,
+				fmt.Sprintf("query parameter %s (%s) is not in the resource", q.Name, q.EpccCliType),
+				&AuditSuggestion{Kind: SuggestQuery, Key: q.Name, Value: q.EpccCliType}))
+		}
+	}
+
+	return findings
+}
+
+func auditRequestSchema(resource resources.Resource, info resourceOperation, opInfo *OperationInfo, newFinding func(kind, key, message string, suggestion *AuditSuggestion) AuditFinding) []AuditFinding {
+	findings := make([]AuditFinding, 0)
+
+	schema := GetRequestSchema(opInfo.Operation)
+
+	if schema == nil {
+		return findings
+	}
+
+	flattened := FlattenSchema(schema)
+
+	regexAttributes := make([]*regexp.Regexp, 0)
+
+	for _, key := range sortedAttributeNames(resource.Attributes) {
+		attribute := resource.Attributes[key]
+
+		if strings.HasPrefix(key, "^") {
+			if r, err := regexp.Compile(key); err == nil {
+				regexAttributes = append(regexAttributes, r)
+			}
+			continue
+		}
+
+		path := GetRequestPathForAttribute(resource, key)
+
+		if !IsPathAllowedBySchema(flattened, path) {
+			findings = append(findings, newFinding(AuditAttributeNotInSpec, key, fmt.Sprintf("attribute %s (%s) is not in the request schema", key, path), nil))
+			continue
+		}
+
+		if strings.HasPrefix(attribute.Type, "ENUM:") {
+			for _, s := range flattened[path] {
+				specValues := GetEnumValues(s)
+
+				if len(specValues) == 0 {
+					continue
+				}
+
+				yamlValues := strings.Split(strings.TrimPrefix(attribute.Type, "ENUM:"), ",")
+
+				if onlyInSpec, onlyInYaml := diffStringSets(specValues, yamlValues); len(onlyInSpec)+len(onlyInYaml) > 0 {
+					findings = append(findings, newFinding(AuditEnumMismatch, key,
+						fmt.Sprintf("enum values for attribute %s differ, only in spec: [%s], only in resource: [%s]", key, strings.Join(onlyInSpec, ","), strings.Join(onlyInYaml, ",")),
+						&AuditSuggestion{Kind: SuggestAttribute, Key: key, Value: "ENUM:" + strings.Join(specValues, ",")}))
+				}
+				break
+			}
+		}
+	}
+
+	// Only report missing properties once per resource, the update schema is frequently the same as the create one.
+	if info.operationType == "update-entity" && resource.CreateEntityInfo != nil && resource.CreateEntityInfo.OpenApiOperationId != "" {
+		return findings
+	}
+
+	paths := make([]string, 0, len(flattened))
+	for path := range flattened {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		key, ok := GetAttributeForRequestPath(resource, path)
+
+		if !ok || key == "type" || key == "id" {
+			continue
+		}
+
+		if _, ok := resource.Attributes[key]; ok {
+			continue
+		}
+
+		if isCoveredByAttribute(resource, key, regexAttributes) {
+			continue
+		}
+
+		var leaf *AuditSuggestion
+		for _, s := range flattened[path] {
+			if IsReadOnly(s) {
+				leaf = nil
+				break
+			}
+
+			if IsLeafSchema(s) && leaf == nil {
+				leaf = &AuditSuggestion{Kind: SuggestAttribute, Key: key, Value: GetEpccCliTypeForSchema(s)}
+			}
+		}
+
+		if leaf == nil {
+			continue
+		}
+
+		findings = append(findings, newFinding(AuditPropertyNotInResource, key, fmt.Sprintf("request property %s (%s) is not in the resource", key, path), leaf))
+	}
+
+	return findings
+}
+
+func auditUnmappedOperations(allOperationIds map[string]OperationIDInfo, referencedOperationIds map[string]bool) []AuditFinding {
+	findings := make([]AuditFinding, 0)
+
+	opIds := make([]string, 0, len(allOperationIds))
+	for opId := range allOperationIds {
+		opIds = append(opIds, opId)
+	}
+	sort.Strings(opIds)
+
+	for _, opId := range opIds {
+		if referencedOperationIds[opId] {
+			continue
+		}
+
+		opInfo := allOperationIds[opId]
+
+		finding := AuditFinding{
+			Kind:        AuditUnmappedOperation,
+			Resource:    "",
+			OperationId: opId,
+			Message:     fmt.Sprintf("%s %s in %s is not referenced by any resource", opInfo.Method, opInfo.Path, opInfo.SpecName),
+		}
+
+		// See if there is a resource that could reference it
+		for _, resourceName := range sortedResourceNames(resources.GetPluralResources()) {
+			resource := resources.GetPluralResources()[resourceName]
+			for _, info := range getResourceOperations(resource) {
+				if info.crud.OpenApiOperationId == "" && getMethodForOperationType(info.operationType) == opInfo.Method && normalizeUrl(info.crud.Url) == normalizeUrl(opInfo.Path) {
+					finding.Resource = resourceName
+					finding.SourceFile = resource.SourceFile
+					finding.OperationType = info.operationType
+					finding.Suggestion = &AuditSuggestion{Kind: SuggestOperationId, Key: info.operationType, Value: opId}
+				}
+			}
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// GetRequestPathForAttribute returns the path in the request body that an attribute will be sent as (e.g., data.attributes.name)
+func GetRequestPathForAttribute(resource resources.Resource, key string) string {
+	path := key
+
+	switch {
+	case key == "type" || key == "id":
+	case strings.HasPrefix(key, "attributes.") || strings.HasPrefix(key, "relationships."):
+	case resource.JsonApiFormat == "compliant":
+		path = "attributes." + key
+	}
+
+	if !resource.NoWrapping {
+		path = "data." + path
+	}
+
+	return path
+}
+
+// GetAttributeForRequestPath returns the attribute key that would be sent to a path in the request body, this is the inverse of GetRequestPathForAttribute
+func GetAttributeForRequestPath(resource resources.Resource, path string) (string, bool) {
+	if !resource.NoWrapping {
+		if !strings.HasPrefix(path, "data.") {
+			return "", false
+		}
+		path = strings.TrimPrefix(path, "data.")
+	}
+
+	if resource.JsonApiFormat == "compliant" && strings.HasPrefix(path, "attributes.") {
+		path = strings.TrimPrefix(path, "attributes.")
+	}
+
+	return path, true
+}
+
+// isCoveredByAttribute returns true if a key is matched by a regex attribute, or a parent of it is an attribute
+func isCoveredByAttribute(resource resources.Resource, key string, regexAttributes []*regexp.Regexp) bool {
+	for _, r := range regexAttributes {
+		if r.MatchString(key) {
+			return true
+		}
+	}
+
+	for parent := getParentPath(key); parent != ""; parent = getParentPath(parent) {
+		if _, ok := resource.Attributes[parent]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getMethodForOperationType(operationType string) string {
+	switch operationType {
+	case "get-collection", "get-entity":
+		return "GET"
+	case "create-entity":
+		return "POST"
+	case "update-entity":
+		return "PUT"
+	case "delete-entity":
+		return "DELETE"
+	}
+
+	return ""
+}
+
+func normalizeUrl(url string) string {
+	return strings.TrimSuffix(urlVariableRegex.ReplaceAllString(url, "{}"), "/")
+}
+
+func diffStringSets(a []string, b []string) ([]string, []string) {
+	inA := map[string]bool{}
+	inB := map[string]bool{}
+
+	for _, v := range a {
+		inA[v] = true
+	}
+
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	onlyA := make([]string, 0)
+	for _, v := range a {
+		if !inB[v] {
+			onlyA = append(onlyA, v)
+		}
+	}
+
+	onlyB := make([]string, 0)
+	for _, v := range b {
+		if !inA[v] {
+			onlyB = append(onlyB, v)
+		}
+	}
+
+	return onlyA, onlyB
+}
+
+func sortedResourceNames(r map[string]resources.Resource) []string {
+	names := make([]string, 0, len(r))
+	for k := range r {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedAttributeNames(a map[string]*resources.CrudEntityAttribute) []string {
+	names := make([]string, 0, len(a))
+	for k := range a {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRequestPathForAttributeRoundTrips(t *testing.T) {
+	// Fixture Setup
+	compliant := resources.Resource{JsonApiFormat: "compliant"}
+	legacy := resources.Resource{JsonApiFormat: "legacy"}
+	unwrapped := resources.Resource{JsonApiFormat: "legacy", NoWrapping: true}
+
+	// Execute SUT & Verification
+	require.Equal(t, "data.attributes.name", GetRequestPathForAttribute(compliant, "name"))
+	require.Equal(t, "data.relationships.parent.data.id", GetRequestPathForAttribute(compliant, "relationships.parent.data.id"))
+	require.Equal(t, "data.type", GetRequestPathForAttribute(compliant, "type"))
+	require.Equal(t, "data.name", GetRequestPathForAttribute(legacy, "name"))
+	require.Equal(t, "name", GetRequestPathForAttribute(unwrapped, "name"))
+
+	key, ok := GetAttributeForRequestPath(compliant, "data.attributes.tags[n]")
+	require.True(t, ok)
+	require.Equal(t, "tags[n]", key)
+
+	_, ok = GetAttributeForRequestPath(legacy, "included[n].id")
+	require.False(t, ok)
+}
+
+func TestAuditResourcesReportsDrift(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("accounts")
+	resource.Attributes = map[string]*resources.CrudEntityAttribute{
+		"name":           {Type: "STRING"},
+		"not_a_property": {Type: "STRING"},
+	}
+	collection := *resource.GetCollectionInfo
+	collection.QueryParameters = nil
+	resource.GetCollectionInfo = &collection
+
+	// Execute SUT
+	findings, err := AuditResources(map[string]resources.Resource{"accounts": resource}, false)
+
+	// Verification
+	require.NoError(t, err)
+
+	kinds := map[string][]string{}
+	for _, f := range findings {
+		kinds[f.Kind] = append(kinds[f.Kind], f.Key)
+	}
+
+	require.Contains(t, kinds[AuditAttributeNotInSpec], "not_a_property")
+	require.Contains(t, kinds[AuditPropertyNotInResource], "legal_name")
+	require.Contains(t, kinds[AuditMissingQueryParameter], "filter")
+	require.NotContains(t, kinds, AuditUnmappedOperation)
+}
@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Property names that can be used as is in a jq path (e.g., .data.name), others need to be quoted (e.g., .data["authentication-realm"])
+var jqIdentifier = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// GetJqPathsForResponse returns a jq path (e.g., .data.attributes.name or .data[].id) for every value in the response of an operation.
+func GetJqPathsForResponse(operationID string) ([]string, error) {
+	opInfo, err := FindOperationByID(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	validationMutex.Lock()
+	defer validationMutex.Unlock()
+
+	schema := GetResponseSchema(opInfo.Operation)
+	if schema == nil {
+		return nil, fmt.Errorf("operation %s doesn't have a JSON response", operationID)
+	}
+
+	paths := make([]string, 0)
+	for path := range FlattenSchema(schema) {
+		paths = append(paths, convertSchemaPathToJq(path))
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// convertSchemaPathToJq converts a path from FlattenSchema (e.g., data[n].relationships.authentication-realm) to jq (e.g., .data[].relationships["authentication-realm"])
+func convertSchemaPathToJq(path string) string {
+	sb := strings.Builder{}
+
+	for _, segment := range strings.Split(path, ".") {
+		arrays := 0
+		for strings.HasSuffix(segment, "[n]") {
+			segment = strings.TrimSuffix(segment, "[n]")
+			arrays++
+		}
+
+		if jqIdentifier.MatchString(segment) {
+			sb.WriteString("." + segment)
+		} else if sb.Len() == 0 {
+			sb.WriteString(".[" + strconv.Quote(segment) + "]")
+		} else {
+			sb.WriteString("[" + strconv.Quote(segment) + "]")
+		}
+
+		sb.WriteString(strings.Repeat("[]", arrays))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJqPathsForResponseFollowsArrays(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	paths, err := GetJqPathsForResponse("get-v2-account-members")
+
+	// Verification
+	require.NoError(t, err)
+	require.Contains(t, paths, ".data[]")
+	require.Contains(t, paths, ".data[].meta.timestamps.created_at")
+	require.Contains(t, paths, ".meta.results.total")
+}
+
+func TestGetJqPathsForResponseWithUnknownOperationReturnsError(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, err := GetJqPathsForResponse("not-an-operation")
+
+	// Verification
+	require.Error(t, err)
+}
+
+func TestConvertSchemaPathToJqQuotesPropertyNames(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	nested := convertSchemaPathToJq("data[n].relationships.authentication-realm.data[n][n]")
+	root := convertSchemaPathToJq("main-image")
+
+	// Verification
+	require.Equal(t, `.data[].relationships["authentication-realm"].data[][]`, nested)
+	require.Equal(t, `.["main-image"]`, root)
+}
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/pb33f/libopenapi"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -73,6 +74,19 @@ func GetAllSpecModels() (map[string]*SpecModel, error) {
 	return specModels, nil
 }
 
+var cachedSpecModels map[string]*SpecModel
+var cachedSpecModelsErr error
+var cachedSpecModelsOnce sync.Once
+
+// getCachedSpecModels returns all available OpenAPI specs, the specs are embedded so these are only built once.
+func getCachedSpecModels() (map[string]*SpecModel, error) {
+	cachedSpecModelsOnce.Do(func() {
+		cachedSpecModels, cachedSpecModelsErr = GetAllSpecModels()
+	})
+
+	return cachedSpecModels, cachedSpecModelsErr
+}
+
 // GetSpecModel returns a specific OpenAPI spec as a libopenapi model
 func GetSpecModel(name string) (*SpecModel, error) {
 	// If the name doesn't end with .yaml, add the extension
@@ -130,7 +144,7 @@ type OperationInfo struct {
 //	    opInfo.SpecName, opInfo.Path, opInfo.Method)
 func FindOperationByID(operationID string) (*OperationInfo, error) {
 	// Get all spec models
-	specModels, err := GetAllSpecModels()
+	specModels, err := getCachedSpecModels()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OpenAPI specs: %w", err)
 	}
@@ -307,7 +321,7 @@ type TagInfo struct {
 //	}
 func GetAllOperationIDs() (map[string]OperationIDInfo, error) {
 	// Get all spec models
-	specModels, err := GetAllSpecModels()
+	specModels, err := getCachedSpecModels()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OpenAPI specs: %w", err)
 	}
@@ -366,7 +380,7 @@ func GetAllOperationIDs() (map[string]OperationIDInfo, error) {
 
 func FindTagByName(tag string) (*TagInfo, string, error) {
 	// Get all spec models
-	specModels, err := GetAllSpecModels()
+	specModels, err := getCachedSpecModels()
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get OpenAPI specs: %w", err)
 	}
@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// The maximum number of segments we will follow when flattening a schema, this protects us from recursive schemas.
+const maxSchemaDepth = 12
+
+// GetRequestSchema returns the JSON schema of the request body of an operation, or nil if there isn't one.
+func GetRequestSchema(operation *v3.Operation) *base.Schema {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Content == nil {
+		return nil
+	}
+
+	return getJsonSchemaFromContent(operation.RequestBody.Content.FromOldest())
+}
+
+// GetResponseSchema returns the JSON schema of the first successful response of an operation, or nil if there isn't one.
+func GetResponseSchema(operation *v3.Operation) *base.Schema {
+	if operation == nil || operation.Responses == nil || operation.Responses.Codes == nil {
+		return nil
+	}
+
+	for code, response := range operation.Responses.Codes.FromOldest() {
+		if !strings.HasPrefix(code, "2") || response == nil || response.Content == nil {
+			continue
+		}
+
+		if schema := getJsonSchemaFromContent(response.Content.FromOldest()); schema != nil {
+			return schema
+		}
+	}
+
+	return nil
+}
+
+func getJsonSchemaFromContent(content iter.Seq2[string, *v3.MediaType]) *base.Schema {
+	var result *base.Schema
+
+	for contentType, mediaType := range content {
+		if mediaType == nil || mediaType.Schema == nil {
+			continue
+		}
+
+		if result == nil || strings.Contains(contentType, "json") {
+			result = mediaType.Schema.Schema()
+		}
+
+		if strings.Contains(contentType, "json") {
+			break
+		}
+	}
+
+	return result
+}
+
+// FlattenSchema returns every path in a schema (e.g., data.attributes.name, data.tags[n]) mapped to the schemas at that path.
+// Paths can have more than one schema when allOf, oneOf or anyOf are used.
+func FlattenSchema(schema *base.Schema) map[string][]*base.Schema {
+	result := map[string][]*base.Schema{}
+	flattenSchema(schema, "", 0, result)
+	return result
+}
+
+func flattenSchema(schema *base.Schema, path string, depth int, result map[string][]*base.Schema) {
+	if schema == nil || depth > maxSchemaDepth {
+		return
+	}
+
+	if path != "" {
+		result[path] = append(result[path], schema)
+	}
+
+	for _, composed := range [][]*base.SchemaProxy{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, proxy := range composed {
+			if proxy != nil {
+				flattenSchema(proxy.Schema(), path, depth+1, result)
+			}
+		}
+	}
+
+	if schema.Properties != nil {
+		for name, proxy := range schema.Properties.FromOldest() {
+			if proxy == nil {
+				continue
+			}
+
+			childPath := name
+			if path != "" {
+				childPath = path + "." + name
+			}
+
+			flattenSchema(proxy.Schema(), childPath, depth+1, result)
+		}
+	}
+
+	if schema.Items != nil && schema.Items.IsA() && schema.Items.A != nil {
+		flattenSchema(schema.Items.A.Schema(), path+"[n]", depth+1, result)
+	}
+}
+
+// IsPathAllowedBySchema returns true if a path is either in the flattened schema, or a parent of it allows arbitrary properties.
+func IsPathAllowedBySchema(flattened map[string][]*base.Schema, path string) bool {
+	if _, ok := flattened[path]; ok {
+		return true
+	}
+
+	for parent := getParentPath(path); parent != ""; parent = getParentPath(parent) {
+		schemas, ok := flattened[parent]
+
+		if !ok {
+			continue
+		}
+
+		for _, s := range schemas {
+			if IsFreeFormObject(s) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// IsFreeFormObject returns true if a schema allows properties that aren't declared.
+func IsFreeFormObject(schema *base.Schema) bool {
+	if schema == nil {
+		return false
+	}
+
+	if schema.AdditionalProperties != nil {
+		if schema.AdditionalProperties.IsB() {
+			return schema.AdditionalProperties.B
+		}
+		return true
+	}
+
+	isObject := len(schema.Type) == 0
+	for _, t := range schema.Type {
+		if t == "object" {
+			isObject = true
+		}
+	}
+
+	return isObject && (schema.Properties == nil || schema.Properties.Len() == 0) &&
+		len(schema.AllOf) == 0 && len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 && schema.Items == nil
+}
+
+// IsLeafSchema returns true if a schema has no properties or items of its own (e.g., a string or number)
+func IsLeafSchema(schema *base.Schema) bool {
+	if schema == nil {
+		return false
+	}
+
+	if schema.Properties != nil && schema.Properties.Len() > 0 {
+		return false
+	}
+
+	if schema.Items != nil || len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return false
+	}
+
+	for _, t := range schema.Type {
+		if t == "object" || t == "array" {
+			return false
+		}
+	}
+
+	return len(schema.Type) > 0 || len(schema.Enum) > 0
+}
+
+// IsReadOnly returns true if the schema is marked as read only.
+func IsReadOnly(schema *base.Schema) bool {
+	return schema != nil && schema.ReadOnly != nil && *schema.ReadOnly
+}
+
+// GetEnumValues returns the string values of an enum in a schema, or nil if it isn't an enum.
+func GetEnumValues(schema *base.Schema) []string {
+	if schema == nil || len(schema.Enum) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(schema.Enum))
+
+	for _, e := range schema.Enum {
+		if e != nil && e.Value != "" {
+			values = append(values, e.Value)
+		}
+	}
+
+	return values
+}
+
+// GetEpccCliTypeForSchema returns the attribute type the EPCC CLI would use for a schema (e.g., STRING, INT, ENUM:a,b).
+func GetEpccCliTypeForSchema(schema *base.Schema) string {
+	if schema == nil {
+		return "STRING"
+	}
+
+	if values := GetEnumValues(schema); len(values) > 0 {
+		return fmt.Sprintf("ENUM:%s", strings.Join(values, ","))
+	}
+
+	for _, t := range schema.Type {
+		switch strings.ToLower(t) {
+		case "integer":
+			return "INT"
+		case "number":
+			return "FLOAT"
+		case "boolean":
+			return "BOOL"
+		case "string":
+			if schema.Format == "uri" || schema.Format == "url" {
+				return "URL"
+			}
+			return "STRING"
+		}
+	}
+
+	return "STRING"
+}
+
+func getParentPath(path string) string {
+	if strings.HasSuffix(path, "[n]") {
+		return strings.TrimSuffix(path, "[n]")
+	}
+
+	idx := strings.LastIndex(path, ".")
+
+	if idx < 0 {
+		return ""
+	}
+
+	return path[0:idx]
+}
@@ -0,0 +1,271 @@
+package openapi
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// SchemaViolation is a value in a document that doesn't conform to a schema.
+type SchemaViolation struct {
+	// The path to the value (e.g., data.attributes.price[0].amount)
+	Path string
+
+	// True if the value is missing but required
+	Missing bool
+
+	Message string
+}
+
+// Schemas are lazily built by libopenapi, so we don't want to walk them concurrently (e.g., in runbooks)
+var validationMutex sync.Mutex
+
+var uuidRegex = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// ValidateAgainstSchema checks a document (decoded with json.Decoder.UseNumber()) against a schema, checking types, enums, required
+// properties, formats and additionalProperties. Properties that a schema doesn't declare are only reported when additionalProperties is false.
+func ValidateAgainstSchema(schema *base.Schema, value interface{}) []SchemaViolation {
+	return validate(schema, value, true)
+}
+
+// ValidatePartialAgainstSchema checks a document like ValidateAgainstSchema, but doesn't require properties, as an update only needs to send the properties that change.
+func ValidatePartialAgainstSchema(schema *base.Schema, value interface{}) []SchemaViolation {
+	return validate(schema, value, false)
+}
+
+func validate(schema *base.Schema, value interface{}, checkRequired bool) []SchemaViolation {
+	validationMutex.Lock()
+	defer validationMutex.Unlock()
+
+	violations := validateAgainstSchema(schema, value, "", 0, checkRequired)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+
+	return violations
+}
+
+func validateAgainstSchema(schema *base.Schema, value interface{}, path string, depth int, checkRequired bool) []SchemaViolation {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	if value == nil {
+		// Always allow clearing a value, few specs mark properties as nullable
+		return nil
+	}
+
+	violations := make([]SchemaViolation, 0)
+
+	for _, proxy := range schema.AllOf {
+		if proxy != nil {
+			violations = append(violations, validateAgainstSchema(proxy.Schema(), value, path, depth+1, checkRequired)...)
+		}
+	}
+
+	for _, alternatives := range [][]*base.SchemaProxy{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		// We treat oneOf like anyOf, and if nothing matches report the closest alternative.
+		var closest []SchemaViolation
+		for _, proxy := range alternatives {
+			if proxy == nil {
+				continue
+			}
+
+			v := validateAgainstSchema(proxy.Schema(), value, path, depth+1, checkRequired)
+
+			if closest == nil || len(v) < len(closest) {
+				closest = v
+			}
+
+			if len(v) == 0 {
+				break
+			}
+		}
+
+		violations = append(violations, closest...)
+	}
+
+	if msg := checkType(schema, value); msg != "" {
+		return append(violations, SchemaViolation{Path: path, Message: msg})
+	}
+
+	if enumValues := GetEnumValues(schema); len(enumValues) > 0 {
+		if s := fmt.Sprintf("%v", value); !containsString(enumValues, s) {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must be one of [%s]", strings.Join(enumValues, ","))})
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if msg := checkFormat(schema.Format, v); msg != "" {
+			violations = append(violations, SchemaViolation{Path: path, Message: msg})
+		}
+	case map[string]interface{}:
+		violations = append(violations, validateObject(schema, v, path, depth, checkRequired)...)
+	case []interface{}:
+		if schema.Items != nil && schema.Items.IsA() && schema.Items.A != nil {
+			itemSchema := schema.Items.A.Schema()
+			for i, item := range v {
+				violations = append(violations, validateAgainstSchema(itemSchema, item, fmt.Sprintf("%s[%d]", path, i), depth+1, checkRequired)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func validateObject(schema *base.Schema, obj map[string]interface{}, path string, depth int, checkRequired bool) []SchemaViolation {
+	violations := make([]SchemaViolation, 0)
+
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok && checkRequired {
+			violations = append(violations, SchemaViolation{Path: joinPath(path, name), Missing: true, Message: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if schema.Properties != nil {
+			if proxy, ok := schema.Properties.Get(k); ok {
+				if proxy != nil {
+					violations = append(violations, validateAgainstSchema(proxy.Schema(), obj[k], joinPath(path, k), depth+1, checkRequired)...)
+				}
+				continue
+			}
+		}
+
+		if schema.AdditionalProperties == nil {
+			continue
+		}
+
+		if schema.AdditionalProperties.IsB() {
+			if !schema.AdditionalProperties.B {
+				violations = append(violations, SchemaViolation{Path: joinPath(path, k), Message: "is not a known property"})
+			}
+		} else if schema.AdditionalProperties.A != nil {
+			violations = append(violations, validateAgainstSchema(schema.AdditionalProperties.A.Schema(), obj[k], joinPath(path, k), depth+1, checkRequired)...)
+		}
+	}
+
+	return violations
+}
+
+func checkType(schema *base.Schema, value interface{}) string {
+	if len(schema.Type) == 0 {
+		return ""
+	}
+
+	for _, t := range schema.Type {
+		switch t {
+		case "string":
+			if _, ok := value.(string); ok {
+				return ""
+			}
+		case "integer":
+			if n, ok := value.(gojson.Number); ok {
+				if _, err := n.Int64(); err == nil {
+					return ""
+				}
+			}
+		case "number":
+			if _, ok := value.(gojson.Number); ok {
+				return ""
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return ""
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return ""
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return ""
+			}
+		case "null":
+		default:
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("expected %s but got %s", strings.Join(schema.Type, " or "), describeJsonType(value))
+}
+
+func checkFormat(format string, value string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "is not a valid date-time (e.g., 2006-01-02T15:04:05Z)"
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "is not a valid date (e.g., 2006-01-02)"
+		}
+	case "uri", "url":
+		if u, err := url.ParseRequestURI(value); err != nil || u.Scheme == "" {
+			return "is not a valid url"
+		}
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil {
+			return "is not a valid email address"
+		}
+	case "uuid":
+		if !uuidRegex.MatchString(value) {
+			return "is not a valid uuid"
+		}
+	}
+
+	return ""
+}
+
+func describeJsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case gojson.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	default:
+		return "null"
+	}
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeForValidation(t *testing.T, s string) interface{} {
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+
+	var v interface{}
+	require.NoError(t, decoder.Decode(&v))
+	return v
+}
+
+func TestValidateAgainstSchemaAcceptsValidRequest(t *testing.T) {
+	// Fixture Setup
+	opInfo, err := FindOperationByID("CreateProrationPolicy")
+	require.NoError(t, err)
+	schema := GetRequestSchema(opInfo.Operation)
+	require.NotNil(t, schema)
+
+	body := decodeForValidation(t, `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy","rounding":"up","skip_zero_value_items":true}}}`)
+
+	// Execute SUT
+	violations := ValidateAgainstSchema(schema, body)
+
+	// Verification
+	require.Empty(t, violations)
+}
+
+func TestValidateAgainstSchemaReportsWrongTypesAndEnumValues(t *testing.T) {
+	// Fixture Setup
+	opInfo, err := FindOperationByID("CreateProrationPolicy")
+	require.NoError(t, err)
+	schema := GetRequestSchema(opInfo.Operation)
+
+	body := decodeForValidation(t, `{"data":{"type":"subscription_proration_policy","attributes":{"name":5,"rounding":"sideways"}}}`)
+
+	// Execute SUT
+	violations := ValidateAgainstSchema(schema, body)
+
+	// Verification
+	paths := map[string]SchemaViolation{}
+	for _, v := range violations {
+		paths[v.Path] = v
+	}
+
+	require.Contains(t, paths, "data.attributes.name")
+	require.Equal(t, "expected string but got a number", paths["data.attributes.name"].Message)
+	require.Contains(t, paths, "data.attributes.rounding")
+	require.Contains(t, paths["data.attributes.rounding"].Message, "must be one of")
+}
+
+func TestValidateAgainstSchemaReportsMissingRequiredProperties(t *testing.T) {
+	// Fixture Setup
+	opInfo, err := FindOperationByID("CreateProrationPolicy")
+	require.NoError(t, err)
+	schema := GetRequestSchema(opInfo.Operation)
+
+	body := decodeForValidation(t, `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy"}}}`)
+
+	// Execute SUT
+	violations := ValidateAgainstSchema(schema, body)
+
+	// Verification
+	require.Contains(t, violations, SchemaViolation{Path: "data.attributes.rounding", Missing: true, Message: "is required"})
+}
+
+func TestValidateAgainstSchemaChecksFormats(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT & Verification
+	require.Equal(t, "", checkFormat("date-time", "2024-01-02T03:04:05Z"))
+	require.NotEqual(t, "", checkFormat("date-time", "yesterday"))
+	require.Equal(t, "", checkFormat("uri", "https://example.com/"))
+	require.NotEqual(t, "", checkFormat("uri", "example"))
+	require.Equal(t, "", checkFormat("email", "test@example.com"))
+	require.NotEqual(t, "", checkFormat("email", "test"))
+	require.Equal(t, "", checkFormat("uuid", "1e0d4a57-0c4b-4bc1-b5f9-5c4f2a5f7c4e"))
+	require.NotEqual(t, "", checkFormat("uuid", "name=foo"))
+}
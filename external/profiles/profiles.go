@@ -91,3 +91,17 @@ func GetProfile(name string) *config.Env {
 	return result
 
 }
+
+// ProfileExists returns true if the profile is in the config file, or has been used before
+func ProfileExists(name string) bool {
+	if _, err := os.Stat(filepath.Clean(filepath.FromSlash(GetProfileDirectory() + "/" + name))); err == nil {
+		return true
+	}
+
+	cfg, err := ini.Load(GetConfigFilePath())
+	if err != nil {
+		return false
+	}
+
+	return cfg.HasSection(name)
+}
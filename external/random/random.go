@@ -0,0 +1,173 @@
+package random
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Stream is a source of random values (for template functions and fake data), when a seed is set the values are the same every run.
+type Stream struct {
+	source *lockedSource
+	rand   *rand.Rand
+	faker  *gofakeit.Faker
+	// The seed the stream was restarted from (e.g., with {{ seed }}), streams derived from this one use it
+	reseed atomic.Pointer[seedState]
+}
+
+type seedState struct {
+	seed   int64
+	seeded bool
+}
+
+var currentSeed atomic.Pointer[seedState]
+
+var defaultStream atomic.Pointer[Stream]
+
+type streamKey struct{}
+
+func init() {
+	currentSeed.Store(&seedState{})
+	defaultStream.Store(NewStream())
+}
+
+// SetSeed makes all random values deterministic, every stream is derived from the seed and the labels of the stream.
+func SetSeed(seed int64) {
+	currentSeed.Store(&seedState{seed: seed, seeded: true})
+	defaultStream.Store(NewStream("default"))
+}
+
+// GetSeed returns the seed, and whether one was set
+func GetSeed() (int64, bool) {
+	s := currentSeed.Load()
+	return s.seed, s.seeded
+}
+
+// NewStream returns a stream for the labels (e.g., the runbook, step and command index), with a seed the same labels always return the same values,
+// so the order things run in (e.g., concurrent commands in a runbook) doesn't change them. Without a seed the values are different every time.
+func NewStream(labels ...any) *Stream {
+	seed, seeded := GetSeed()
+	return newStream(seed, seeded, labels)
+}
+
+func newStream(seed int64, seeded bool, labels []any) *Stream {
+	if seeded {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d", seed)
+
+		for _, label := range labels {
+			fmt.Fprintf(h, "\x00%v", label)
+		}
+
+		seed = int64(h.Sum64())
+	} else {
+		binary.Read(crand.Reader, binary.BigEndian, &seed)
+	}
+
+	source := &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+
+	return &Stream{
+		source: source,
+		rand:   rand.New(source),
+		faker:  gofakeit.NewCustom(source),
+	}
+}
+
+// Default returns the stream used when there isn't one in the context (e.g., for a single command)
+func Default() *Stream {
+	return defaultStream.Load()
+}
+
+// WithStream returns a context that uses the stream for random values
+func WithStream(ctx context.Context, s *Stream) context.Context {
+	return context.WithValue(ctx, streamKey{}, s)
+}
+
+// FromContext returns the stream in the context, or the default one
+func FromContext(ctx context.Context) *Stream {
+	if ctx != nil {
+		if s, ok := ctx.Value(streamKey{}).(*Stream); ok {
+			return s
+		}
+	}
+
+	return Default()
+}
+
+// Reseed restarts the stream from a seed
+func (s *Stream) Reseed(seed int64) {
+	s.reseed.Store(&seedState{seed: seed, seeded: true})
+	s.source.Seed(seed)
+}
+
+// Derive returns a stream for the labels (e.g., a command in a step), if this stream was reseeded the values depend on that seed
+// so that they are the same every run, otherwise it is the same as NewStream.
+func (s *Stream) Derive(labels ...any) *Stream {
+	if r := s.reseed.Load(); r != nil {
+		return newStream(r.seed, true, labels)
+	}
+
+	return NewStream(labels...)
+}
+
+// Intn returns a value between 0 (inclusive) and n (exclusive)
+func (s *Stream) Intn(n int) int {
+	return s.rand.Intn(n)
+}
+
+// Perm returns a random permutation of the values between 0 (inclusive) and n (exclusive)
+func (s *Stream) Perm(n int) []int {
+	return s.rand.Perm(n)
+}
+
+// NormFloat64 returns a normally distributed value with a mean of 0 and a standard deviation of 1
+func (s *Stream) NormFloat64() float64 {
+	return s.rand.NormFloat64()
+}
+
+// Read fills p with random bytes (e.g., for UUIDs)
+func (s *Stream) Read(p []byte) (int, error) {
+	for i := 0; i < len(p); i += 8 {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], s.source.Uint64())
+		copy(p[i:], buf[:])
+	}
+
+	return len(p), nil
+}
+
+// Faker returns the fake data generator that uses this stream
+func (s *Stream) Faker() *gofakeit.Faker {
+	return s.faker
+}
+
+// lockedSource lets a stream be used concurrently (e.g., the default one)
+type lockedSource struct {
+	lock sync.Mutex
+	src  rand.Source64
+}
+
+func (r *lockedSource) Int63() int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.src.Int63()
+}
+
+func (r *lockedSource) Uint64() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.src.Uint64()
+}
+
+func (r *lockedSource) Seed(seed int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.src.Seed(seed)
+}
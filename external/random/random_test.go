@@ -0,0 +1,97 @@
+package random
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStreamReturnsTheSameValuesForTheSameLabelsWithASeed(t *testing.T) {
+	// Fixture Setup
+	SetSeed(42)
+
+	first := NewStream("runbook", "action", 1, 2)
+	second := NewStream("runbook", "action", 1, 2)
+
+	// Execute SUT
+	firstValues := []int{first.Intn(1000000), first.Intn(1000000), first.Intn(1000000)}
+	secondValues := []int{second.Intn(1000000), second.Intn(1000000), second.Intn(1000000)}
+
+	// Verification
+	require.Equal(t, firstValues, secondValues)
+	require.Equal(t, first.Faker().Email(), second.Faker().Email())
+}
+
+func TestNewStreamReturnsDifferentValuesForDifferentLabelsWithASeed(t *testing.T) {
+	// Fixture Setup
+	SetSeed(42)
+
+	first := NewStream("runbook", "action", 1, 2)
+	second := NewStream("runbook", "action", 2, 1)
+
+	// Execute SUT
+	firstValues := []int{first.Intn(1000000), first.Intn(1000000), first.Intn(1000000)}
+	secondValues := []int{second.Intn(1000000), second.Intn(1000000), second.Intn(1000000)}
+
+	// Verification
+	require.NotEqual(t, firstValues, secondValues)
+}
+
+func TestNewStreamReturnsDifferentValuesForDifferentSeeds(t *testing.T) {
+	// Fixture Setup
+	SetSeed(1)
+	first := NewStream("runbook")
+
+	SetSeed(2)
+	second := NewStream("runbook")
+
+	// Execute SUT
+	firstValues := []int{first.Intn(1000000), first.Intn(1000000), first.Intn(1000000)}
+	secondValues := []int{second.Intn(1000000), second.Intn(1000000), second.Intn(1000000)}
+
+	// Verification
+	require.NotEqual(t, firstValues, secondValues)
+}
+
+func TestReseedRestartsTheStream(t *testing.T) {
+	// Fixture Setup
+	s := NewStream()
+	s.Reseed(7)
+	expected := []int{s.Intn(1000000), s.Intn(1000000)}
+
+	// Execute SUT
+	s.Reseed(7)
+
+	// Verification
+	require.Equal(t, expected, []int{s.Intn(1000000), s.Intn(1000000)})
+}
+
+func TestFromContextReturnsTheStreamInTheContextOrTheDefault(t *testing.T) {
+	// Fixture Setup
+	s := NewStream("test")
+
+	// Execute SUT
+	fromContextWithStream := FromContext(WithStream(context.Background(), s))
+	fromContextWithoutStream := FromContext(context.Background())
+
+	// Verification
+	require.Same(t, s, fromContextWithStream)
+	require.Same(t, Default(), fromContextWithoutStream)
+}
+
+func TestDeriveUsesTheSeedOfAReseededStream(t *testing.T) {
+	// Fixture Setup
+	first := NewStream("step")
+	second := NewStream("step")
+	first.Reseed(7)
+	second.Reseed(7)
+
+	// Execute SUT
+	firstDerived := first.Derive("step", 0)
+	secondDerived := second.Derive("step", 0)
+
+	// Verification
+	require.Equal(t, firstDerived.Intn(1000000), secondDerived.Intn(1000000))
+	require.Equal(t, firstDerived.Faker().Email(), secondDerived.Faker().Email())
+}
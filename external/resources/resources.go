@@ -60,6 +60,9 @@ type Resource struct {
 	// If true, don't wrap json in a data tag
 	NoWrapping bool `yaml:"no-wrapping,omitempty"`
 
+	// If true, the resource can have attributes that aren't listed (e.g., fields added with flows)
+	AdditionalAttributes bool `yaml:"additional-attributes,omitempty"`
+
 	// The singular name version of the resource.
 	SingularName string `yaml:"singular-name"`
 
@@ -70,7 +73,7 @@ type Resource struct {
 	// a store reset would clear a resource another way (e.g., the resource represents a projection).
 	SuppressResetWarning bool `yaml:"suppress-reset-warning,omitempty"`
 
-	Legacy bool `yaml:"legacy"`
+	Legacy bool `yaml:"legacy,omitempty"`
 
 	// If another resource is used to create this resource, list it here
 	CreatedBy []VerbResource `yaml:"created_by,omitempty"`
@@ -83,8 +86,11 @@ type Resource struct {
 	// Useful for fields that are read-only or have different semantics on create (e.g., password on customers)
 	ExcludedJsonPointersFromImport []string `yaml:"excluded-json-pointers-from-import,omitempty"`
 
+	// The columns to show by default with --output table, these are paths in the JSON object (e.g., attributes.name)
+	DefaultColumns []string `yaml:"default-columns,omitempty"`
+
 	// Source Filename
-	SourceFile string
+	SourceFile string `yaml:"-"`
 }
 
 type QueryParameter struct {
@@ -120,10 +126,10 @@ type CrudEntityInfo struct {
 	// Override the attribute we use in the URL for a specific key
 	ParentResourceValueOverrides map[string]string `yaml:"parent_resource_value_overrides,omitempty"`
 
-	OpenApiOperationId string `yaml:"openapi-operation-id"`
+	OpenApiOperationId string `yaml:"openapi-operation-id,omitempty"`
 
 	// Only valid on create, if set we report that the type created by this is different.
-	Creates string `yaml:"creates"`
+	Creates string `yaml:"creates,omitempty"`
 
 	DefaultQueryParams map[string]string `yaml:"default-query-params,omitempty"`
 }
@@ -276,6 +282,23 @@ func PublicInit() {
 		}
 	}
 
+	if e.EPCC_RESOURCE_DIRECTORY != "" {
+		userResources, err := LoadResourcesFromDirectory(e.EPCC_RESOURCE_DIRECTORY)
+
+		if err != nil {
+			log.Warnf("EPCC_RESOURCE_DIRECTORY set as %s but could not load resources: %v", e.EPCC_RESOURCE_DIRECTORY, err)
+		} else if len(userResources) == 0 {
+			log.Warnf("EPCC_RESOURCE_DIRECTORY set as %s but no resources found, resource files should end in .yaml", e.EPCC_RESOURCE_DIRECTORY)
+		}
+
+		for k, v := range userResources {
+			if existing, ok := resourceData[k]; ok {
+				log.Debugf("Resource %s from %s overrides resource from %s", k, v.SourceFile, existing.SourceFile)
+			}
+			resourceData[k] = v
+		}
+	}
+
 	resources = resourceData
 
 	createFlowEntityRelationships()
@@ -0,0 +1,196 @@
+package resources
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed resources_schema.json
+var resourcesSchema []byte
+
+const resourcesSchemaUrl = "https://github.com/elasticpath/epcc-cli/blob/main/external/resources/resources_schema.json"
+
+// LoadResourcesFromDirectory loads all resource definitions from .yaml or .yml files in a directory, files that
+// fail to validate against the resource schema are reported and skipped.
+func LoadResourcesFromDirectory(dir string) (map[string]Resource, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read resources from directory %s: %w", dir, err)
+	}
+
+	result := map[string]Resource{}
+
+	for _, entry := range entries {
+		lName := strings.ToLower(entry.Name())
+		if entry.IsDir() || !(strings.HasSuffix(lName, ".yaml") || strings.HasSuffix(lName, ".yml")) {
+			log.Tracef("File %s does not end in .yaml, not parsing.", entry.Name())
+			continue
+		}
+
+		filename := filepath.Join(dir, entry.Name())
+
+		contents, err := os.ReadFile(filename)
+
+		if err != nil {
+			log.Errorf("Could not read resources from file %s due to error: %v", filename, err)
+			continue
+		}
+
+		r, err := LoadResourcesFromYaml(filename, contents)
+
+		if err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				log.Errorf("Could not load resources from file %s", line)
+			}
+			continue
+		}
+
+		for k, v := range r {
+			if existing, ok := result[k]; ok {
+				log.Warnf("Resource %s is defined in both %s and %s, using %s", k, existing.SourceFile, v.SourceFile, v.SourceFile)
+			}
+			result[k] = v
+		}
+	}
+
+	return result, nil
+}
+
+// LoadResourcesFromYaml validates the contents of a resource yaml file against the resource schema and returns the resources in it.
+func LoadResourcesFromYaml(filename string, contents []byte) (map[string]Resource, error) {
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(contents, &node); err != nil {
+		return nil, fmt.Errorf("%s: invalid yaml: %w", filename, err)
+	}
+
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: invalid yaml: %w", filename, err)
+	}
+
+	if v == nil {
+		return map[string]Resource{}, nil
+	}
+
+	if err := validateAgainstResourceSchema(filename, &node, v); err != nil {
+		return nil, err
+	}
+
+	r := map[string]Resource{}
+	if err := node.Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s: couldn't unmarshal resources: %w", filename, err)
+	}
+
+	for k, res := range r {
+		res.SourceFile = filename
+		log.Tracef("Loaded %s from %s", k, filename)
+		r[k] = res
+	}
+
+	return r, nil
+}
+
+func validateAgainstResourceSchema(filename string, node *yaml.Node, v interface{}) error {
+	compiler := jsonschema.NewCompiler()
+
+	if err := compiler.AddResource(resourcesSchemaUrl, bytes.NewReader(resourcesSchema)); err != nil {
+		return fmt.Errorf("could not load resource schema: %w", err)
+	}
+
+	schema, err := compiler.Compile(resourcesSchemaUrl)
+
+	if err != nil {
+		return fmt.Errorf("could not compile resource schema: %w", err)
+	}
+
+	err = schema.Validate(v)
+
+	if err == nil {
+		return nil
+	}
+
+	ve, ok := err.(*jsonschema.ValidationError)
+
+	if !ok {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	messages := make([]string, 0)
+	seen := map[string]bool{}
+
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, c := range e.Causes {
+				collect(c)
+			}
+			return
+		}
+
+		msg := fmt.Sprintf("%s:%d: %s: %s", filename, lineForJsonPointer(node, e.InstanceLocation), e.InstanceLocation, e.Message)
+
+		if !seen[msg] {
+			seen[msg] = true
+			messages = append(messages, msg)
+		}
+	}
+
+	collect(ve)
+
+	sort.Strings(messages)
+
+	return fmt.Errorf("%s", strings.Join(messages, "\n"))
+}
+
+// lineForJsonPointer returns the line in the yaml document that a JSON pointer refers to, or the closest parent that exists.
+func lineForJsonPointer(node *yaml.Node, pointer string) int {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := node.Line
+
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if idx, err := strconv.Atoi(segment); err == nil && idx >= 0 && idx < len(node.Content) {
+				next = node.Content[idx]
+				line = next.Line
+			}
+		}
+
+		if next == nil {
+			return line
+		}
+
+		node = next
+	}
+
+	return line
+}
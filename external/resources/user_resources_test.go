@@ -0,0 +1,81 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadResourcesFromYamlReturnsValidResource(t *testing.T) {
+	// Fixture Setup
+	contents := `
+wishlists:
+  singular-name: "wishlist"
+  json-api-type: "wishlist"
+  json-api-format: "legacy"
+  docs: "https://elasticpath.dev/docs/commerce-cloud/custom-apis/custom-apis-overview"
+  get-collection:
+    docs: "https://elasticpath.dev/docs/commerce-cloud/custom-apis/custom-apis-overview"
+    url: "/v2/extensions/wishlists"
+  attributes:
+    name:
+      type: STRING
+`
+
+	// Execute SUT
+	r, err := LoadResourcesFromYaml("wishlists.yaml", []byte(contents))
+
+	// Verification
+	require.NoError(t, err)
+	require.Contains(t, r, "wishlists")
+	require.Equal(t, "wishlist", r["wishlists"].SingularName)
+	require.Equal(t, "wishlists.yaml", r["wishlists"].SourceFile)
+}
+
+func TestLoadResourcesFromYamlReportsFileAndLineOfSchemaErrors(t *testing.T) {
+	// Fixture Setup
+	contents := `
+wishlists:
+  singular-name: "wishlist"
+  json-api-type: "wishlist"
+  json-api-format: "something"
+  docs: "https://elasticpath.dev/docs/commerce-cloud/custom-apis/custom-apis-overview"
+`
+
+	// Execute SUT
+	_, err := LoadResourcesFromYaml("wishlists.yaml", []byte(contents))
+
+	// Verification
+	require.ErrorContains(t, err, "wishlists.yaml:5: /wishlists/json-api-format")
+}
+
+func TestLoadResourcesFromDirectorySkipsInvalidFiles(t *testing.T) {
+	// Fixture Setup
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "valid.yaml"), []byte(`
+wishlists:
+  singular-name: "wishlist"
+  json-api-type: "wishlist"
+  json-api-format: "legacy"
+  docs: "https://elasticpath.dev/docs/commerce-cloud/custom-apis/custom-apis-overview"
+`), 0600))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yml"), []byte(`
+things:
+  unknown-key: true
+`), 0600))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(`Not a resource`), 0600))
+
+	// Execute SUT
+	r, err := LoadResourcesFromDirectory(dir)
+
+	// Verification
+	require.NoError(t, err)
+	require.Len(t, r, 1)
+	require.Contains(t, r, "wishlists")
+	require.Equal(t, filepath.Join(dir, "valid.yaml"), r["wishlists"].SourceFile)
+}
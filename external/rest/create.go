@@ -18,7 +18,7 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func CreateInternal(ctx context.Context, overrides *httpclient.HttpParameterOverrides, args []string, autoFillOnCreate bool, aliasName string, skipAliases bool, disableConstants bool, data string) (string, error) {
+func CreateInternal(ctx context.Context, overrides *httpclient.HttpParameterOverrides, args []string, autoFillOnCreate bool, aliasName string, skipAliases bool, disableConstants bool, noValidate bool, data string) (string, error) {
 	shutdown.OutstandingOpCounter.Add(1)
 	defer shutdown.OutstandingOpCounter.Done()
 
@@ -79,29 +79,36 @@ func CreateInternal(ctx context.Context, overrides *httpclient.HttpParameterOver
 		}
 
 		var body string
+		var jsonArgs []string
 
 		if data != "" {
 			// Use the provided data as the request body
 			body = data
 		} else {
 			// Create the body from remaining args
-			jsonArgs := args[(idCount + 1):]
+			jsonArgs = args[(idCount + 1):]
 
 			if !resource.NoWrapping && !disableConstants {
 				jsonArgs = append([]string{"type", resource.JsonApiType}, jsonArgs...)
 			}
 
 			if autoFillOnCreate {
-				autofilledData := autofill.GetJsonArrayForResource(&resource)
+				autofilledData := autofill.GetJsonArrayForResource(ctx, &resource, jsonArgs)
 				jsonArgs = append(autofilledData, jsonArgs...)
 			}
 
-			body, err = json.ToJson(jsonArgs, resource.NoWrapping, resource.JsonApiFormat == "compliant", resource.Attributes, true, !disableConstants)
+			body, err = json.ToJsonWithContext(ctx, jsonArgs, resource.NoWrapping, resource.JsonApiFormat == "compliant", resource.Attributes, true, !disableConstants)
 			if err != nil {
 				return "", err
 			}
 		}
 
+		if !noValidate {
+			if err := ValidateRequest(resource, resource.CreateEntityInfo, jsonArgs, body, false); err != nil {
+				return "", err
+			}
+		}
+
 		// Submit request
 		resp, err = httpclient.DoRequest(ctx, "POST", resourceURL, params.Encode(), strings.NewReader(body))
 	}
@@ -108,10 +108,10 @@ func GetResource(ctx context.Context, overrides *httpclient.HttpParameterOverrid
 	params := url.Values{}
 
 	if autoFill {
-		autoFillQueryParams := autofill.GetAutoFillQueryParameters(resource.SingularName, resourceUrlInfo.QueryParameters)
+		autoFillQueryParams := autofill.GetAutoFillQueryParameters(ctx, resource.SingularName, resourceUrlInfo.QueryParameters)
 
 		for i := 0; i < len(autoFillQueryParams); i += 2 {
-			val := templates.Render(autoFillQueryParams[i+1])
+			val := templates.RenderWithContext(ctx, autoFillQueryParams[i+1])
 			params.Add(autoFillQueryParams[i], val)
 		}
 	}
@@ -15,7 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func UpdateInternal(ctx context.Context, overrides *httpclient.HttpParameterOverrides, skipAliases bool, disableConstants bool, args []string, data string) (string, error) {
+func UpdateInternal(ctx context.Context, overrides *httpclient.HttpParameterOverrides, skipAliases bool, disableConstants bool, noValidate bool, merge *UpdateMergeOptions, args []string, data string) (string, error) {
 	shutdown.OutstandingOpCounter.Add(1)
 	defer shutdown.OutstandingOpCounter.Done()
 
@@ -47,25 +47,53 @@ func UpdateInternal(ctx context.Context, overrides *httpclient.HttpParameterOver
 		resourceURL = overrides.OverrideUrlPath
 	}
 
+	var currentState map[string]string
+	if merge != nil {
+		if data != "" {
+			return "", fmt.Errorf("the current state cannot be merged when the request body is provided")
+		}
+
+		mergedArgs, current, err := getMergedUpdateArgs(ctx, resource, args[1:idCount+1], args[idCount+1:], merge.Unset)
+		if err != nil {
+			return "", err
+		}
+
+		currentState = current
+		args = append(args[0:idCount+1:idCount+1], mergedArgs...)
+	}
+
 	var body string
+	var jsonArgs []string
 	if data != "" {
 		// Use the provided data as the request body
 		body = data
 	} else {
 		// Create the body from remaining args
-		jsonArgs := args[(idCount + 1):]
+		jsonArgs = args[(idCount + 1):]
 
 		if !resource.NoWrapping && !disableConstants {
 			jsonArgs = append([]string{"type", resource.JsonApiType}, jsonArgs...)
 		}
 
 		// Create the body from remaining args
-		body, err = json.ToJson(jsonArgs, resource.NoWrapping, resource.JsonApiFormat == "compliant", resource.Attributes, true, !disableConstants)
+		body, err = json.ToJsonWithContext(ctx, jsonArgs, resource.NoWrapping, resource.JsonApiFormat == "compliant", resource.Attributes, true, !disableConstants)
 		if err != nil {
 			return "", err
 		}
 	}
 
+	if merge != nil && merge.ShowDiff {
+		if err := printUpdateDiff(currentState, body); err != nil {
+			return "", err
+		}
+	}
+
+	if !noValidate {
+		if err := ValidateRequest(resource, resourceUrlInfo, jsonArgs, body, true); err != nil {
+			return "", err
+		}
+	}
+
 	params := url.Values{}
 
 	for _, v := range overrides.QueryParameters {
@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/httpclient"
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/elasticpath/epcc-cli/external/resources"
+)
+
+// UpdateMergeOptions are used when an update should be applied on top of the current state of the entity, this is
+// needed for endpoints where PUT replaces the entire entity.
+type UpdateMergeOptions struct {
+	// Keys to remove from the current state (e.g., tags or custom_inputs.engraving)
+	Unset []string
+
+	// Print the changes that will be made before sending the request
+	ShowDiff bool
+}
+
+// Keys that are returned by the API, but that we should never send back, keys starting with $. are outside of attributes for compliant resources (e.g., $.data.meta.timestamps.created_at)
+var readOnlyKeyPrefixes = []string{json.RootKeyPrefix, "meta.", "links.", "created_at", "updated_at", "timestamps."}
+
+// getMergedUpdateArgs returns the key and value args for an update, with the current state of the entity first and the args from the user after.
+// The current state of the entity (without read only keys) is also returned.
+func getMergedUpdateArgs(ctx context.Context, resource resources.Resource, ids []string, args []string, unset []string) ([]string, map[string]string, error) {
+	if resource.GetEntityInfo == nil {
+		return nil, nil, fmt.Errorf("resource %s doesn't support GET, so the current state cannot be merged", resource.SingularName)
+	}
+
+	getIdCount, err := resources.GetNumberOfVariablesNeeded(resource.GetEntityInfo.Url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if getIdCount != len(ids) {
+		return nil, nil, fmt.Errorf("resource %s needs %d ids to GET but %d to UPDATE, so the current state cannot be merged", resource.SingularName, getIdCount, len(ids))
+	}
+
+	resourceURL, err := resources.GenerateUrl(resource.GetEntityInfo, ids, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := httpclient.DoRequest(ctx, "GET", resourceURL, "", nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get current state of %s: %w", resource.SingularName, err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get current state of %s: %w", resource.SingularName, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		json.PrintJsonToStdout(string(body))
+		return nil, nil, fmt.Errorf("could not get current state of %s: %s", resource.SingularName, resp.Status)
+	}
+
+	current, err := getCurrentStateAsMap(resource, string(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mergeUpdateArgs(current, args, unset), current, nil
+}
+
+// mergeUpdateArgs removes the unset keys and any keys set by the args from the current state, and then returns the current state followed by the args
+func mergeUpdateArgs(current map[string]string, args []string, unset []string) []string {
+	merged := make(map[string]string, len(current))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for _, k := range unset {
+		removeKeyAndChildren(merged, k)
+	}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		removeKeyAndChildren(merged, args[i])
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]string, 0, len(merged)*2+len(args))
+	for _, k := range keys {
+		// Existing values are JSON literals, so we can escape a brace to stop them from being processed as templates
+		result = append(result, k, strings.ReplaceAll(merged[k], "{{", `{\u007b`))
+	}
+
+	return append(result, args...)
+}
+
+func getCurrentStateAsMap(resource resources.Resource, body string) (map[string]string, error) {
+	var response map[string]interface{}
+
+	if err := gojson.Unmarshal([]byte(body), &response); err != nil {
+		return nil, fmt.Errorf("could not parse current state of %s: %w", resource.SingularName, err)
+	}
+
+	data, ok := response["data"]
+	if !ok {
+		return nil, fmt.Errorf("could not find data in current state of %s", resource.SingularName)
+	}
+
+	dataJson, err := gojson.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.FromJsonToMap(string(dataJson))
+	if err != nil {
+		return nil, fmt.Errorf("could not convert current state of %s: %w", resource.SingularName, err)
+	}
+
+nextKey:
+	for k, v := range current {
+		// Values that aren't set are left out, rather than sent back as null or empty
+		switch v {
+		case "null", "[]", "{}":
+			delete(current, k)
+			continue nextKey
+		}
+
+		for _, prefix := range readOnlyKeyPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				delete(current, k)
+				continue nextKey
+			}
+		}
+
+		for _, excluded := range resource.ExcludedJsonPointersFromImport {
+			if strings.HasPrefix(k, excluded) || strings.HasPrefix("attributes."+k, excluded) {
+				delete(current, k)
+				continue nextKey
+			}
+		}
+	}
+
+	return current, nil
+}
+
+// removeKeyAndChildren removes a key and anything nested under it (e.g., tags removes tags[0] and tags[1])
+func removeKeyAndChildren(kvs map[string]string, key string) {
+	key = strings.TrimPrefix(key, "attributes.")
+
+	for k := range kvs {
+		if k == key || strings.HasPrefix(k, key+".") || strings.HasPrefix(k, key+"[") {
+			delete(kvs, k)
+		}
+	}
+}
+
+// printUpdateDiff prints the difference between the current state of an entity and the request body that will be sent.
+func printUpdateDiff(before map[string]string, body string) error {
+	after, err := json.FromJsonToMap(body)
+
+	if err != nil {
+		return fmt.Errorf("could not compare request with current state: %w", err)
+	}
+
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	changes := 0
+	for _, k := range keys {
+		oldValue, inBefore := before[k]
+		newValue, inAfter := after[k]
+
+		switch {
+		case !inAfter:
+			fmt.Fprintf(os.Stderr, "- %s: %s\n", k, oldValue)
+		case !inBefore:
+			fmt.Fprintf(os.Stderr, "+ %s: %s\n", k, newValue)
+		case oldValue != newValue:
+			fmt.Fprintf(os.Stderr, "~ %s: %s => %s\n", k, oldValue, newValue)
+		default:
+			continue
+		}
+
+		changes++
+	}
+
+	if changes == 0 {
+		fmt.Fprintf(os.Stderr, "No changes\n")
+	}
+
+	return nil
+}
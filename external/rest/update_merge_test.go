@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrentStateAsMapRemovesReadOnlyKeys(t *testing.T) {
+	// Fixture Setup
+	resource := resources.Resource{
+		SingularName:                   "widget",
+		JsonApiFormat:                  "compliant",
+		ExcludedJsonPointersFromImport: []string{"attributes.secret", "relationships.children."},
+	}
+
+	// language=json
+	body := `{
+	  "data": {
+	    "id": "123",
+	    "type": "widget",
+	    "attributes": {"name": "Ring", "secret": "shh", "tags": ["a", "b"]},
+	    "relationships": {"children": {"data": [{"id": "1"}]}, "parent": {"data": {"id": "2"}}},
+	    "meta": {"timestamps": {"created_at": "2024-01-01T00:00:00Z"}},
+	    "links": {"self": "https://example.com"}
+	  },
+	  "links": {"self": "https://example.com"}
+	}`
+
+	// Execute SUT
+	current, err := getCurrentStateAsMap(resource, body)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"id":                           `"123"`,
+		"type":                         `"widget"`,
+		"name":                         `"Ring"`,
+		"tags[0]":                      `"a"`,
+		"tags[1]":                      `"b"`,
+		"relationships.parent.data.id": `"2"`,
+	}, current)
+}
+
+func TestMergeUpdateArgsReplacesAndUnsetsKeys(t *testing.T) {
+	// Fixture Setup
+	current := map[string]string{
+		"name":                 `"Ring"`,
+		"description":          `"Uses {{ braces }}"`,
+		"tags[0]":              `"a"`,
+		"tags[1]":              `"b"`,
+		"custom_inputs.a.name": `"A"`,
+		"custom_inputs.b.name": `"B"`,
+	}
+
+	// Execute SUT
+	merged := mergeUpdateArgs(current, []string{"attributes.tags[0]", "c", "name", "Band"}, []string{"custom_inputs.a"})
+
+	// Verification
+	require.Equal(t, []string{
+		"custom_inputs.b.name", `"B"`,
+		"description", `"Uses {\u007b braces }}"`,
+		"tags[1]", `"b"`,
+		"attributes.tags[0]", "c",
+		"name", "Band",
+	}, merged)
+
+	body, err := json.ToJson(merged, false, true, map[string]*resources.CrudEntityAttribute{}, true, true)
+	require.NoError(t, err)
+	require.Equal(t, `{"data":{"attributes":{"custom_inputs":{"b":{"name":"B"}},"description":"Uses {{ braces }}","name":"Band","tags":["c","b"]}}}`, body)
+}
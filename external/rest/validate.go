@@ -0,0 +1,222 @@
+package rest
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/openapi"
+	"github.com/elasticpath/epcc-cli/external/resources"
+	log "github.com/sirupsen/logrus"
+)
+
+var attributeWithArrayIndex = regexp.MustCompile("\\[[0-9]+]")
+
+var currencyRegex = regexp.MustCompile("^[A-Z]{3}$")
+
+// Aliases that don't exist are passed through unchanged (e.g., name=foo)
+var unresolvedAliasRegex = regexp.MustCompile("^[a-z_]+=")
+
+// ValidateRequest checks a request body against the attribute types of a resource and the OpenAPI request schema of the operation (if known).
+// The args are the key value pairs the body was built from, and are used so that problems are reported against the argument that caused them.
+// Partial requests (i.e., updates) don't need to have the properties that the schema requires.
+func ValidateRequest(resource resources.Resource, crudInfo *resources.CrudEntityInfo, args []string, body string, partial bool) error {
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(body)))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("request body is not valid json: %w", err)
+	}
+
+	problems := make([]string, 0)
+	reportedKeys := map[string]bool{}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		key := args[i]
+
+		if strings.HasPrefix(key, "[") {
+			// Array syntax, the attributes don't apply
+			break
+		}
+
+		attributeName := attributeWithArrayIndex.ReplaceAllString(strings.TrimPrefix(key, "attributes."), "[n]")
+
+		attribute, ok := resource.Attributes[attributeName]
+		if !ok {
+			continue
+		}
+
+		value, ok := getValueAtPath(document, openapi.GetRequestPathForAttribute(resource, key))
+		if !ok {
+			continue
+		}
+
+		if msg := checkAttributeType(attribute, value); msg != "" {
+			problems = append(problems, fmt.Sprintf("%s %s: %s", key, args[i+1], msg))
+			reportedKeys[strings.TrimPrefix(key, "attributes.")] = true
+		}
+	}
+
+	if crudInfo != nil && crudInfo.OpenApiOperationId != "" {
+		opInfo, err := openapi.FindOperationByID(crudInfo.OpenApiOperationId)
+
+		if err != nil {
+			log.Debugf("Could not find operation %s to validate request: %v", crudInfo.OpenApiOperationId, err)
+		} else if schema := openapi.GetRequestSchema(opInfo.Operation); schema != nil {
+			reportedProblems := map[string]bool{}
+
+			validateAgainstSchema := openapi.ValidateAgainstSchema
+			if partial {
+				validateAgainstSchema = openapi.ValidatePartialAgainstSchema
+			}
+
+			for _, v := range validateAgainstSchema(schema, document) {
+				key, ok := openapi.GetAttributeForRequestPath(resource, v.Path)
+
+				if !ok {
+					key = v.Path
+				}
+
+				if reportedKeys[strings.TrimPrefix(key, "attributes.")] {
+					continue
+				}
+
+				problem := fmt.Sprintf("%s: %s", key, v.Message)
+
+				if value, ok := getArgumentValue(args, key); ok && !v.Missing {
+					problem = fmt.Sprintf("%s %s: %s", key, value, v.Message)
+				}
+
+				// The same problem can be found through more than one oneOf or allOf
+				if !reportedProblems[problem] {
+					reportedProblems[problem] = true
+					problems = append(problems, problem)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid arguments for %s (use --no-validate to send the request anyway):\n  %s", resource.SingularName, strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+func checkAttributeType(attribute *resources.CrudEntityAttribute, value interface{}) string {
+	if value == nil {
+		// Always allow clearing a value
+		return ""
+	}
+
+	aType := attribute.Type
+	s, isString := value.(string)
+
+	switch {
+	case aType == "INT":
+		if n, ok := value.(gojson.Number); !ok {
+			return "expected an integer"
+		} else if _, err := n.Int64(); err != nil {
+			return "expected an integer"
+		}
+	case aType == "FLOAT":
+		if _, ok := value.(gojson.Number); !ok {
+			return "expected a number"
+		}
+	case aType == "BOOL":
+		if _, ok := value.(bool); !ok {
+			return "expected true or false"
+		}
+	case strings.HasPrefix(aType, "ENUM:"):
+		allowed := strings.Split(strings.TrimPrefix(aType, "ENUM:"), ",")
+		actual := fmt.Sprintf("%v", value)
+
+		for _, a := range allowed {
+			if a == actual {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("expected one of [%s]", strings.Join(allowed, ","))
+	case aType == "CURRENCY":
+		if !isString || !currencyRegex.MatchString(s) {
+			return "expected a three letter currency code (e.g., USD)"
+		}
+	case aType == "URL":
+		if u, err := url.ParseRequestURI(s); !isString || err != nil || u.Scheme == "" || u.Host == "" {
+			return "expected a url (e.g., https://example.com)"
+		}
+	case strings.HasPrefix(aType, "RESOURCE_ID:") && aType != "RESOURCE_ID:*":
+		if !isString {
+			return "expected an id or alias"
+		}
+
+		if unresolvedAliasRegex.MatchString(s) {
+			return fmt.Sprintf("could not find an alias for %s", strings.TrimPrefix(aType, "RESOURCE_ID:"))
+		}
+
+		if (attribute.AliasAttribute == "" || attribute.AliasAttribute == "id") && strings.ContainsAny(s, " \t\n") {
+			return "expected an id or alias, ids cannot contain whitespace"
+		}
+	}
+
+	return ""
+}
+
+// getValueAtPath returns the value in a document at a path like data.attributes.price[0].amount
+func getValueAtPath(document interface{}, path string) (interface{}, bool) {
+	current := document
+
+	for _, segment := range strings.Split(path, ".") {
+		name := segment
+		indexes := make([]int, 0)
+
+		if idx := strings.Index(segment, "["); idx >= 0 {
+			name = segment[0:idx]
+
+			for _, i := range strings.Split(strings.Trim(segment[idx:], "[]"), "][") {
+				n, err := strconv.Atoi(i)
+				if err != nil {
+					return nil, false
+				}
+				indexes = append(indexes, n)
+			}
+		}
+
+		if name != "" {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			if current, ok = obj[name]; !ok {
+				return nil, false
+			}
+		}
+
+		for _, i := range indexes {
+			arr, ok := current.([]interface{})
+			if !ok || i < 0 || i >= len(arr) {
+				return nil, false
+			}
+			current = arr[i]
+		}
+	}
+
+	return current, true
+}
+
+func getArgumentValue(args []string, key string) (string, bool) {
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] == key || args[i] == "attributes."+key {
+			return args[i+1], true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,147 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestAcceptsValidArguments(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("subscription-proration-policies")
+	args := []string{"type", "subscription_proration_policy", "name", "Policy", "rounding", "up"}
+	body := `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy","rounding":"up","skip_zero_value_items":true}}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.CreateEntityInfo, args, body, false)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestValidateRequestReportsTheArgumentThatIsInvalid(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("subscription-proration-policies")
+	args := []string{"type", "subscription_proration_policy", "name", "Policy", "rounding", "sideways"}
+	body := `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy","rounding":"sideways","skip_zero_value_items":true}}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.CreateEntityInfo, args, body, false)
+
+	// Verification
+	require.ErrorContains(t, err, "rounding sideways: expected one of [up,down,nearest]")
+}
+
+func TestValidateRequestReportsMissingRequiredAttributes(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("subscription-proration-policies")
+	args := []string{"type", "subscription_proration_policy", "name", "Policy"}
+	body := `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy","skip_zero_value_items":true}}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.CreateEntityInfo, args, body, false)
+
+	// Verification
+	require.ErrorContains(t, err, "rounding: is required")
+}
+
+func TestValidateRequestChecksAttributeTypes(t *testing.T) {
+	// Fixture Setup
+	resource := resources.Resource{
+		SingularName:  "widget",
+		JsonApiFormat: "legacy",
+		Attributes: map[string]*resources.CrudEntityAttribute{
+			"count":         {Type: "INT"},
+			"weight":        {Type: "FLOAT"},
+			"enabled":       {Type: "BOOL"},
+			"prices[n].cur": {Type: "CURRENCY"},
+			"link":          {Type: "URL"},
+			"parent":        {Type: "RESOURCE_ID:accounts"},
+		},
+	}
+
+	args := []string{"count", "1.5", "weight", "heavy", "enabled", "yes", "prices[1].cur", "usd", "link", "example.com", "parent", "name=missing"}
+	body := `{"data":{"count":1.5,"weight":"heavy","enabled":"yes","prices":[{"cur":"USD"},{"cur":"usd"}],"link":"example.com","parent":"name=missing"}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, nil, args, body, false)
+
+	// Verification
+	require.ErrorContains(t, err, "count 1.5: expected an integer")
+	require.ErrorContains(t, err, "weight heavy: expected a number")
+	require.ErrorContains(t, err, "enabled yes: expected true or false")
+	require.ErrorContains(t, err, "prices[1].cur usd: expected a three letter currency code")
+	require.ErrorContains(t, err, "link example.com: expected a url")
+	require.ErrorContains(t, err, "parent name=missing: could not find an alias for accounts")
+}
+
+func TestValidateRequestAllowsNullValues(t *testing.T) {
+	// Fixture Setup
+	resource := resources.Resource{
+		SingularName:  "widget",
+		JsonApiFormat: "legacy",
+		Attributes: map[string]*resources.CrudEntityAttribute{
+			"count": {Type: "INT"},
+		},
+	}
+
+	// Execute SUT
+	err := ValidateRequest(resource, nil, []string{"count", "null"}, `{"data":{"count":null}}`, false)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestValidateRequestAllowsPartialUpdates(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("accounts")
+	args := []string{"type", "account", "legal_name", "Test Update"}
+	body := `{"data":{"type":"account","legal_name":"Test Update"}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.UpdateEntityInfo, args, body, true)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestValidateRequestChecksTypesOfPartialUpdates(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("subscription-proration-policies")
+	args := []string{"type", "subscription_proration_policy", "rounding", "sideways"}
+	body := `{"data":{"type":"subscription_proration_policy","attributes":{"rounding":"sideways"}}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.UpdateEntityInfo, args, body, true)
+
+	// Verification
+	require.ErrorContains(t, err, "rounding sideways: expected one of [up,down,nearest]")
+	require.NotContains(t, err.Error(), "is required")
+}
+
+func TestValidateRequestAllowsNullOnCreate(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("subscription-proration-policies")
+	args := []string{"type", "subscription_proration_policy", "name", "Policy", "rounding", "up", "external_ref", "null"}
+	body := `{"data":{"type":"subscription_proration_policy","attributes":{"name":"Policy","rounding":"up","skip_zero_value_items":true,"external_ref":null}}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.CreateEntityInfo, args, body, false)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestValidateRequestAllowsNullOnUpdate(t *testing.T) {
+	// Fixture Setup
+	resource := resources.MustGetResourceByName("carts")
+	args := []string{"description", "null"}
+	body := `{"data":{"description":null}}`
+
+	// Execute SUT
+	err := ValidateRequest(resource, resource.UpdateEntityInfo, args, body, true)
+
+	// Verification
+	require.NoError(t, err)
+}
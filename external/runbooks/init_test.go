@@ -0,0 +1,9 @@
+package runbooks
+
+import (
+	"github.com/elasticpath/epcc-cli/external/resources"
+)
+
+func init() {
+	resources.PublicInit()
+}
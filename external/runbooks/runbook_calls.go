@@ -0,0 +1,84 @@
+package runbooks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/templates"
+)
+
+// ResolveCall returns the runbook and action for a call (e.g., currencies/create-currency), actions in the current runbook are found even if it isn't loaded yet
+func ResolveCall(current *Runbook, call string) (*Runbook, *RunbookAction, error) {
+	runbookName, actionName, found := strings.Cut(call, "/")
+
+	if !found || runbookName == "" || actionName == "" {
+		return nil, nil, fmt.Errorf("invalid call %s, it should be a runbook and action, e.g., call: <runbook>/<action>", call)
+	}
+
+	var runbook *Runbook
+	if current != nil && current.Name == runbookName {
+		runbook = current
+	} else if r, ok := runbooks[runbookName]; ok {
+		runbook = &r
+	} else {
+		return nil, nil, fmt.Errorf("could not find runbook %s for call %s", runbookName, call)
+	}
+
+	runbookAction, ok := runbook.RunbookActions[actionName]
+	if !ok {
+		return nil, nil, fmt.Errorf("could not find action %s in runbook %s for call %s", actionName, runbookName, call)
+	}
+
+	return runbook, runbookAction, nil
+}
+
+// RenderCallArguments returns the variables for the called action, the defaults of the action with the values in with rendered using the variables of the caller
+func RenderCallArguments(ctx context.Context, step RunbookStep, calledAction *RunbookAction, stringVars map[string]*string, variableDefinitions map[string]Variable) (map[string]*string, error) {
+	calledArguments := make(map[string]*string, len(calledAction.Variables))
+
+	// Sorted, so that random values don't depend on the order of the map
+	keys := make([]string, 0, len(calledAction.Variables))
+	for key := range calledAction.Variables {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := templates.RenderWithContext(ctx, calledAction.Variables[key].Default)
+		calledArguments[key] = &s
+	}
+
+	withKeys := make([]string, 0, len(step.With))
+	for key := range step.With {
+		withKeys = append(withKeys, key)
+	}
+
+	sort.Strings(withKeys)
+
+	for _, key := range withKeys {
+		if _, ok := calledAction.Variables[key]; !ok {
+			return nil, fmt.Errorf("%s is not a variable of %s", key, step.Call)
+		}
+
+		templateName := fmt.Sprintf("Call: %s Variable: %s", step.Call, key)
+		renderedLines, err := RenderTemplates(ctx, templateName, step.With[key], stringVars, variableDefinitions)
+
+		if err != nil {
+			return nil, fmt.Errorf("error rendering variable %s of %s: %w", key, step.Call, err)
+		}
+
+		s := strings.Join(renderedLines, "\n")
+		calledArguments[key] = &s
+	}
+
+	for _, key := range keys {
+		if _, ok := step.With[key]; !ok && calledAction.Variables[key].Required {
+			return nil, fmt.Errorf("required variable %s of %s is not set in with", key, step.Call)
+		}
+	}
+
+	return calledArguments, nil
+}
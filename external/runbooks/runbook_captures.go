@@ -0,0 +1,175 @@
+package runbooks
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/elasticpath/epcc-cli/external/json"
+	"github.com/itchyny/gojq"
+)
+
+const captureFlag = "--capture"
+
+var captureVariableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// Capture stores the result of a jq query on the output of a command into a variable (e.g., --capture order_total='.data.meta.display_price.with_tax.amount')
+type Capture struct {
+	Variable string
+	Query    string
+}
+
+// CommandCaptures are the captures for a single command and the values captured, each command has its own so that concurrent commands don't race
+type CommandCaptures struct {
+	Captures []Capture
+
+	lock   sync.Mutex
+	values map[string]string
+}
+
+type capturesKey struct{}
+
+// ExtractCaptures removes the --capture arguments from a command, and returns them
+func ExtractCaptures(args []string) ([]string, []Capture, error) {
+	remainingArgs := make([]string, 0, len(args))
+	captures := make([]Capture, 0)
+
+	for i := 0; i < len(args); i++ {
+		var value string
+
+		switch {
+		case args[i] == "--":
+			// Everything else is an argument to the command
+			remainingArgs = append(remainingArgs, args[i:]...)
+			return remainingArgs, captures, nil
+		case args[i] == captureFlag:
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("%s needs a value, e.g., %s name='.data.name'", captureFlag, captureFlag)
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(args[i], captureFlag+"="):
+			value = strings.TrimPrefix(args[i], captureFlag+"=")
+		default:
+			remainingArgs = append(remainingArgs, args[i])
+			continue
+		}
+
+		capture, err := parseCapture(value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		captures = append(captures, capture)
+	}
+
+	return remainingArgs, captures, nil
+}
+
+func parseCapture(value string) (Capture, error) {
+	variable, query, found := strings.Cut(value, "=")
+
+	if !found || query == "" {
+		return Capture{}, fmt.Errorf("invalid capture %s, it should be a variable name and a jq query, e.g., %s name='.data.name'", value, captureFlag)
+	}
+
+	if !captureVariableName.MatchString(variable) {
+		return Capture{}, fmt.Errorf("invalid capture %s, the variable name %s should only contain letters, digits, _ and -", value, variable)
+	}
+
+	if _, err := gojq.Parse(query); err != nil {
+		return Capture{}, fmt.Errorf("invalid capture %s, could not parse jq query: %w", value, err)
+	}
+
+	return Capture{Variable: variable, Query: query}, nil
+}
+
+// WithCaptures returns a context for a command, so that it captures its output
+func WithCaptures(ctx context.Context, c *CommandCaptures) context.Context {
+	return context.WithValue(ctx, capturesKey{}, c)
+}
+
+// CapturesFromContext returns the captures for the command, or nil if nothing should be captured
+func CapturesFromContext(ctx context.Context) *CommandCaptures {
+	if ctx == nil {
+		return nil
+	}
+
+	c, _ := ctx.Value(capturesKey{}).(*CommandCaptures)
+	return c
+}
+
+type capturedValuesKey struct{}
+
+// WithCapturedValues returns a context for a command, with the values captured by earlier steps of the action (e.g., for epcc assert --from-capture)
+func WithCapturedValues(ctx context.Context, values map[string]string) context.Context {
+	return context.WithValue(ctx, capturedValuesKey{}, values)
+}
+
+// CapturedValueFromContext returns a value captured by an earlier step, and whether there is one
+func CapturedValueFromContext(ctx context.Context, name string) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	values, _ := ctx.Value(capturedValuesKey{}).(map[string]string)
+	value, ok := values[name]
+	return value, ok
+}
+
+// CapturedValueForTemplate returns a captured value (JSON) as it is used in templates, strings without quotes, and anything else as JSON
+func CapturedValueForTemplate(value string) string {
+	var s string
+	if err := gojson.Unmarshal([]byte(value), &s); err == nil {
+		return s
+	}
+
+	return value
+}
+
+// CaptureFromBody runs the queries on the body of a response, and saves the results as JSON (so that a string like "123" stays a string)
+func (c *CommandCaptures) CaptureFromBody(body string) error {
+	for _, capture := range c.Captures {
+		results, err := json.RunJQOnStringWithArray(capture.Query, body)
+
+		if err != nil {
+			return fmt.Errorf("could not capture %s: %w", capture.Variable, err)
+		}
+
+		var result interface{} = results
+		if len(results) == 1 {
+			result = results[0]
+		}
+
+		b, err := gojson.Marshal(result)
+
+		if err != nil {
+			return fmt.Errorf("could not capture %s: %w", capture.Variable, err)
+		}
+
+		c.lock.Lock()
+		if c.values == nil {
+			c.values = map[string]string{}
+		}
+		c.values[capture.Variable] = string(b)
+		c.lock.Unlock()
+	}
+
+	return nil
+}
+
+// Values returns the captured values
+func (c *CommandCaptures) Values() map[string]string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	values := make(map[string]string, len(c.values))
+	for k, v := range c.values {
+		values[k] = v
+	}
+
+	return values
+}
@@ -0,0 +1,97 @@
+package runbooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCapturesRemovesCapturesFromArguments(t *testing.T) {
+	// Fixture Setup
+	args := []string{"epcc", "create", "customer", "--capture", "email=.data.email", "name", "John", "--capture=id=.data.id"}
+
+	// Execute SUT
+	remainingArgs, captures, err := ExtractCaptures(args)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"epcc", "create", "customer", "name", "John"}, remainingArgs)
+	require.Equal(t, []Capture{{Variable: "email", Query: ".data.email"}, {Variable: "id", Query: ".data.id"}}, captures)
+}
+
+func TestExtractCapturesDoesNotRemoveArgumentsAfterDoubleDash(t *testing.T) {
+	// Fixture Setup
+	args := []string{"epcc", "create", "customer", "--", "name", "--capture"}
+
+	// Execute SUT
+	remainingArgs, captures, err := ExtractCaptures(args)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, args, remainingArgs)
+	require.Empty(t, captures)
+}
+
+func TestExtractCapturesReturnsErrorForCaptureWithoutQuery(t *testing.T) {
+	// Fixture Setup
+	args := []string{"epcc", "create", "customer", "--capture", "email"}
+
+	// Execute SUT
+	_, _, err := ExtractCaptures(args)
+
+	// Verification
+	require.ErrorContains(t, err, "invalid capture email")
+}
+
+func TestCaptureFromBodySavesValuesAsJson(t *testing.T) {
+	// Fixture Setup
+	c := &CommandCaptures{Captures: []Capture{
+		{Variable: "status", Query: ".data.status"},
+		{Variable: "code", Query: ".data.code"},
+		{Variable: "total", Query: ".data.meta.total"},
+		{Variable: "tags", Query: ".data.tags"},
+		{Variable: "ids", Query: ".data.items[].id"},
+	}}
+
+	// Execute SUT
+	err := c.CaptureFromBody(`{"data": {"status": "live", "code": "12345", "meta": {"total": 12345}, "tags": ["a"], "items": [{"id": "1"}, {"id": "2"}]}}`)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"status": `"live"`,
+		"code":   `"12345"`,
+		"total":  "12345",
+		"tags":   `["a"]`,
+		"ids":    `["1","2"]`,
+	}, c.Values())
+}
+
+func TestCapturesFromContextReturnsTheCaptures(t *testing.T) {
+	// Fixture Setup
+	c := &CommandCaptures{}
+
+	// Execute SUT
+	fromContext := CapturesFromContext(WithCaptures(context.Background(), c))
+
+	// Verification
+	require.Same(t, c, fromContext)
+	require.Nil(t, CapturesFromContext(context.Background()))
+}
+
+func TestCapturedValueForTemplateRemovesTheQuotesOfStrings(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	str := CapturedValueForTemplate(`"a@b.com"`)
+	numericStr := CapturedValueForTemplate(`"12345"`)
+	number := CapturedValueForTemplate("12345")
+	object := CapturedValueForTemplate(`{"id":"1"}`)
+
+	// Verification
+	require.Equal(t, "a@b.com", str)
+	require.Equal(t, "12345", numericStr)
+	require.Equal(t, "12345", number)
+	require.Equal(t, `{"id":"1"}`, object)
+}
@@ -0,0 +1,216 @@
+package runbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elasticpath/epcc-cli/external/profiles"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CommandSucceeded = "succeeded"
+	CommandFailed    = "failed"
+)
+
+var stepIdFormat = regexp.MustCompile(`^[1-9][0-9]*(\.[1-9][0-9]*)*$`)
+
+// Checkpoint is the progress of a runbook action, saved after each step so that it can be resumed
+type Checkpoint struct {
+	Runbook string `json:"runbook"`
+	Action  string `json:"action"`
+
+	// The values of the variables of the action
+	Variables map[string]string `json:"variables"`
+
+	// The values captured by the steps that ran
+	Captured map[string]string `json:"captured"`
+
+	// The ID of the last step that finished (or was skipped), resuming starts after it
+	LastCompletedStep string `json:"last_completed_step"`
+
+	// The ID of the last step that ran, and the status of its commands (so that commands that succeeded aren't run again if it failed)
+	CurrentStep string          `json:"current_step"`
+	Commands    []CommandStatus `json:"commands"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommandStatus is the status of a command in a step
+type CommandStatus struct {
+	Index   int    `json:"index"`
+	Command string `json:"command"`
+	Status  string `json:"status"`
+}
+
+// NewCheckpoint returns a checkpoint for an action that hasn't run yet
+func NewCheckpoint(runbookName string, actionName string) *Checkpoint {
+	return &Checkpoint{
+		Runbook:   runbookName,
+		Action:    actionName,
+		Variables: map[string]string{},
+		Captured:  map[string]string{},
+	}
+}
+
+// LoadCheckpoint returns the saved checkpoint for an action
+func LoadCheckpoint(runbookName string, actionName string) (*Checkpoint, error) {
+	checkpointPath := getCheckpointPath(runbookName, actionName)
+
+	data, err := os.ReadFile(checkpointPath)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("there is no checkpoint for %s %s to resume from", runbookName, actionName)
+		}
+
+		return nil, fmt.Errorf("could not read checkpoint %s: %w", checkpointPath, err)
+	}
+
+	checkpoint := NewCheckpoint(runbookName, actionName)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("could not read checkpoint %s: %w", checkpointPath, err)
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the profile data directory
+func (c *Checkpoint) Save() error {
+	c.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not save checkpoint: %w", err)
+	}
+
+	checkpointPath := getCheckpointPath(c.Runbook, c.Action)
+
+	// We will write to a temp file and then rename, so that a checkpoint is never half written
+	tmpFileName := checkpointPath + "." + uuid.New().String()
+
+	if err := os.WriteFile(tmpFileName, data, 0600); err != nil {
+		return fmt.Errorf("could not save checkpoint %s: %w", checkpointPath, err)
+	}
+
+	if err := os.Rename(tmpFileName, checkpointPath); err != nil {
+		return fmt.Errorf("could not save checkpoint %s: %w", checkpointPath, err)
+	}
+
+	log.Tracef("Saved checkpoint to %s", checkpointPath)
+	return nil
+}
+
+// Delete removes the saved checkpoint
+func (c *Checkpoint) Delete() {
+	checkpointPath := getCheckpointPath(c.Runbook, c.Action)
+
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Could not delete checkpoint %s: %v", checkpointPath, err)
+	}
+}
+
+// CommandSucceeded returns whether the command succeeded the last time the step ran
+func (c *Checkpoint) CommandSucceeded(stepId string, commandIdx int, command string) bool {
+	if c.CurrentStep != stepId {
+		return false
+	}
+
+	for _, commandStatus := range c.Commands {
+		if commandStatus.Index == commandIdx && commandStatus.Command == command {
+			return commandStatus.Status == CommandSucceeded
+		}
+	}
+
+	return false
+}
+
+func getCheckpointPath(runbookName string, actionName string) string {
+	dir := filepath.Clean(profiles.GetProfileDataDirectory() + "/runbook_checkpoints")
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Warnf("Could not make directory %s: %v", dir, err)
+	}
+
+	return filepath.Clean(dir + "/" + url.PathEscape(runbookName) + "." + url.PathEscape(actionName) + ".json")
+}
+
+// StepRange is which steps to run, by their IDs (see IdentifySteps), the steps rendered by a step are in the range if it is (e.g., 3.2 is between 2 and 3)
+type StepRange struct {
+	// The first step to run
+	From string
+
+	// The last step to run
+	To string
+
+	// Steps until and including this step are skipped (i.e., when resuming)
+	After string
+}
+
+// NewStepRange returns the range of steps between from and to (either can be empty)
+func NewStepRange(from string, to string) (StepRange, error) {
+	for _, id := range []string{from, to} {
+		if id != "" && !stepIdFormat.MatchString(id) {
+			return StepRange{}, fmt.Errorf("invalid step %s, steps are numbered from 1, and steps rendered by a step have the number of the step and their position (e.g., 3.2)", id)
+		}
+	}
+
+	if from != "" && to != "" && compareStepIds(from, to) > 0 {
+		return StepRange{}, fmt.Errorf("the first step %s is after the last step %s", from, to)
+	}
+
+	return StepRange{From: from, To: to}, nil
+}
+
+// Skips returns whether a step is outside the range, a step that renders steps in the range isn't skipped
+func (r StepRange) Skips(id string) bool {
+	if r.From != "" && compareStepIds(id, r.From) < 0 {
+		return true
+	}
+
+	if r.To != "" && compareStepIds(id, r.To) > 0 {
+		return true
+	}
+
+	if r.After != "" {
+		if cmp := compareStepIds(id, r.After); cmp < 0 || (cmp == 0 && len(strings.Split(id, ".")) >= len(strings.Split(r.After, "."))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IncludesEnd returns whether the range runs every step until the end
+func (r StepRange) IncludesEnd() bool {
+	return r.To == ""
+}
+
+// compareStepIds compares the numbers in the IDs, an ID and the IDs of the steps it renders are equal (e.g., 3 and 3.2)
+func compareStepIds(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, _ := strconv.Atoi(aParts[i])
+		bNum, _ := strconv.Atoi(bParts[i])
+
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
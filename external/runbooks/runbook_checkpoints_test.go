@@ -0,0 +1,110 @@
+package runbooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThatStepIdsAreStableWhenStepsRenderYamlArrays(t *testing.T) {
+	// Fixture Setup
+	steps := IdentifySteps("", []RunbookStep{{Run: "epcc get customers"}, {Run: "- sleep 1\n- sleep 2"}, {Run: "epcc get accounts"}})
+
+	// Execute SUT
+	renderedSteps, ok := ParseRenderedSteps(steps[1], []string{"- sleep 1", "- sleep 2"})
+
+	// Verification
+	require.True(t, ok)
+	require.Equal(t, []string{"1", "2", "3"}, []string{steps[0].ID, steps[1].ID, steps[2].ID})
+	require.Equal(t, []string{"2.1", "2.2"}, []string{renderedSteps[0].ID, renderedSteps[1].ID})
+}
+
+func TestThatStepRangeSkipsStepsOutsideTheRange(t *testing.T) {
+	// Fixture Setup
+	stepRange, err := NewStepRange("2.2", "3")
+	require.NoError(t, err)
+
+	// Execute SUT
+	skipped := map[string]bool{}
+	for _, id := range []string{"1", "2", "2.1", "2.2", "2.3", "3", "3.1", "4"} {
+		skipped[id] = stepRange.Skips(id)
+	}
+
+	// Verification
+	require.Equal(t, map[string]bool{
+		"1":   true,
+		"2":   false,
+		"2.1": true,
+		"2.2": false,
+		"2.3": false,
+		"3":   false,
+		"3.1": false,
+		"4":   true,
+	}, skipped)
+}
+
+func TestThatStepRangeWhenResumingSkipsCompletedSteps(t *testing.T) {
+	// Fixture Setup
+	stepRange := StepRange{After: "2.1"}
+
+	// Execute SUT
+	skipped := map[string]bool{}
+	for _, id := range []string{"1", "2", "2.1", "2.2", "3"} {
+		skipped[id] = stepRange.Skips(id)
+	}
+
+	// Verification
+	require.Equal(t, map[string]bool{
+		"1":   true,
+		"2":   false,
+		"2.1": true,
+		"2.2": false,
+		"3":   false,
+	}, skipped)
+}
+
+func TestThatInvalidStepRangeFails(t *testing.T) {
+	// Fixture Setup
+
+	// Execute SUT
+	_, invalidErr := NewStepRange("0", "")
+	_, reversedErr := NewStepRange("4", "3.1")
+
+	// Verification
+	require.ErrorContains(t, invalidErr, "invalid step 0")
+	require.ErrorContains(t, reversedErr, "the first step 4 is after the last step 3.1")
+}
+
+func TestThatCheckpointCanBeSavedAndLoaded(t *testing.T) {
+	// Fixture Setup
+	t.Setenv("HOME", t.TempDir())
+
+	checkpoint := NewCheckpoint("unit-test-runbook", "test-action")
+	checkpoint.Variables["count"] = "3"
+	checkpoint.Captured["customer_email"] = "test@test.example"
+	checkpoint.LastCompletedStep = "2.1"
+	checkpoint.CurrentStep = "2.2"
+	checkpoint.Commands = []CommandStatus{
+		{Index: 0, Command: "epcc get customers", Status: CommandSucceeded},
+		{Index: 1, Command: "epcc get accounts", Status: CommandFailed},
+	}
+
+	// Execute SUT
+	err := checkpoint.Save()
+	require.NoError(t, err)
+
+	loadedCheckpoint, loadErr := LoadCheckpoint("unit-test-runbook", "test-action")
+
+	checkpoint.Delete()
+	_, deletedErr := LoadCheckpoint("unit-test-runbook", "test-action")
+
+	// Verification
+	require.NoError(t, loadErr)
+	require.Equal(t, checkpoint.Variables, loadedCheckpoint.Variables)
+	require.Equal(t, checkpoint.Captured, loadedCheckpoint.Captured)
+	require.Equal(t, "2.1", loadedCheckpoint.LastCompletedStep)
+	require.True(t, loadedCheckpoint.CommandSucceeded("2.2", 0, "epcc get customers"))
+	require.False(t, loadedCheckpoint.CommandSucceeded("2.2", 1, "epcc get accounts"))
+	require.False(t, loadedCheckpoint.CommandSucceeded("2.1", 0, "epcc get customers"))
+	require.ErrorContains(t, deletedErr, "there is no checkpoint")
+}
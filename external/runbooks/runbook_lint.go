@@ -0,0 +1,362 @@
+package runbooks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/elasticpath/epcc-cli/external/resources"
+	"github.com/expr-lang/expr"
+)
+
+// LintProblem is something wrong with an epcc command in a runbook, warnings are likely mistakes that don't stop the runbook from running
+type LintProblem struct {
+	Runbook string
+	Action  string
+	Step    string
+	Line    int
+	Warning bool
+	Message string
+}
+
+func (p LintProblem) Error() string {
+	return fmt.Sprintf("%s/%s step %s line %d: %s", p.Runbook, p.Action, p.Step, p.Line, p.Message)
+}
+
+// parseCommandFlags parses the flags of an epcc command in a runbook (e.g., [create customer --auto-fill name Ron]) and returns the other arguments (e.g., [create customer name Ron]),
+// it is set by the cmd package, which has the commands
+var parseCommandFlags func(args []string) ([]string, error)
+
+// SetCommandFlagParser sets how the flags of epcc commands in runbooks are parsed when validating them
+func SetCommandFlagParser(parser func(args []string) ([]string, error)) {
+	parseCommandFlags = parser
+}
+
+var arrayIndexPattern = regexp.MustCompile("\\[[0-9]+]")
+
+// Aliases are an attribute and a value (e.g., name=Ron), ids can't contain =
+var aliasPattern = regexp.MustCompile("^[a-z_]+=")
+
+// lintCommand is an epcc command in a runbook (without epcc), rendered with the default values of the variables
+type lintCommand struct {
+	location LintProblem
+	args     []string
+
+	// Attributes that the resource doesn't have, but that the step sends on purpose
+	ignoredAttributes []string
+}
+
+// runbookLinter collects the epcc commands of a runbook as it is validated, so that they can be checked in the order they run
+type runbookLinter struct {
+	// The commands of each action of the runbook (including the actions it calls)
+	commands map[string][]lintCommand
+	action   string
+}
+
+func newRunbookLinter() *runbookLinter {
+	return &runbookLinter{commands: map[string][]lintCommand{}}
+}
+
+func (l *runbookLinter) addCommand(runbookName string, actionName string, step RunbookStep, line int, args []string) {
+	l.commands[l.action] = append(l.commands[l.action], lintCommand{
+		location:          LintProblem{Runbook: runbookName, Action: actionName, Step: step.ID, Line: line},
+		args:              args,
+		ignoredAttributes: step.LintIgnoreAttributes,
+	})
+}
+
+// problems checks the commands of each action against the resources, aliases must be for a type that an earlier command in the action,
+// or any command in another action of the runbook, creates or retrieves
+func (l *runbookLinter) problems(actionNames []string) []LintProblem {
+	problems := make([]LintProblem, 0)
+
+	if parseCommandFlags == nil {
+		// The commands aren't known (e.g., in tests of this package)
+		return problems
+	}
+
+	producedByAction := make(map[string]map[string]bool, len(l.commands))
+
+	for action, commands := range l.commands {
+		producedByAction[action] = map[string]bool{}
+
+		for _, c := range commands {
+			if resource, ok := producedResource(c.args); ok {
+				producedByAction[action][resource.JsonApiType] = true
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, action := range actionNames {
+		produced := map[string]bool{}
+
+		for otherAction, types := range producedByAction {
+			if otherAction != action {
+				for t := range types {
+					produced[t] = true
+				}
+			}
+		}
+
+		for _, c := range l.commands[action] {
+			for _, problem := range lintEpccCommand(c, produced) {
+				// Called actions are checked for each call, and on their own
+				if key := fmt.Sprintf("%v %s", problem.Warning, problem.Error()); !seen[key] {
+					seen[key] = true
+					problems = append(problems, problem)
+				}
+			}
+
+			if resource, ok := producedResource(c.args); ok {
+				produced[resource.JsonApiType] = true
+			}
+		}
+	}
+
+	return problems
+}
+
+// producedResource returns the resource that a command creates or retrieves, and so has aliases afterwards
+func producedResource(args []string) (resources.Resource, bool) {
+	positional, err := parseCommandFlags(args)
+
+	if err != nil || len(positional) < 2 {
+		return resources.Resource{}, false
+	}
+
+	switch positional[0] {
+	case "get", "create", "update", "assert":
+		return resources.GetResourceByName(positional[1])
+	}
+
+	return resources.Resource{}, false
+}
+
+// lintEpccCommand checks that the resource of a command supports the verb, that the ids and attributes are for the resource, and that the flags exist
+func lintEpccCommand(c lintCommand, produced map[string]bool) []LintProblem {
+	var problems []LintProblem
+
+	problem := func(warning bool, format string, a ...any) {
+		p := c.location
+		p.Warning = warning
+		p.Message = fmt.Sprintf(format, a...)
+		problems = append(problems, p)
+	}
+
+	positional, err := parseCommandFlags(c.args)
+
+	if err != nil {
+		problem(false, "%v", err)
+		return problems
+	}
+
+	if len(positional) < 2 {
+		if len(positional) == 1 && positional[0] != "assert" {
+			problem(false, "%s needs a resource", positional[0])
+		}
+
+		return problems
+	}
+
+	verb := positional[0]
+	resourceName := positional[1]
+	resource, ok := resources.GetResourceByName(resourceName)
+
+	if !ok {
+		problem(false, "unknown resource %s", resourceName)
+		return problems
+	}
+
+	var crudInfo *resources.CrudEntityInfo
+
+	switch verb {
+	case "get", "assert":
+		crudInfo = resource.GetEntityInfo
+		if resourceName == resource.PluralName {
+			crudInfo = resource.GetCollectionInfo
+		}
+	case "create":
+		crudInfo = resource.CreateEntityInfo
+	case "update":
+		crudInfo = resource.UpdateEntityInfo
+	case "delete":
+		crudInfo = resource.DeleteEntityInfo
+	case "delete-all":
+		crudInfo = resource.GetCollectionInfo
+		if resource.DeleteEntityInfo == nil {
+			crudInfo = nil
+		}
+	}
+
+	if crudInfo == nil {
+		problem(false, "%s does not support %s", resourceName, verb)
+		return problems
+	}
+
+	// A command with --if-alias-exists doesn't run if the alias doesn't exist (e.g., when cleaning up)
+	guardedAlias := ifAliasExists(c.args)
+
+	checkAlias := func(value string, typeName string) {
+		// The type of last_read and related aliases depends on the response, not the command
+		if !aliasPattern.MatchString(value) || strings.HasPrefix(value, "last_read=") || strings.HasPrefix(value, "related_") || value == guardedAlias {
+			return
+		}
+
+		aliasResource, ok := resources.GetResourceByName(typeName)
+
+		if !ok {
+			return
+		}
+
+		for _, t := range append([]string{aliasResource.JsonApiType}, aliasResource.AlternateJsonApiTypesForAliases...) {
+			if produced[t] {
+				return
+			}
+		}
+
+		problem(true, "alias %s is for a %s, but no earlier command in the runbook creates or retrieves one", value, aliasResource.SingularName)
+	}
+
+	if verb == "delete-all" {
+		return problems
+	}
+
+	types, err := resources.GetTypesOfVariablesNeeded(crudInfo.Url)
+
+	if err != nil {
+		return problems
+	}
+
+	ids := positional[2:]
+
+	if len(ids) < len(types) {
+		singularTypes, _ := resources.GetSingularTypesOfVariablesNeeded(crudInfo.Url)
+		problem(false, "%s %s needs %d id(s) (%s), but got %d", verb, resourceName, len(types), strings.Join(singularTypes, ", "), len(ids))
+		return problems
+	}
+
+	for idx, typeName := range types {
+		checkAlias(ids[idx], typeName)
+	}
+
+	if verb != "create" && verb != "update" {
+		return problems
+	}
+
+	keyValues := positional[2+len(types):]
+
+	if len(keyValues) > 0 && strings.HasPrefix(keyValues[0], "[") {
+		// Array syntax, the attributes don't apply
+		return problems
+	}
+
+	if len(keyValues)%2 != 0 {
+		problem(false, "%s %s has %d arguments after the id(s), but they should be key value pairs (is there an extra or missing id?)", verb, resourceName, len(keyValues))
+		return problems
+	}
+
+	// The values of the command, for when conditions of attributes
+	env := make(map[string]string, len(keyValues)/2)
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		env[keyValues[i]] = keyValues[i+1]
+	}
+
+	// Unknown keys are reported once for each top level attribute (e.g., once for included.items[0].sku, included.items[1].sku)
+	unknownTopLevelKeys := map[string]bool{}
+
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		key, value := keyValues[i], keyValues[i+1]
+
+		attribute, ok := findAttribute(resource, key)
+
+		if !ok {
+			if resource.AdditionalAttributes {
+				// Attributes that aren't listed are allowed (e.g., fields of flows)
+				continue
+			}
+
+			topLevelKey := strings.FieldsFunc(strings.TrimPrefix(key, "attributes."), func(r rune) bool { return r == '.' || r == '[' })[0]
+
+			if slices.Contains(c.ignoredAttributes, key) || slices.Contains(c.ignoredAttributes, topLevelKey) {
+				continue
+			}
+
+			if !unknownTopLevelKeys[topLevelKey] {
+				unknownTopLevelKeys[topLevelKey] = true
+				problem(true, "%s is not a known attribute of %s", key, resource.SingularName)
+			}
+
+			continue
+		}
+
+		if attribute.When != "" && verb == "create" {
+			if enabled, err := expr.Eval(attribute.When, env); err == nil && enabled == false {
+				problem(true, "%s is only an attribute of %s when %s", key, resource.SingularName, attribute.When)
+			}
+		}
+
+		if strings.HasPrefix(attribute.Type, "ENUM:") {
+			allowed := strings.Split(strings.TrimPrefix(attribute.Type, "ENUM:"), ",")
+
+			if !slices.Contains(allowed, value) {
+				problem(true, "%s %s is not one of [%s]", key, value, strings.Join(allowed, ","))
+			}
+		}
+
+		if strings.HasPrefix(attribute.Type, "RESOURCE_ID:") {
+			checkAlias(value, strings.TrimPrefix(attribute.Type, "RESOURCE_ID:"))
+		}
+	}
+
+	return problems
+}
+
+// ifAliasExists returns the value of the --if-alias-exists flag of a command, or "" if it doesn't have one
+func ifAliasExists(args []string) string {
+	for i, arg := range args {
+		if arg == "--if-alias-exists" && i+1 < len(args) {
+			return args[i+1]
+		}
+
+		if value, ok := strings.CutPrefix(arg, "--if-alias-exists="); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// findAttribute returns the attribute of a resource for a key, array indexes (e.g., [0]) match [n], and attributes can be regular expressions
+func findAttribute(resource resources.Resource, key string) (*resources.CrudEntityAttribute, bool) {
+	key = arrayIndexPattern.ReplaceAllString(key, "[n]")
+
+	// Attributes are usually named without the attributes. prefix, but some need it (e.g., attributes.type, as type is the type of the resource)
+	names := []string{strings.TrimPrefix(key, "attributes."), key}
+
+	for _, name := range names {
+		if attribute, ok := resource.Attributes[name]; ok {
+			return attribute, true
+		}
+	}
+
+	for attributeName, attribute := range resource.Attributes {
+		if strings.HasPrefix(attributeName, "^") && strings.HasSuffix(attributeName, "$") {
+			r, err := regexp.Compile(attributeName)
+
+			if err != nil {
+				continue
+			}
+
+			for _, name := range names {
+				if r.MatchString(name) {
+					return attribute, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
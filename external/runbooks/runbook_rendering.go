@@ -2,12 +2,11 @@ package runbooks
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"text/template"
 
-	"github.com/Masterminds/sprig/v3"
 	"github.com/elasticpath/epcc-cli/external/templates"
 )
 
@@ -21,8 +20,9 @@ func CreateMapForRunbookArgumentPointers(runbookAction *RunbookAction) map[strin
 	return runbookStringArguments
 }
 
-func RenderTemplates(templateName string, rawCmd string, stringVars map[string]*string, variableDefinitions map[string]Variable) ([]string, error) {
-	tpl, err := template.New(templateName).Funcs(sprig.FuncMap()).Funcs(templates.AddlFuncs).Parse(rawCmd)
+// RenderTemplates renders a step of a runbook, with random values from the stream in the context
+func RenderTemplates(ctx context.Context, templateName string, rawCmd string, stringVars map[string]*string, variableDefinitions map[string]Variable) ([]string, error) {
+	tpl, err := template.New(templateName).Funcs(templates.FuncMap(ctx)).Parse(rawCmd)
 
 	if err != nil {
 		// Handle this case better
@@ -34,36 +34,13 @@ func RenderTemplates(templateName string, rawCmd string, stringVars map[string]*
 	data := map[string]interface{}{}
 	for key, val := range stringVars {
 		if variableDef, ok := variableDefinitions[key]; ok {
+			parsedVal, err := ParseVariableValue(variableDef.Type, *val)
 
-			if variableDef.Type == "INT" {
-				parsedVal, err := strconv.Atoi(*val)
-
-				if err != nil {
-					return nil, fmt.Errorf("error processing variable %s, value %v is not an integer: %w", key, *val, err)
-				}
-				data[key] = parsedVal
-			} else if variableDef.Type == "STRING" {
-				data[key] = val
-			} else if strings.HasPrefix(variableDef.Type, "RESOURCE_ID:") {
-				data[key] = val
-			} else if strings.HasPrefix(variableDef.Type, "ENUM:") {
-				// ENUM types are treated as strings, validate the value is one of the enum options
-				enumValues := strings.Split(variableDef.Type[5:], ",")
-				validValue := false
-				for _, enumVal := range enumValues {
-					if *val == enumVal {
-						validValue = true
-						break
-					}
-				}
-				if !validValue {
-					return nil, fmt.Errorf("error processing variable %s, value %q is not a valid enum option. Valid options are: [%s]", key, *val, strings.Join(enumValues, ", "))
-				}
-				data[key] = val
-			} else {
-				return nil, fmt.Errorf("error processing variable %s, unknown type [%s] specified in template", key, variableDef.Type)
+			if err != nil {
+				return nil, fmt.Errorf("error processing variable %s, %w", key, err)
 			}
 
+			data[key] = parsedVal
 		} else {
 			return nil, fmt.Errorf("undefined variable %s", key)
 		}
@@ -0,0 +1,410 @@
+package runbooks
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Report is what happened when an action ran, for CI tools (e.g., as JSON or JUnit XML).
+// The methods do nothing on a nil report, so they can be called when no report is wanted
+type Report struct {
+	lock sync.Mutex
+
+	Runbook    string        `json:"runbook"`
+	Action     string        `json:"action"`
+	StartTime  time.Time     `json:"start_time"`
+	EndTime    time.Time     `json:"end_time"`
+	DurationMs int64         `json:"duration_ms"`
+	Error      string        `json:"error,omitempty"`
+	Steps      []*StepReport `json:"steps"`
+}
+
+// StepReport is what happened in a step, the steps of called actions have the ID of the call as a prefix (e.g., 3.1)
+type StepReport struct {
+	lock *sync.Mutex
+
+	ID         string           `json:"id"`
+	Name       string           `json:"name,omitempty"`
+	Call       string           `json:"call,omitempty"`
+	Skipped    bool             `json:"skipped"`
+	SkipReason string           `json:"skip_reason,omitempty"`
+	StartTime  time.Time        `json:"start_time"`
+	EndTime    time.Time        `json:"end_time"`
+	DurationMs int64            `json:"duration_ms"`
+	Error      string           `json:"error,omitempty"`
+	Commands   []*CommandReport `json:"commands"`
+}
+
+// CommandReport is what happened when a command in a step ran
+type CommandReport struct {
+	lock *sync.Mutex
+
+	Index      int       `json:"index"`
+	Command    string    `json:"command"`
+	Skipped    bool      `json:"skipped"`
+	SkipReason string    `json:"skip_reason,omitempty"`
+	Attempts   int       `json:"attempts"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	DurationMs int64     `json:"duration_ms"`
+	Requests   int       `json:"requests"`
+	// The number of requests with each status code, connection errors have a status code of 0
+	StatusCodes map[int]int `json:"status_codes"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// NewReport returns a report for an action that is starting
+func NewReport(runbookName string, actionName string) *Report {
+	return &Report{
+		Runbook:   runbookName,
+		Action:    actionName,
+		StartTime: time.Now(),
+		Steps:     []*StepReport{},
+	}
+}
+
+// Finish records that the action finished, with the error if it failed
+func (r *Report) Finish(err error) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.EndTime = time.Now()
+	r.DurationMs = r.EndTime.Sub(r.StartTime).Milliseconds()
+	r.Error = errorMessage(err)
+}
+
+// AddStep records that a step is starting
+func (r *Report) AddStep(id string, name string) *StepReport {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := &StepReport{
+		lock:      &r.lock,
+		ID:        id,
+		Name:      name,
+		StartTime: time.Now(),
+		Commands:  []*CommandReport{},
+	}
+
+	r.Steps = append(r.Steps, s)
+	return s
+}
+
+// Skip records that the step was skipped
+func (s *StepReport) Skip(reason string) {
+	if s == nil {
+		return
+	}
+
+	s.Finish(nil)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Skipped = true
+	s.SkipReason = reason
+}
+
+// Calls records the action the step calls
+func (s *StepReport) Calls(call string) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Call = call
+}
+
+// Finish records that the step finished, with the error if it failed
+func (s *StepReport) Finish(err error) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.EndTime = time.Now()
+	s.DurationMs = s.EndTime.Sub(s.StartTime).Milliseconds()
+	s.Error = errorMessage(err)
+}
+
+// AddCommand records a command of the step, before it starts
+func (s *StepReport) AddCommand(commandIdx int, command string) *CommandReport {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c := &CommandReport{
+		lock:        s.lock,
+		Index:       commandIdx,
+		Command:     command,
+		StatusCodes: map[int]int{},
+	}
+
+	s.Commands = append(s.Commands, c)
+	return c
+}
+
+// Skip records that the command was skipped
+func (c *CommandReport) Skip(reason string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.Skipped = true
+	c.SkipReason = reason
+}
+
+// Start records that the command is starting
+func (c *CommandReport) Start() {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.StartTime = time.Now()
+}
+
+// Finish records that the command finished, with the requests it made, and the error if it failed
+func (c *CommandReport) Finish(attempts int, requests int, statusCodes map[int]int, err error) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.EndTime = time.Now()
+	c.DurationMs = c.EndTime.Sub(c.StartTime).Milliseconds()
+	c.Attempts = attempts
+	c.Requests = requests
+	c.StatusCodes = statusCodes
+	c.Error = errorMessage(err)
+}
+
+// WriteJson writes the report as JSON to a file
+func (r *Report) WriteJson(path string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not write report %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write report %s: %w", path, err)
+	}
+
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit returns the report as JUnit XML, each step is a test suite and each command a test case (a step without commands, e.g., a skipped step or a call, is a test case itself)
+func (r *Report) JUnit() ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	suites := junitTestSuites{
+		Name: r.Runbook + "/" + r.Action,
+		Time: junitSeconds(r.DurationMs),
+	}
+
+	for _, step := range r.Steps {
+		suiteName := "Step " + step.ID
+		if step.Name != "" {
+			suiteName += ": " + step.Name
+		}
+
+		suite := junitTestSuite{
+			Name:      suiteName,
+			Time:      junitSeconds(step.DurationMs),
+			Timestamp: step.StartTime.Format("2006-01-02T15:04:05"),
+		}
+
+		className := r.Runbook + "." + r.Action + ".step-" + step.ID
+
+		for _, command := range step.Commands {
+			testCase := junitTestCase{
+				Name:      command.Command,
+				ClassName: className,
+				Time:      junitSeconds(command.DurationMs),
+			}
+
+			if command.Skipped {
+				testCase.Skipped = &junitMessage{Message: command.SkipReason}
+			} else {
+				testCase.SystemOut = fmt.Sprintf("attempts: %d, requests: %d, status codes: %s", command.Attempts, command.Requests, formatStatusCodes(command.StatusCodes))
+			}
+
+			if command.Error != "" {
+				testCase.Failure = &junitMessage{Message: command.Error, Text: command.Error}
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		if len(step.Commands) == 0 {
+			testCase := junitTestCase{
+				Name:      suiteName,
+				ClassName: className,
+				Time:      junitSeconds(step.DurationMs),
+			}
+
+			if step.Call != "" {
+				testCase.Name = "call " + step.Call
+			}
+
+			if step.Skipped {
+				testCase.Skipped = &junitMessage{Message: step.SkipReason}
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		if step.Error != "" && !suiteHasFailure(suite) {
+			// The step failed without a command failing (e.g., a timeout or a failed call)
+			suite.Cases[len(suite.Cases)-1].Failure = &junitMessage{Message: step.Error, Text: step.Error}
+		}
+
+		for _, testCase := range suite.Cases {
+			suite.Tests++
+
+			if testCase.Failure != nil {
+				suite.Failures++
+			} else if testCase.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// WriteJUnit writes the report as JUnit XML to a file
+func (r *Report) WriteJUnit(path string) error {
+	data, err := r.JUnit()
+
+	if err != nil {
+		return fmt.Errorf("could not write report %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write report %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func suiteHasFailure(suite junitTestSuite) bool {
+	for _, testCase := range suite.Cases {
+		if testCase.Failure != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func junitSeconds(durationMs int64) string {
+	return strconv.FormatFloat(float64(durationMs)/1000, 'f', 3, 64)
+}
+
+func formatStatusCodes(statusCodes map[int]int) string {
+	keys := make([]int, 0, len(statusCodes))
+	for k := range statusCodes {
+		keys = append(keys, k)
+	}
+
+	sort.Ints(keys)
+
+	counts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == 0 {
+			counts = append(counts, fmt.Sprintf("CONN_ERROR:%d", statusCodes[k]))
+		} else {
+			counts = append(counts, fmt.Sprintf("%d:%d", k, statusCodes[k]))
+		}
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
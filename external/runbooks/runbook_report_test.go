@@ -0,0 +1,102 @@
+package runbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThatJUnitReportHasASuiteForEachStepAndACaseForEachCommand(t *testing.T) {
+	// Fixture Setup
+	report := NewReport("unit-test-runbook", "setup")
+
+	step := report.AddStep("1", "create currencies")
+	usd := step.AddCommand(0, "epcc create currency code USD")
+	usd.Start()
+	usd.Finish(1, 1, map[int]int{201: 1}, nil)
+	eur := step.AddCommand(1, "epcc create currency code EUR")
+	eur.Start()
+	eur.Finish(3, 3, map[int]int{409: 3}, fmt.Errorf("409 Conflict"))
+	step.Finish(fmt.Errorf("error occurred while processing script aborting"))
+
+	report.AddStep("2", "").Skip("with_catalog == \"yes\" is false")
+	report.Finish(fmt.Errorf("error occurred while processing script aborting"))
+
+	// Execute SUT
+	data, err := report.JUnit()
+
+	// Verification
+	require.NoError(t, err)
+	xml := string(data)
+	require.Contains(t, xml, `<testsuites name="unit-test-runbook/setup" tests="3" failures="1" skipped="1"`)
+	require.Contains(t, xml, `<testsuite name="Step 1: create currencies" tests="2" failures="1" skipped="0"`)
+	require.Contains(t, xml, `<failure message="409 Conflict">409 Conflict</failure>`)
+	require.Contains(t, xml, `<system-out>attempts: 3, requests: 3, status codes: 409:3</system-out>`)
+	require.Contains(t, xml, `<skipped message="with_catalog == &#34;yes&#34; is false"></skipped>`)
+}
+
+func TestThatJUnitReportHasAFailureForAStepThatFailedWithoutAFailedCommand(t *testing.T) {
+	// Fixture Setup
+	report := NewReport("unit-test-runbook", "setup")
+
+	step := report.AddStep("1", "")
+	step.Calls("unit-test-runbook/other")
+	step.Finish(fmt.Errorf("error in called action unit-test-runbook/other"))
+
+	// Execute SUT
+	data, err := report.JUnit()
+
+	// Verification
+	require.NoError(t, err)
+	require.Contains(t, string(data), `<testcase name="call unit-test-runbook/other" classname="unit-test-runbook.setup.step-1"`)
+	require.Contains(t, string(data), `<failure message="error in called action unit-test-runbook/other">`)
+}
+
+func TestThatJsonReportIsWritten(t *testing.T) {
+	// Fixture Setup
+	report := NewReport("unit-test-runbook", "setup")
+	command := report.AddStep("1", "").AddCommand(0, "epcc get currencies")
+	command.Skip("already succeeded")
+	report.Finish(nil)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+
+	// Execute SUT
+	err := report.WriteJson(path)
+
+	// Verification
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var written map[string]any
+	require.NoError(t, json.Unmarshal(data, &written))
+	require.Equal(t, "unit-test-runbook", written["runbook"])
+	require.NotContains(t, written, "error")
+
+	commands := written["steps"].([]any)[0].(map[string]any)["commands"].([]any)
+	require.Equal(t, true, commands[0].(map[string]any)["skipped"])
+	require.Equal(t, "already succeeded", commands[0].(map[string]any)["skip_reason"])
+}
+
+func TestThatNilReportDoesNothing(t *testing.T) {
+	// Fixture Setup
+	var report *Report
+
+	// Execute SUT
+	step := report.AddStep("1", "")
+	command := step.AddCommand(0, "epcc get currencies")
+	command.Start()
+	command.Finish(1, 1, nil, nil)
+	step.Finish(nil)
+	report.Finish(nil)
+
+	// Verification
+	require.Nil(t, step)
+	require.Nil(t, command)
+}
@@ -40,3 +40,30 @@ func TestJsonSchemaValidate(t *testing.T) {
 	}
 
 }
+
+func TestJsonSchemaRejectsCallWithRetries(t *testing.T) {
+	// Fixture Setup
+	sch, err := jsonschema.Compile("runbook_schema.json")
+	require.NoError(t, err)
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+description:
+  short: "A runbook"
+actions:
+  setup:
+    commands:
+      - call: unit-test-runbook/other
+        retries: 2
+`
+
+	var v interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(runbookString), &v))
+
+	// Execute SUT
+	err = sch.Validate(v)
+
+	// Verification
+	require.Error(t, err)
+}
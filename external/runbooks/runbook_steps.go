@@ -0,0 +1,252 @@
+package runbooks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"gopkg.in/yaml.v3"
+)
+
+// RunbookStep is a step in a runbook, either just the commands to run (a string) or a map with the commands in run (or another action to call), and when/how to run them
+type RunbookStep struct {
+	// Identifies the step, see IdentifySteps
+	ID string `yaml:"-"`
+
+	// The name of the step used in logs and when showing the runbook
+	Name string `yaml:"name"`
+
+	// The commands to run (concurrently if there is more than one line)
+	Run string `yaml:"run"`
+
+	// Another action to run instead of commands (e.g., currencies/create-currency)
+	Call string `yaml:"call"`
+
+	// The variables for the called action, values are templates rendered with the variables of this action
+	With map[string]string `yaml:"with"`
+
+	// An expression (https://expr-lang.org/) with the variables, the step only runs if it's true
+	When string `yaml:"when"`
+
+	// How many times a command that fails is retried
+	Retries int `yaml:"retries"`
+
+	// How long to wait between retries (e.g., 2s)
+	RetryDelay time.Duration `yaml:"retry_delay"`
+
+	// Overrides ignore_errors of the action for this step
+	IgnoreErrors *bool `yaml:"ignore_errors"`
+
+	// How long to wait for the commands to finish (e.g., 30s), instead of the execution timeout
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Attributes that runbooks validate shouldn't warn about, as the commands send them on purpose even though the resource doesn't have them
+	LintIgnoreAttributes []string `yaml:"lint_ignore_attributes"`
+}
+
+func (s *RunbookStep) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = RunbookStep{Run: node.Value}
+		return nil
+	}
+
+	if node.Kind == yaml.MappingNode {
+		hasRun, hasCall := false, false
+		for i := 0; i < len(node.Content); i += 2 {
+			switch key := node.Content[i].Value; key {
+			case "run":
+				hasRun = true
+			case "call":
+				hasCall = true
+			case "name", "with", "when", "retries", "retry_delay", "ignore_errors", "timeout", "lint_ignore_attributes":
+			default:
+				return fmt.Errorf("line %d: unknown field %s in step, steps can have { name, run, call, with, when, retries, retry_delay, ignore_errors, timeout, lint_ignore_attributes }", node.Content[i].Line, key)
+			}
+		}
+
+		if hasRun == hasCall {
+			return fmt.Errorf("line %d: step must have either a run field with the commands to run, or a call field with an action to call", node.Line)
+		}
+	}
+
+	// A different type, so that this function isn't called again
+	type runbookStep RunbookStep
+
+	var step runbookStep
+	if err := node.Decode(&step); err != nil {
+		return err
+	}
+
+	*s = RunbookStep(step)
+	return nil
+}
+
+// IdentifySteps returns a copy of the steps numbered from 1 after the prefix, steps rendered from a YAML array get the number of the step that rendered them and their position (e.g., 3.2), so that a step has the same ID regardless of how other steps render
+func IdentifySteps(prefix string, steps []RunbookStep) []RunbookStep {
+	identifiedSteps := make([]RunbookStep, len(steps))
+
+	for i, step := range steps {
+		step.ID = prefix + strconv.Itoa(i+1)
+		identifiedSteps[i] = step
+	}
+
+	return identifiedSteps
+}
+
+// ParseRenderedSteps returns the steps if the rendered commands of a step are a YAML array, steps that don't set them use the name, retries, error policy, timeout and ignored lint attributes of the step (calls only use the name and error policy)
+func ParseRenderedSteps(parent RunbookStep, rawCmdLines []string) ([]RunbookStep, bool) {
+	joinedString := strings.Join(rawCmdLines, "\n")
+
+	var renderedSteps []RunbookStep
+	if err := yaml.Unmarshal([]byte(joinedString), &renderedSteps); err != nil {
+		return nil, false
+	}
+
+	for i := range renderedSteps {
+		step := &renderedSteps[i]
+
+		if parent.ID != "" {
+			step.ID = fmt.Sprintf("%s.%d", parent.ID, i+1)
+		}
+
+		if step.Name == "" {
+			step.Name = parent.Name
+		}
+
+		if step.IgnoreErrors == nil {
+			step.IgnoreErrors = parent.IgnoreErrors
+		}
+
+		if step.Call != "" {
+			// The steps of the called action have their own retries and timeouts
+			continue
+		}
+
+		if step.LintIgnoreAttributes == nil {
+			step.LintIgnoreAttributes = parent.LintIgnoreAttributes
+		}
+
+		if step.Retries == 0 {
+			step.Retries = parent.Retries
+		}
+
+		if step.RetryDelay == 0 {
+			step.RetryDelay = parent.RetryDelay
+		}
+
+		if step.Timeout == 0 {
+			step.Timeout = parent.Timeout
+		}
+	}
+
+	return renderedSteps, true
+}
+
+// ShouldIgnoreErrors returns whether the errors in the step should be ignored
+func (s *RunbookStep) ShouldIgnoreErrors(runbookAction *RunbookAction) bool {
+	if s.IgnoreErrors != nil {
+		return *s.IgnoreErrors
+	}
+
+	return runbookAction.IgnoreErrors
+}
+
+// Describe returns a description of the step for logs (e.g., 3.2 (create catalog))
+func (s *RunbookStep) Describe() string {
+	if s.Name == "" {
+		return s.ID
+	}
+
+	return fmt.Sprintf("%s (%s)", s.ID, s.Name)
+}
+
+// ValidateCall returns an error if the step calls an action and sets retries, retry_delay or timeout, which only apply to the steps of the called action
+func (s *RunbookStep) ValidateCall() error {
+	if s.Call != "" && (s.Retries != 0 || s.RetryDelay != 0 || s.Timeout != 0) {
+		return fmt.Errorf("retries, retry_delay and timeout can't be used with call, they can be set on the steps of the called action")
+	}
+
+	return nil
+}
+
+func (s *RunbookStep) validate(variableDefinitions map[string]Variable) error {
+	if s.Call == "" && len(s.With) > 0 {
+		return fmt.Errorf("with can only be used with call")
+	}
+
+	if err := s.ValidateCall(); err != nil {
+		return err
+	}
+
+	if s.Retries < 0 {
+		return fmt.Errorf("retries must not be negative, but is %d", s.Retries)
+	}
+
+	if s.RetryDelay < 0 {
+		return fmt.Errorf("retry_delay must not be negative, but is %s", s.RetryDelay)
+	}
+
+	if s.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, but is %s", s.Timeout)
+	}
+
+	if s.When != "" {
+		// Variables are checked for their type, but they might also be captured by an earlier step
+		env := make(map[string]interface{}, len(variableDefinitions))
+		for key, variableDef := range variableDefinitions {
+			switch {
+			case variableDef.Type == "INT":
+				env[key] = 0
+			case variableDef.Type == "FLOAT":
+				env[key] = 0.0
+			case variableDef.Type == "BOOL":
+				env[key] = false
+			case variableDef.Type == "FILE":
+				env[key] = FileVariable{}
+			case variableDef.Type == "JSON", strings.HasPrefix(variableDef.Type, "LIST:"):
+				// The type depends on the value, so these are left undefined
+			default:
+				env[key] = ""
+			}
+		}
+
+		if _, err := expr.Compile(s.When, expr.Env(env), expr.AllowUndefinedVariables(), expr.AsBool()); err != nil {
+			return fmt.Errorf("invalid when condition %s: %w", s.When, err)
+		}
+	}
+
+	return nil
+}
+
+// EvaluateWhen returns whether the step should run, based on the when condition and the variables (which have the same values as in templates)
+func (s *RunbookStep) EvaluateWhen(stringVars map[string]*string, variableDefinitions map[string]Variable) (bool, error) {
+	if s.When == "" {
+		return true, nil
+	}
+
+	env := make(map[string]interface{}, len(stringVars))
+
+	for key, val := range stringVars {
+		env[key] = *val
+
+		if variableDef, ok := variableDefinitions[key]; ok {
+			if parsedVal, err := ParseVariableValue(variableDef.Type, *val); err == nil {
+				env[key] = parsedVal
+			}
+		}
+	}
+
+	program, err := expr.Compile(s.When, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return false, fmt.Errorf("invalid when condition %s: %w", s.When, err)
+	}
+
+	output, err := expr.Run(program, env)
+	if err != nil {
+		return false, fmt.Errorf("could not evaluate when condition %s: %w", s.When, err)
+	}
+
+	return output.(bool), nil
+}
@@ -0,0 +1,110 @@
+package runbooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestThatStepsCanBeStringsOrMaps(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	stepsString := `
+- epcc get customers
+- name: "create customer"
+  run: epcc create customer --auto-fill
+  when: count > 1
+  retries: 2
+  retry_delay: 500ms
+  ignore_errors: true
+  timeout: 1m
+`
+	var steps []RunbookStep
+
+	// Execute SUT
+	err := yaml.Unmarshal([]byte(stepsString), &steps)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []RunbookStep{
+		{Run: "epcc get customers"},
+		{
+			Name:         "create customer",
+			Run:          "epcc create customer --auto-fill",
+			When:         "count > 1",
+			Retries:      2,
+			RetryDelay:   500 * time.Millisecond,
+			IgnoreErrors: &[]bool{true}[0],
+			Timeout:      time.Minute,
+		},
+	}, steps)
+}
+
+func TestThatEvaluateWhenUsesVariables(t *testing.T) {
+	// Fixture Setup
+	count := "3"
+	country := "US"
+	stringVars := map[string]*string{"count": &count, "country": &country}
+	variableDefinitions := map[string]Variable{"count": {Type: "INT"}, "country": {Type: "STRING"}}
+
+	// Execute SUT
+	numberResult, numberErr := (&RunbookStep{When: "count > 2"}).EvaluateWhen(stringVars, variableDefinitions)
+	stringResult, stringErr := (&RunbookStep{When: `country == "CA"`}).EvaluateWhen(stringVars, variableDefinitions)
+	emptyResult, emptyErr := (&RunbookStep{}).EvaluateWhen(stringVars, variableDefinitions)
+
+	// Verification
+	require.NoError(t, numberErr)
+	require.True(t, numberResult)
+	require.NoError(t, stringErr)
+	require.False(t, stringResult)
+	require.NoError(t, emptyErr)
+	require.True(t, emptyResult)
+}
+
+func TestThatEvaluateWhenWithUnknownVariableFails(t *testing.T) {
+	// Fixture Setup
+	step := &RunbookStep{When: "missing > 2"}
+
+	// Execute SUT
+	_, err := step.EvaluateWhen(map[string]*string{}, map[string]Variable{})
+
+	// Verification
+	require.ErrorContains(t, err, "invalid when condition")
+}
+
+func TestThatParseRenderedStepsInheritsFromTheParent(t *testing.T) {
+	// Fixture Setup
+	parent := RunbookStep{Name: "sleeps", Retries: 2, Timeout: time.Second}
+
+	// Execute SUT
+	steps, ok := ParseRenderedSteps(parent, []string{"- sleep 1", "- run: sleep 2", "  retries: 5"})
+	_, notSteps := ParseRenderedSteps(parent, []string{"epcc get customers"})
+
+	// Verification
+	require.True(t, ok)
+	require.False(t, notSteps)
+	require.Equal(t, []RunbookStep{
+		{Name: "sleeps", Run: "sleep 1", Retries: 2, Timeout: time.Second},
+		{Name: "sleeps", Run: "sleep 2", Retries: 5, Timeout: time.Second},
+	}, steps)
+}
+
+func TestThatStepWithoutRunFails(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	stepsString := `
+- name: "nothing"
+  retries: 2
+`
+	var steps []RunbookStep
+
+	// Execute SUT
+	err := yaml.Unmarshal([]byte(stepsString), &steps)
+
+	// Verification
+	require.ErrorContains(t, err, "step must have either a run field")
+}
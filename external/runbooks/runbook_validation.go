@@ -1,107 +1,221 @@
 package runbooks
 
 import (
+	"context"
 	"fmt"
 	"github.com/buildkite/shellwords"
 	log "github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v3"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 func ValidateRunbook(runbook *Runbook) error {
+	problems, err := LintRunbook(runbook)
+
+	if err != nil {
+		return err
+	}
+
+	errs := make([]string, 0)
+	for _, problem := range problems {
+		if !problem.Warning {
+			errs = append(errs, problem.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// LintRunbook validates a runbook, and then checks each epcc command against the resources, returning the problems found (warnings, and errors that ValidateRunbook fails with)
+func LintRunbook(runbook *Runbook) ([]LintProblem, error) {
 
 	if len(runbook.Name) == 0 {
-		return fmt.Errorf("Runbook has no name")
+		return nil, fmt.Errorf("Runbook has no name")
 	}
 
 	if len(runbook.RunbookActions) == 0 {
-		return fmt.Errorf("number of actions is zero")
+		return nil, fmt.Errorf("number of actions is zero")
+	}
+
+	// Sorted, so that problems are always reported in the same order
+	actionNames := make([]string, 0, len(runbook.RunbookActions))
+	for name := range runbook.RunbookActions {
+		actionNames = append(actionNames, name)
 	}
 
-	for _, runbookAction := range runbook.RunbookActions {
-		if (len(runbookAction.RawCommands)) == 0 {
-			return fmt.Errorf("number of commands in action '%s' is zero", runbookAction.Name)
+	sort.Strings(actionNames)
+
+	linter := newRunbookLinter()
+
+	for _, name := range actionNames {
+		runbookAction := runbook.RunbookActions[name]
+		linter.action = name
+
+		if err := validateRunbookAction(runbook, runbookAction, []string{runbook.Name + "/" + runbookAction.Name}, linter); err != nil {
+			return nil, err
 		}
+	}
 
-		argumentsWithDefaults := CreateMapForRunbookArgumentPointers(runbookAction)
+	return linter.problems(actionNames), nil
+}
 
-		cmds := runbookAction.RawCommands
-		for stepIdx := 0; stepIdx < len(cmds); stepIdx++ {
-			rawCmd := cmds[stepIdx]
+// validateRunbookAction validates an action, and the actions it calls, the call stack has the actions being validated to detect cycles
+func validateRunbookAction(runbook *Runbook, runbookAction *RunbookAction, callStack []string, linter *runbookLinter) error {
+	if (len(runbookAction.Steps)) == 0 {
+		return fmt.Errorf("number of commands in action '%s' is zero", runbookAction.Name)
+	}
 
-			templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %d", runbook.Name, runbookAction.Name, stepIdx+1)
-			rawCmdLines, err := RenderTemplates(templateName, rawCmd, argumentsWithDefaults, runbookAction.Variables)
+	if err := validateSteps(runbook, runbookAction, IdentifySteps("", runbookAction.Steps), callStack, linter); err != nil {
+		return err
+	}
 
-			if err != nil {
-				rawCmdLines := strings.Split(rawCmd, "\n")
+	if err := validateSteps(runbook, runbookAction, IdentifySteps("on_failure-", runbookAction.OnFailure), callStack, linter); err != nil {
+		return err
+	}
+
+	return validateSteps(runbook, runbookAction, IdentifySteps("finally-", runbookAction.Finally), callStack, linter)
+}
+
+func validateSteps(runbook *Runbook, runbookAction *RunbookAction, steps []RunbookStep, callStack []string, linter *runbookLinter) error {
+	argumentsWithDefaults := CreateMapForRunbookArgumentPointers(runbookAction)
 
-				formattedLines := strings.Join(rawCmdLines, "\n>  ")
+	// Required variables without a default are set when the runbook is run, so use a valid value of their type instead of ""
+	for key, variable := range runbookAction.Variables {
+		if variable.Required && *argumentsWithDefaults[key] == "" {
+			placeholder := placeholderVariableValue(variable.Type)
+			argumentsWithDefaults[key] = &placeholder
+		}
+	}
+
+	for stepIdx := 0; stepIdx < len(steps); stepIdx++ {
+		step := steps[stepIdx]
+		rawCmd := step.Run
+
+		if err := step.validate(runbookAction.Variables); err != nil {
+			return fmt.Errorf("error in step %s of action '%s': %w", step.Describe(), runbookAction.Name, err)
+		}
 
-				return fmt.Errorf("error rendering template: %w, command:\n> %s", err, formattedLines)
+		if step.Call != "" {
+			if err := validateCall(runbook, step, argumentsWithDefaults, runbookAction.Variables, callStack, linter); err != nil {
+				return fmt.Errorf("error in step %s of action '%s': %w", step.Describe(), runbookAction.Name, err)
 			}
 
-			joinedString := strings.Join(rawCmdLines, "\n")
-			renderedCmd := []string{}
-			err = yaml.Unmarshal([]byte(joinedString), &renderedCmd)
-
-			if err == nil {
-				log.Tracef("Line %d is a Yaml array %s, inserting into stack", stepIdx, joinedString)
-				newCmds := make([]string, 0, len(cmds)+len(renderedCmd)-1)
-				newCmds = append(newCmds, cmds[0:stepIdx]...)
-				newCmds = append(newCmds, renderedCmd...)
-				newCmds = append(newCmds, cmds[stepIdx+1:]...)
-				cmds = newCmds
-				stepIdx--
+			continue
+		}
+
+		templateName := fmt.Sprintf("Runbook: %s Action: %s Step: %s", runbook.Name, runbookAction.Name, step.ID)
+		rawCmdLines, err := RenderTemplates(context.Background(), templateName, rawCmd, argumentsWithDefaults, runbookAction.Variables)
+
+		if err != nil {
+			rawCmdLines := strings.Split(rawCmd, "\n")
+
+			formattedLines := strings.Join(rawCmdLines, "\n>  ")
+
+			return fmt.Errorf("error rendering template: %w, command:\n> %s", err, formattedLines)
+		}
+
+		if renderedSteps, ok := ParseRenderedSteps(step, rawCmdLines); ok {
+			log.Tracef("Step %s is a Yaml array %s, inserting into stack", step.ID, strings.Join(rawCmdLines, "\n"))
+			newSteps := make([]RunbookStep, 0, len(steps)+len(renderedSteps)-1)
+			newSteps = append(newSteps, steps[0:stepIdx]...)
+			newSteps = append(newSteps, renderedSteps...)
+			newSteps = append(newSteps, steps[stepIdx+1:]...)
+			steps = newSteps
+			stepIdx--
+			continue
+		}
+
+		for commandIdx, rawCmdLine := range rawCmdLines {
+			rawCmdLine := strings.Trim(rawCmdLine, " \n")
+
+			if rawCmdLine == "" {
+				// Allow blank lines
 				continue
 			}
 
-			for commandIdx, rawCmdLine := range rawCmdLines {
-				rawCmdLine := strings.Trim(rawCmdLine, " \n")
+			rawCmdArguments, err := shellwords.SplitPosix(strings.Trim(rawCmdLine, " \n"))
 
-				if rawCmdLine == "" {
-					// Allow blank lines
-					continue
-				}
+			if err != nil {
+				return fmt.Errorf("error processing line at step %s line %d, %v", step.ID, commandIdx+1, err)
+			}
 
-				rawCmdArguments, err := shellwords.SplitPosix(strings.Trim(rawCmdLine, " \n"))
+			rawCmdArguments, captures, err := ExtractCaptures(rawCmdArguments)
 
-				if err != nil {
-					return fmt.Errorf("error processing line at step %d line %d, %v", stepIdx+1, commandIdx+1, err)
+			if err != nil {
+				return fmt.Errorf("error processing line at step %s line %d, %v", step.ID, commandIdx+1, err)
+			}
+
+			if len(rawCmdArguments) < 1 {
+				return fmt.Errorf("Each command should must have atleast one argument, but the line at step %s line %d does not:\n\t%s", step.ID, commandIdx+1, rawCmdLine)
+			}
+
+			if rawCmdArguments[0] == "epcc" {
+				if len(rawCmdArguments) < 2 {
+					return fmt.Errorf("Each epcc command should be followed by a verb but the line at step %s line %d following line does not:\n\t%s", step.ID, commandIdx+1, rawCmdLine)
 				}
 
-				if len(rawCmdArguments) < 1 {
-					return fmt.Errorf("Each command should must have atleast one argument, but the line at step %d line %d does not:\n\t%s", stepIdx+1, commandIdx+1, rawCmdLine)
+				switch rawCmdArguments[1] {
+				case "get":
+				case "delete":
+				case "delete-all":
+				case "create":
+				case "update":
+				case "assert":
+				default:
+					return fmt.Errorf("Each command needs to have a valid verb of { get, create, update, delete, delete-all, assert }, but we got %s in step %s line: %d", rawCmdArguments[1], step.ID, commandIdx+1)
 				}
 
-				if rawCmdArguments[0] == "epcc" {
-					if len(rawCmdArguments) < 2 {
-						return fmt.Errorf("Each epcc command should be followed by a verb but the line at step %d line %d following line does not:\n\t%s", stepIdx+1, commandIdx+1, rawCmdLine)
-					}
-
-					switch rawCmdArguments[1] {
-					case "get":
-					case "delete":
-					case "delete-all":
-					case "create":
-					case "update":
-					default:
-						return fmt.Errorf("Each command needs to have a valid verb of { get, create, update, delete, delete-all }, but we got %s in step %d line: %d", rawCmdArguments[1], stepIdx+1, commandIdx+1)
-					}
-				} else if rawCmdArguments[0] == "sleep" {
-					_, err := strconv.Atoi(rawCmdArguments[1])
-					if err != nil {
-						return fmt.Errorf("Invalid argument to sleep %v, must be an integer in step %d line %d", rawCmdArguments[1], stepIdx+1, commandIdx+1)
-					}
-				} else {
-					return fmt.Errorf("Each command needs be a recognized command, either { epcc, sleep }, but the line in step %d line %d is not:\n\t%s", stepIdx+1, commandIdx+1, rawCmdArguments[0])
+				if len(captures) > 0 && (rawCmdArguments[1] == "delete-all" || rawCmdArguments[1] == "assert") {
+					return fmt.Errorf("Values can only be captured from get, create, update and delete commands, but we got %s in step %s line: %d", rawCmdArguments[1], step.ID, commandIdx+1)
 				}
-			}
 
+				linter.addCommand(runbook.Name, runbookAction.Name, step, commandIdx+1, rawCmdArguments[1:])
+			} else if len(captures) > 0 {
+				return fmt.Errorf("Values can only be captured from epcc commands, but the line in step %s line %d is not:\n\t%s", step.ID, commandIdx+1, rawCmdLine)
+			} else if rawCmdArguments[0] == "sleep" {
+				_, err := strconv.Atoi(rawCmdArguments[1])
+				if err != nil {
+					return fmt.Errorf("Invalid argument to sleep %v, must be an integer in step %s line %d", rawCmdArguments[1], step.ID, commandIdx+1)
+				}
+			} else {
+				return fmt.Errorf("Each command needs be a recognized command, either { epcc, sleep }, but the line in step %s line %d is not:\n\t%s", step.ID, commandIdx+1, rawCmdArguments[0])
+			}
 		}
 
 	}
 
 	return nil
+}
+
+func validateCall(runbook *Runbook, step RunbookStep, stringVars map[string]*string, variableDefinitions map[string]Variable, callStack []string, linter *runbookLinter) error {
+	calledRunbook, calledAction, err := ResolveCall(runbook, step.Call)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := RenderCallArguments(context.Background(), step, calledAction, stringVars, variableDefinitions); err != nil {
+		return err
+	}
+
+	calledName := calledRunbook.Name + "/" + calledAction.Name
+	for _, name := range callStack {
+		if name == calledName {
+			return fmt.Errorf("call cycle detected: %s -> %s", strings.Join(callStack, " -> "), calledName)
+		}
+	}
 
+	// A new slice, so that the call stacks of different steps don't share an array
+	calledStack := append(append(make([]string, 0, len(callStack)+1), callStack...), calledName)
+
+	if err := validateRunbookAction(calledRunbook, calledAction, calledStack, linter); err != nil {
+		return fmt.Errorf("error in called action %s: %w", calledName, err)
+	}
+
+	return nil
 }
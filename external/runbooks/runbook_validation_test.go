@@ -2,6 +2,7 @@ package runbooks
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -533,3 +534,554 @@ func TestThatInitializeBuiltInRunbooksActuallyValidate(t *testing.T) {
 	// Verification
 	require.GreaterOrEqual(t, len(runbookNames), 1, "Expected that some runbooks should be loaded.")
 }
+
+func TestThatRunbookWithCaptureValidates(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - epcc create customer --auto-fill --capture customer_email='.data.email'
+      - epcc get customers filter eq(email,{{ .customer_email }})
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatRunbookWithInvalidCaptureQueryFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - epcc create customer --auto-fill --capture customer_email='.data.email | '
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "could not parse jq query")
+}
+
+func TestThatRunbookWithCaptureOnSleepFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - sleep 1 --capture x=.
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "Values can only be captured from epcc commands")
+}
+
+func TestThatRunbookWithStructuredStepsValidates(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    variables:
+      count:
+        type: INT
+        default: 2
+        description:
+          short: "The count"
+    commands:
+      - epcc create customer --auto-fill
+      - name: "create another customer"
+        run: epcc create customer --auto-fill
+        when: count > 1
+        retries: 3
+        retry_delay: 2s
+        ignore_errors: true
+        timeout: 30s
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatRunbookWithInvalidWhenConditionFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - run: epcc create customer --auto-fill
+        when: count >
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "invalid when condition")
+}
+
+func TestThatRunbookWithNegativeRetriesFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - run: epcc create customer --auto-fill
+        retries: -1
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "retries must not be negative")
+}
+
+func TestThatRunbookWithUnknownStepFieldFailsToLoad(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    commands:
+      - run: epcc create customer --auto-fill
+        retry: 3
+`
+
+	// Execute SUT
+	_, err := loadRunbookFromString(runbookString)
+
+	// Verification
+	require.ErrorContains(t, err, "unknown field retry in step")
+}
+
+func TestThatRunbookWithCallValidates(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  create-currency:
+    variables:
+      code:
+        type: STRING
+        default: "GBP"
+        required: true
+        description:
+          short: "The currency code"
+      decimal_places:
+        type: INT
+        default: 2
+        description:
+          short: "The number of decimal places"
+    commands:
+      - epcc create currency code "{{ .code }}" decimal_places {{ .decimal_places }} --auto-fill
+  setup:
+    variables:
+      currency:
+        type: STRING
+        default: "EUR"
+        description:
+          short: "The currency"
+    commands:
+      - call: unit-test-runbook/create-currency
+        with:
+          code: "{{ .currency }}"
+          decimal_places: 0
+      - epcc get currencies
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatRunbookWithCallToUnknownActionFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  setup:
+    commands:
+      - call: unit-test-runbook/does-not-exist
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "could not find action does-not-exist in runbook unit-test-runbook")
+}
+
+func TestThatRunbookWithCallMissingRequiredVariableFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  create-currency:
+    variables:
+      code:
+        type: STRING
+        default: "GBP"
+        required: true
+        description:
+          short: "The currency code"
+    commands:
+      - epcc create currency code "{{ .code }}" --auto-fill
+  setup:
+    commands:
+      - call: unit-test-runbook/create-currency
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "required variable code of unit-test-runbook/create-currency is not set in with")
+}
+
+func TestThatRunbookWithCallWithUnknownVariableFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  create-currency:
+    commands:
+      - epcc create currency --auto-fill
+  setup:
+    commands:
+      - call: unit-test-runbook/create-currency
+        with:
+          code: GBP
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "code is not a variable of unit-test-runbook/create-currency")
+}
+
+func TestThatRunbookWithCallCycleFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  first:
+    commands:
+      - epcc get currencies
+      - call: unit-test-runbook/second
+  second:
+    commands:
+      - call: unit-test-runbook/first
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "call cycle detected")
+}
+
+func TestThatRunbookWithCallAndRetriesFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  create-currency:
+    commands:
+      - epcc create currency --auto-fill
+  setup:
+    commands:
+      - call: unit-test-runbook/create-currency
+        retries: 2
+        timeout: 5s
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "retries, retry_delay and timeout can't be used with call")
+}
+
+func TestThatRenderedCallStepWithTimeoutFailsValidateCall(t *testing.T) {
+	// Fixture Setup
+	steps, ok := ParseRenderedSteps(RunbookStep{ID: "1", Timeout: time.Minute}, []string{"- call: unit-test-runbook/create-currency", "  timeout: 5s"})
+	require.True(t, ok)
+
+	// Execute SUT
+	err := steps[0].ValidateCall()
+
+	// Verification
+	require.ErrorContains(t, err, "retries, retry_delay and timeout can't be used with call")
+}
+
+func TestThatRunbookWithRunAndCallInTheSameStepFailsToLoad(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  setup:
+    commands:
+      - run: epcc get currencies
+        call: unit-test-runbook/other
+`
+
+	// Execute SUT
+	_, err := loadRunbookFromString(runbookString)
+
+	// Verification
+	require.ErrorContains(t, err, "step must have either a run field")
+}
+
+func TestThatRunbookWithOnFailureAndFinallyStepsPassesValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  setup:
+    commands:
+      - epcc create currency code USD
+    on_failure:
+      - epcc get currencies
+    finally:
+      - name: delete currency
+        run: epcc delete currency code=USD
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+	require.Len(t, runbook.RunbookActions["setup"].OnFailure, 1)
+	require.Len(t, runbook.RunbookActions["setup"].Finally, 1)
+}
+
+func TestThatRunbookWithInvalidFinallyStepFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  setup:
+    commands:
+      - epcc get currencies
+    finally:
+      - epcc get currencies
+      - epcc fetch currencies
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "step finally-2")
+}
+
+func TestThatRunbookWithAssertCommandPassesValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  check:
+    commands:
+      - epcc get currency code=USD --capture currency='.'
+      - epcc assert --from-capture currency --jq '.data.code == "USD"'
+      - epcc assert currencies --expr 'len(data) > 0'
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+}
+
+func TestThatRunbookWithCaptureOnAssertCommandFailsValidation(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  check:
+    commands:
+      - epcc assert currencies --expr 'len(data) > 0' --capture count='.data | length'
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.ErrorContains(t, err, "Values can only be captured from get, create, update and delete commands")
+}
+
+func TestThatRunbookWithRequiredTypedVariablesWithoutDefaultsValidates(t *testing.T) {
+	// Fixture Setup
+
+	// language=yaml
+	runbookString := `
+name: unit-test-runbook
+docs: "http://localhost"
+actions:
+  test-action:
+    variables:
+      count:
+        type: INT
+        required: true
+      amount:
+        type: FLOAT
+        required: true
+      enabled:
+        type: BOOL
+        required: true
+      currency:
+        type: CURRENCY
+        required: true
+      start:
+        type: DATE
+        required: true
+      wait:
+        type: DURATION
+        required: true
+      meta:
+        type: JSON
+        required: true
+      mode:
+        type: ENUM:fast,slow
+        required: true
+      skus:
+        type: LIST:STRING
+        required: true
+    commands:
+      - epcc get currencies filter eq(code,{{ .currency }}) page[limit] {{ .count }}
+      - epcc get customers filter eq(name,{{ .amount }}-{{ .enabled }}-{{ .start }}-{{ .wait }}-{{ .mode }}-{{ len .meta }}-{{ len .skus }})
+`
+
+	runbook, err := loadRunbookFromString(runbookString)
+	require.NoError(t, err, "Error should be nil")
+
+	// Execute SUT
+	err = ValidateRunbook(runbook)
+
+	// Verification
+	require.NoError(t, err)
+}
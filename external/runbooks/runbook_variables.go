@@ -0,0 +1,207 @@
+package runbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileVariable is the value of a FILE variable in templates, it renders as the path and the content of the file is .Content
+type FileVariable struct {
+	Path    string
+	Content string
+}
+
+func (f FileVariable) String() string {
+	return f.Path
+}
+
+var currencyRegex = regexp.MustCompile("^[A-Z]{3}$")
+
+// ParseVariableValue returns the value of a variable as it is used in templates and when conditions,
+// or an error if the value isn't valid for the type of the variable
+func ParseVariableValue(variableType string, value string) (interface{}, error) {
+	switch {
+	case variableType == "STRING", strings.HasPrefix(variableType, "RESOURCE_ID:"):
+		return value, nil
+	case variableType == "INT":
+		parsedVal, err := strconv.Atoi(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("value %s is not an integer", value)
+		}
+
+		return parsedVal, nil
+	case variableType == "FLOAT":
+		parsedVal, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("value %s is not a number", value)
+		}
+
+		return parsedVal, nil
+	case variableType == "BOOL":
+		parsedVal, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("value %s is not a boolean, it should be true or false", value)
+		}
+
+		return parsedVal, nil
+	case variableType == "CURRENCY":
+		if !currencyRegex.MatchString(value) {
+			return nil, fmt.Errorf("value %s is not a three letter currency code (e.g., USD)", value)
+		}
+
+		return value, nil
+	case variableType == "DATE":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("value %s is not a date, it should be YYYY-MM-DD or RFC 3339 (e.g., 2024-01-31 or 2024-01-31T12:00:00Z)", value)
+			}
+		}
+
+		return value, nil
+	case variableType == "DURATION":
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("value %s is not a duration (e.g., 90s, 5m or 1h30m)", value)
+		}
+
+		return value, nil
+	case variableType == "JSON":
+		var parsedVal interface{}
+
+		if err := json.Unmarshal([]byte(value), &parsedVal); err != nil {
+			return nil, fmt.Errorf("value %s is not valid JSON: %w", value, err)
+		}
+
+		return parsedVal, nil
+	case variableType == "FILE":
+		// An empty value means no file was given
+		if value == "" {
+			return FileVariable{}, nil
+		}
+
+		content, err := os.ReadFile(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %s: %w", value, err)
+		}
+
+		return FileVariable{Path: value, Content: string(content)}, nil
+	case strings.HasPrefix(variableType, "ENUM:"):
+		enumValues := strings.Split(variableType[5:], ",")
+
+		for _, enumVal := range enumValues {
+			if value == enumVal {
+				return value, nil
+			}
+		}
+
+		return nil, fmt.Errorf("value %q is not a valid enum option. Valid options are: [%s]", value, strings.Join(enumValues, ", "))
+	case strings.HasPrefix(variableType, "LIST:"):
+		elementType := variableType[5:]
+
+		if strings.HasPrefix(elementType, "LIST:") {
+			return nil, fmt.Errorf("lists of lists are not supported [%s]", variableType)
+		}
+
+		elements, err := SplitListValue(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsedVal := make([]interface{}, 0, len(elements))
+
+		for idx, element := range elements {
+			parsedElement, err := ParseVariableValue(elementType, element)
+
+			if err != nil {
+				return nil, fmt.Errorf("element %d of list is invalid: %w", idx, err)
+			}
+
+			parsedVal = append(parsedVal, parsedElement)
+		}
+
+		return parsedVal, nil
+	default:
+		return nil, fmt.Errorf("unknown type [%s] specified in template", variableType)
+	}
+}
+
+// placeholderVariableValue returns a valid value for a variable type, used when validating runbooks with required variables that have no default
+func placeholderVariableValue(variableType string) string {
+	switch {
+	case variableType == "INT", variableType == "FLOAT":
+		return "1"
+	case variableType == "BOOL":
+		return "false"
+	case variableType == "CURRENCY":
+		return "USD"
+	case variableType == "DATE":
+		return "2024-01-31"
+	case variableType == "DURATION":
+		return "1s"
+	case variableType == "JSON":
+		return "{}"
+	case strings.HasPrefix(variableType, "ENUM:"):
+		return strings.Split(variableType[5:], ",")[0]
+	default:
+		return ""
+	}
+}
+
+// SplitListValue returns the elements of the value of a LIST variable, which is either a JSON array (e.g., ["a","b"]) or comma separated (e.g., a,b)
+func SplitListValue(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(value, "[") {
+		return strings.Split(value, ","), nil
+	}
+
+	var elements []interface{}
+
+	if err := json.Unmarshal([]byte(value), &elements); err != nil {
+		return nil, fmt.Errorf("value %s is not a JSON array or comma separated list: %w", value, err)
+	}
+
+	result := make([]string, 0, len(elements))
+
+	for _, element := range elements {
+		if s, ok := element.(string); ok {
+			result = append(result, s)
+		} else {
+			// Numbers, booleans and objects are kept as JSON, so that they can be parsed by the element type
+			b, err := json.Marshal(element)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, string(b))
+		}
+	}
+
+	return result, nil
+}
+
+// JoinListValue returns the value of a LIST variable with the given elements, as a JSON array
+func JoinListValue(elements []string) string {
+	b, err := json.Marshal(elements)
+
+	if err != nil {
+		// Marshalling strings can't fail
+		return ""
+	}
+
+	return string(b)
+}
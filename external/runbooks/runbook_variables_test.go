@@ -0,0 +1,107 @@
+package runbooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThatParseVariableValueConvertsValuesToTheirType(t *testing.T) {
+	// Fixture Setup
+	path := filepath.Join(t.TempDir(), "description.txt")
+	require.NoError(t, os.WriteFile(path, []byte("A fine product"), 0600))
+
+	// Execute SUT
+	boolVal, boolErr := ParseVariableValue("BOOL", "true")
+	floatVal, floatErr := ParseVariableValue("FLOAT", "1.5")
+	jsonVal, jsonErr := ParseVariableValue("JSON", `{"name":"Ron"}`)
+	listVal, listErr := ParseVariableValue("LIST:INT", `["1","2"]`)
+	commaListVal, commaListErr := ParseVariableValue("LIST:CURRENCY", "USD,CAD")
+	fileVal, fileErr := ParseVariableValue("FILE", path)
+
+	// Verification
+	require.NoError(t, boolErr)
+	require.Equal(t, true, boolVal)
+	require.NoError(t, floatErr)
+	require.Equal(t, 1.5, floatVal)
+	require.NoError(t, jsonErr)
+	require.Equal(t, map[string]interface{}{"name": "Ron"}, jsonVal)
+	require.NoError(t, listErr)
+	require.Equal(t, []interface{}{1, 2}, listVal)
+	require.NoError(t, commaListErr)
+	require.Equal(t, []interface{}{"USD", "CAD"}, commaListVal)
+	require.NoError(t, fileErr)
+	require.Equal(t, FileVariable{Path: path, Content: "A fine product"}, fileVal)
+}
+
+func TestThatParseVariableValueRejectsInvalidValues(t *testing.T) {
+	// Fixture Setup
+	invalidValues := map[string]string{
+		"BOOL":         "maybe",
+		"FLOAT":        "1.5.2",
+		"CURRENCY":     "dollars",
+		"DATE":         "31/01/2024",
+		"DURATION":     "5 minutes",
+		"JSON":         "{",
+		"FILE":         filepath.Join(t.TempDir(), "missing.txt"),
+		"LIST:INT":     "1,two",
+		"ENUM:yes,no":  "maybe",
+		"LIST:LIST:ID": "1",
+	}
+
+	for variableType, value := range invalidValues {
+		// Execute SUT
+		_, err := ParseVariableValue(variableType, value)
+
+		// Verification
+		require.Error(t, err, "%s should not accept %s", variableType, value)
+	}
+}
+
+func TestThatListVariablesAreSlicesInTemplates(t *testing.T) {
+	// Fixture Setup
+	skus := JoinListValue([]string{"shirt", "hat"})
+	stringVars := map[string]*string{"skus": &skus}
+	variableDefinitions := map[string]Variable{"skus": {Type: "LIST:STRING"}}
+
+	// Execute SUT
+	lines, err := RenderTemplates(context.Background(), "test", `{{ range .skus }}epcc create pcm-product sku {{ . }}
+{{ end }}`, stringVars, variableDefinitions)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{"epcc create pcm-product sku shirt", "epcc create pcm-product sku hat", ""}, lines)
+}
+
+func TestThatFileVariablesRenderAsThePathAndHaveTheContent(t *testing.T) {
+	// Fixture Setup
+	path := filepath.Join(t.TempDir(), "description.txt")
+	require.NoError(t, os.WriteFile(path, []byte("A fine product"), 0600))
+	stringVars := map[string]*string{"description": &path}
+	variableDefinitions := map[string]Variable{"description": {Type: "FILE"}}
+
+	// Execute SUT
+	lines, err := RenderTemplates(context.Background(), "test", `{{ .description }}: {{ .description.Content }}`, stringVars, variableDefinitions)
+
+	// Verification
+	require.NoError(t, err)
+	require.Equal(t, []string{path + ": A fine product"}, lines)
+}
+
+func TestThatEvaluateWhenUsesBoolAndListVariables(t *testing.T) {
+	// Fixture Setup
+	enabled := "true"
+	currencies := "USD,CAD"
+	stringVars := map[string]*string{"enabled": &enabled, "currencies": &currencies}
+	variableDefinitions := map[string]Variable{"enabled": {Type: "BOOL"}, "currencies": {Type: "LIST:CURRENCY"}}
+
+	// Execute SUT
+	result, err := (&RunbookStep{When: `enabled && "CAD" in currencies`}).EvaluateWhen(stringVars, variableDefinitions)
+
+	// Verification
+	require.NoError(t, err)
+	require.True(t, result)
+}
@@ -35,8 +35,14 @@ type Runbook struct {
 type RunbookAction struct {
 	Name         string
 	Description  *RunbookDescription
-	RawCommands  []string `yaml:"commands"`
-	IgnoreErrors bool     `yaml:"ignore_errors"`
+	Steps        []RunbookStep `yaml:"commands"`
+	IgnoreErrors bool          `yaml:"ignore_errors"`
+
+	// Steps that run after the commands if they fail (or the runbook is interrupted), before finally
+	OnFailure []RunbookStep `yaml:"on_failure"`
+
+	// Steps that always run after the commands (e.g., to clean up), even if they fail or the runbook is interrupted
+	Finally []RunbookStep `yaml:"finally"`
 
 	Variables map[string]Variable `yaml:"variables"`
 }
@@ -0,0 +1,20 @@
+package shutdown
+
+import "context"
+
+type cleanupKey struct{}
+
+// WithCleanup returns a context for cleaning up (e.g., the finally commands of a runbook), requests are still made with it once the program is shutting down
+func WithCleanup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cleanupKey{}, true)
+}
+
+// IsCleanup returns whether the context is for cleaning up
+func IsCleanup(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	cleanup, _ := ctx.Value(cleanupKey{}).(bool)
+	return cleanup
+}
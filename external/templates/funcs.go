@@ -3,18 +3,22 @@ package templates
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/elasticpath/epcc-cli/external/faker"
+	"github.com/elasticpath/epcc-cli/external/random"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
 )
 
-var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+// streamFuncs are the template functions that use random values, for a specific stream
+type streamFuncs struct {
+	s *random.Stream
+}
 
 // toFloat64 converts 64-bit floats
 func toFloat64(v any) float64 {
@@ -32,66 +36,128 @@ func toInt64(v any) int64 {
 
 // RandString is the internal function that generates a random string. It takes the length of the string and a string of allowed characters as parameters.
 func RandString(letters string, n int) string {
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letters[r.Intn(len(letters))]
-	}
-	return string(b)
+	return streamFuncs{random.Default()}.RandString(letters, n)
 }
 
 // RandAlphaNum generates a string consisting of characters in the range 0-9, a-z, and A-Z.
 func RandAlphaNum(n int) string {
-	const letters = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	return RandString(letters, n)
+	return streamFuncs{random.Default()}.RandAlphaNum(n)
 }
 
 // RandAlpha generates a string consisting of characters in the range a-z and A-Z.
 func RandAlpha(n int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	return RandString(letters, n)
+	return streamFuncs{random.Default()}.RandAlpha(n)
 }
 
 // RandNumeric generates a string consisting of characters in the range 0-9.
 func RandNumeric(n int) string {
-	const digits = "0123456789"
-	return RandString(digits, n)
+	return streamFuncs{random.Default()}.RandNumeric(n)
 }
 
 // RandInt returns a value between the min (inclusive) and max (exclusive)
 func RandInt(minA, maxA any) int {
+	return streamFuncs{random.Default()}.RandInt(minA, maxA)
+}
+
+// RandNorm returns a normal sample with set mean and std deviation.
+func RandNorm(meanA any, stdDevA any) float64 {
+	return streamFuncs{random.Default()}.RandNorm(meanA, stdDevA)
+}
+
+func Fake(string string) string {
+	return streamFuncs{random.Default()}.Fake(string)
+}
+
+// FakeLocale returns fake data for a locale (e.g., fakeLocale "fr_FR" "Name")
+func FakeLocale(locale string, name string) string {
+	return streamFuncs{random.Default()}.FakeLocale(locale, name)
+}
+
+func Seed(x any) string {
+	return streamFuncs{random.Default()}.Seed(x)
+}
+
+// NRandInt returns n unique values between the min (inclusive) and max (exclusive)
+func NRandInt(nAny, minAny, maxAny any) []int {
+	return streamFuncs{random.Default()}.NRandInt(nAny, minAny, maxAny)
+}
+
+func WeightedDateTimeSampler(start string, end string) string {
+	return streamFuncs{random.Default()}.WeightedDateTimeSampler(start, end)
+}
+
+func (f streamFuncs) RandString(letters string, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[f.s.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func (f streamFuncs) RandAlphaNum(n int) string {
+	const letters = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	return f.RandString(letters, n)
+}
+
+func (f streamFuncs) RandAlpha(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	return f.RandString(letters, n)
+}
+
+func (f streamFuncs) RandNumeric(n int) string {
+	const digits = "0123456789"
+	return f.RandString(digits, n)
+}
+
+// RandAscii generates a string consisting of printable ASCII characters (like sprig's randAscii)
+func (f streamFuncs) RandAscii(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(' ' + f.s.Intn('~'-' '+1))
+	}
+	return string(b)
+}
+
+func (f streamFuncs) RandInt(minA, maxA any) int {
 
 	minN := toInt(minA)
 	maxN := toInt(maxA)
 
-	return r.Intn(maxN-minN) + minN
+	return f.s.Intn(maxN-minN) + minN
 }
 
-// RandNorm returns a normal sample with set mean and std deviation.
-func RandNorm(meanA any, stdDevA any) float64 {
+func (f streamFuncs) RandNorm(meanA any, stdDevA any) float64 {
 	mean := toFloat64(meanA)
 	stdDev := toFloat64(stdDevA)
 
-	return r.NormFloat64()*stdDev + mean
+	return f.s.NormFloat64()*stdDev + mean
 }
 
-var mutex sync.Mutex
+func (f streamFuncs) Fake(string string) string {
+	return faker.CallFakeFuncFrom(f.s, string)
+}
 
-var sampler = make(map[string][]time.Time)
+func (f streamFuncs) FakeLocale(locale string, name string) string {
+	return faker.CallLocaleFakeFuncFrom(f.s, locale, name)
+}
 
-func Fake(string string) string {
-	return faker.CallFakeFunc(string)
+func (f streamFuncs) Seed(x any) string {
+	f.s.Reseed(toInt64(x))
+	return ""
 }
 
-func Seed(x any) string {
-	n := toInt64(x)
+// UUIDv4 generates a random UUID (like sprig's uuidv4)
+func (f streamFuncs) UUIDv4() string {
+	id, err := uuid.NewRandomFromReader(f.s)
+	if err != nil {
+		log.Warnf("Could not generate uuid: %v", err)
+		return ""
+	}
 
-	faker.Seed(n)
-	r = rand.New(rand.NewSource(n))
-	return ""
+	return id.String()
 }
 
-// NRandInt returns n unique values between the min (inclusive) and max (exclusive)
-func NRandInt(nAny, minAny, maxAny any) []int {
+func (f streamFuncs) NRandInt(nAny, minAny, maxAny any) []int {
 
 	n := toInt(nAny)
 	minInt := toInt(minAny)
@@ -106,7 +172,7 @@ func NRandInt(nAny, minAny, maxAny any) []int {
 	// If the range is small enough, lets just generate a permutation
 	if n < 2048 && (maxInt-minInt) < 4096 {
 		nRange := maxInt - minInt
-		perm := r.Perm(nRange)
+		perm := f.s.Perm(nRange)
 
 		v := make([]int, n)
 
@@ -120,7 +186,7 @@ func NRandInt(nAny, minAny, maxAny any) []int {
 		v := map[int]struct{}{}
 
 		for len(v) < n {
-			v[RandInt(minInt, maxInt)] = struct{}{}
+			v[f.RandInt(minInt, maxInt)] = struct{}{}
 		}
 
 		results := make([]int, 0, n)
@@ -133,6 +199,10 @@ func NRandInt(nAny, minAny, maxAny any) []int {
 	}
 }
 
+var mutex sync.Mutex
+
+var sampler = make(map[string][]time.Time)
+
 type CurrencyConfig struct {
 	DecimalPlaces     int
 	DecimalPoint      string
@@ -211,16 +281,16 @@ func formatNumber(value float64, precision int, decimalPoint string, thousandSep
 	return formatted
 }
 
-func WeightedDateTimeSampler(start string, end string) string {
+func (f streamFuncs) WeightedDateTimeSampler(start string, end string) string {
 
 	key := fmt.Sprintf("%s_%s", start, end)
 	mutex.Lock()
 	defer mutex.Unlock()
 
 	if computedTable, ok := sampler[key]; ok {
-		t := computedTable[r.Intn(len(computedTable))]
+		t := computedTable[f.s.Intn(len(computedTable))]
 
-		t = t.Add(time.Duration(RandInt(0, 3600)) * time.Second)
+		t = t.Add(time.Duration(f.RandInt(0, 3600)) * time.Second)
 		return t.Format(time.RFC3339)
 	}
 
@@ -276,8 +346,8 @@ func WeightedDateTimeSampler(start string, end string) string {
 
 	sampler[key] = lookup
 
-	t := lookup[r.Intn(len(lookup))]
-	t = t.Add(time.Duration(RandInt(0, 3600)) * time.Second)
+	t := lookup[f.s.Intn(len(lookup))]
+	t = t.Add(time.Duration(f.RandInt(0, 3600)) * time.Second)
 	return t.Format(time.RFC3339)
 }
 
@@ -2,35 +2,62 @@ package templates
 
 import (
 	"bytes"
+	"context"
 	"math"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/elasticpath/epcc-cli/config"
+	"github.com/elasticpath/epcc-cli/external/random"
 	log "github.com/sirupsen/logrus"
 )
 
-func init() {
+// FuncMap returns the functions available in templates (sprig's and ours), the random ones use the stream in the context.
+func FuncMap(ctx context.Context) template.FuncMap {
+	f := streamFuncs{random.FromContext(ctx)}
 
-}
+	funcs := sprig.FuncMap()
+
+	if _, seeded := random.GetSeed(); seeded {
+		// sprig's random functions can't be seeded, so we replace them with ones that can be
+		funcs["randAlphaNum"] = f.RandAlphaNum
+		funcs["randAlpha"] = f.RandAlpha
+		funcs["randNumeric"] = f.RandNumeric
+		funcs["randAscii"] = f.RandAscii
+		funcs["randInt"] = f.RandInt
+		funcs["uuidv4"] = f.UUIDv4
+	}
+
+	addlFuncs := map[string]any{
+		"pow":                   func(a, b int) int { return int(math.Pow(float64(a), float64(b))) },
+		"pseudoRandAlphaNum":    f.RandAlphaNum,
+		"pseudoRandAlpha":       f.RandAlpha,
+		"pseudoRandNumeric":     f.RandNumeric,
+		"pseudoRandString":      f.RandString,
+		"pseudoRandInt":         f.RandInt,
+		"pseudoRandNorm":        f.RandNorm,
+		"weightDatedTimeSample": f.WeightedDateTimeSampler,
+		"nRandInt":              f.NRandInt,
+		"fake":                  f.Fake,
+		"fakeLocale":            f.FakeLocale,
+		"seed":                  f.Seed,
+		"formatPrice":           FormatPrice,
+	}
 
-var AddlFuncs = map[string]any{
-	"pow":                   func(a, b int) int { return int(math.Pow(float64(a), float64(b))) },
-	"pseudoRandAlphaNum":    RandAlphaNum,
-	"pseudoRandAlpha":       RandAlpha,
-	"pseudoRandNumeric":     RandNumeric,
-	"pseudoRandString":      RandString,
-	"pseudoRandInt":         RandInt,
-	"pseudoRandNorm":        RandNorm,
-	"weightDatedTimeSample": WeightedDateTimeSampler,
-	"nRandInt":              NRandInt,
-	"fake":                  Fake,
-	"seed":                  Seed,
-	"formatPrice":           FormatPrice,
+	for k, v := range addlFuncs {
+		funcs[k] = v
+	}
+
+	return funcs
 }
 
 func Render(templateString string) string {
+	return RenderWithContext(context.Background(), templateString)
+}
+
+// RenderWithContext renders a template, with random values from the stream in the context
+func RenderWithContext(ctx context.Context, templateString string) string {
 
 	if config.GetEnv().EPCC_CLI_DISABLE_TEMPLATE_EXECUTION {
 		return templateString
@@ -40,7 +67,7 @@ func Render(templateString string) string {
 		return templateString
 	}
 
-	tpl, err := template.New("templateName").Funcs(sprig.FuncMap()).Funcs(AddlFuncs).Parse(templateString)
+	tpl, err := template.New("templateName").Funcs(FuncMap(ctx)).Parse(templateString)
 
 	if err != nil {
 		log.Warnf("Could not process argument template: %s, due to %v", templateString, err)
@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/elasticpath/epcc-cli/external/random"
+	"github.com/stretchr/testify/require"
+)
+
+const randomTemplate = `{{ pseudoRandInt 0 1000000 }} {{ pseudoRandNorm 0 1 }} {{ nRandInt 3 0 100 }} {{ fake "Email" }} {{ uuidv4 }} {{ randAlphaNum 10 }} {{ randInt 0 1000000 }} {{ weightDatedTimeSample "2024-01-01" "2024-02-01" }}`
+
+func TestRenderWithContextReturnsTheSameValuesForTheSameStreamWithASeed(t *testing.T) {
+	// Fixture Setup
+	random.SetSeed(42)
+
+	firstCtx := random.WithStream(context.Background(), random.NewStream("runbook", "action", 0))
+	secondCtx := random.WithStream(context.Background(), random.NewStream("runbook", "action", 0))
+
+	// Execute SUT
+	first := RenderWithContext(firstCtx, randomTemplate)
+	second := RenderWithContext(secondCtx, randomTemplate)
+
+	// Verification
+	require.NotContains(t, first, "{{")
+	require.Equal(t, first, second)
+}
+
+func TestRenderWithContextReturnsDifferentValuesForDifferentStreamsWithASeed(t *testing.T) {
+	// Fixture Setup
+	random.SetSeed(42)
+
+	firstCtx := random.WithStream(context.Background(), random.NewStream("runbook", "action", 0))
+	secondCtx := random.WithStream(context.Background(), random.NewStream("runbook", "action", 1))
+
+	// Execute SUT
+	first := RenderWithContext(firstCtx, randomTemplate)
+	second := RenderWithContext(secondCtx, randomTemplate)
+
+	// Verification
+	require.NotEqual(t, first, second)
+}
+
+func TestRenderWithContextSeedFunctionRestartsTheStream(t *testing.T) {
+	// Fixture Setup
+	ctx := random.WithStream(context.Background(), random.NewStream())
+
+	// Execute SUT
+	result := RenderWithContext(ctx, `{{ seed 7 }}{{ pseudoRandInt 0 1000000 }} {{ seed 7 }}{{ pseudoRandInt 0 1000000 }}`)
+
+	// Verification
+	var first, second string
+	_, err := fmt.Sscanf(result, "%s %s", &first, &second)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+}
+
+func TestRenderWithContextFakeLocaleReturnsValuesForTheLocale(t *testing.T) {
+	// Fixture Setup
+	ctx := random.WithStream(context.Background(), random.NewStream())
+
+	// Execute SUT
+	result := RenderWithContext(ctx, `{{ fakeLocale "fr_FR" "CountryAbr" }} {{ fake "CurrencyShort:en_GB" }}`)
+
+	// Verification
+	require.Equal(t, "FR GBP", result)
+}